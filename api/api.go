@@ -1,7 +1,9 @@
 package api
 
 import (
+	"github.com/carsonmyers/bublar-assignment/api/graphql"
 	v1 "github.com/carsonmyers/bublar-assignment/api/v1"
+	v2 "github.com/carsonmyers/bublar-assignment/api/v2"
 	"github.com/carsonmyers/bublar-assignment/configure"
 	"github.com/gorilla/mux"
 )
@@ -15,6 +17,8 @@ func GetAPI() *mux.Router {
 
 		router = mux.NewRouter()
 		v1.Init(router.PathPrefix(conf.BasePath).Subrouter())
+		v2.Init(router.PathPrefix(v2.BasePath).Subrouter())
+		graphql.Init(router.PathPrefix(graphql.Path).Subrouter())
 	}
 
 	return router
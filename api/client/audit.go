@@ -0,0 +1,60 @@
+package client
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+)
+
+// AuditQuery - paging and filters for listing the audit log. Empty filters
+// match every entry.
+type AuditQuery struct {
+	ListQuery
+	Actor     string
+	Action    string
+	Target    string
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+}
+
+func (q *AuditQuery) values() url.Values {
+	if q == nil {
+		return url.Values{}
+	}
+
+	values := q.ListQuery.values()
+	if len(q.Actor) > 0 {
+		values.Set("actor", q.Actor)
+	}
+	if len(q.Action) > 0 {
+		values.Set("action", q.Action)
+	}
+	if len(q.Target) > 0 {
+		values.Set("target", q.Target)
+	}
+	if len(q.RequestID) > 0 {
+		values.Set("requestId", q.RequestID)
+	}
+	if !q.Since.IsZero() {
+		values.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		values.Set("until", q.Until.Format(time.RFC3339))
+	}
+
+	return values
+}
+
+// Audit - list one page of the audit log, newest first, along with the cursor
+// of the next page if there is one
+func (c *Client) Audit(query *AuditQuery) ([]*data.AuditEntry, string, error) {
+	entries := make([]*data.AuditEntry, 0)
+	r, err := c.do("GET", c.endpoint("/admin/audit", query.values()), nil, &entries, 0)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return entries, r.next, nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+)
+
+// BatchOperation - one request of a batch. The path is relative to the API's
+// base path, like `/admin/locations`, and may have a query string.
+type BatchOperation struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Body    interface{} `json:"body,omitempty"`
+	IfMatch uint        `json:"ifMatch,omitempty"`
+}
+
+// BatchResult - the outcome of one operation of a batch, with its response
+// data left encoded. Skipped operations have no status code or response.
+type BatchResult struct {
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	StatusCode int             `json:"statusCode,omitempty"`
+	Skipped    bool            `json:"skipped,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Problems   []*errors.Error `json:"problems,omitempty"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	Next       string          `json:"next,omitempty"`
+}
+
+// batchResult - a result as the API sends it, with the operation's response
+// envelope nested in it
+type batchResult struct {
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"statusCode"`
+	Skipped    bool      `json:"skipped"`
+	Response   *envelope `json:"response"`
+}
+
+// Batch - run several operations in order. The batch stops at the first
+// operation which fails unless continueOnError is set. The results are nil if
+// the batch was rejected before anything ran; the problems say why, or which
+// operations failed.
+func (c *Client) Batch(ops []*BatchOperation, continueOnError bool) ([]*BatchResult, []*errors.Error, error) {
+	query := url.Values{}
+	query.Set("continueOnError", strconv.FormatBool(continueOnError))
+
+	var results []*batchResult
+	r, err := c.send("POST", c.endpoint("/admin/batch", query), ops, &results, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if results == nil {
+		return nil, r.problems, nil
+	}
+
+	res := make([]*BatchResult, len(results))
+	for i, result := range results {
+		res[i] = &BatchResult{
+			Method:     result.Method,
+			Path:       result.Path,
+			StatusCode: result.StatusCode,
+			Skipped:    result.Skipped,
+		}
+
+		if env := result.Response; env != nil {
+			res[i].Status = env.Status
+			res[i].Problems = restoreProblems(env.Problems)
+			res[i].Data = env.Data
+			res[i].Next = env.Next
+		}
+	}
+
+	return res, r.problems, nil
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"go.uber.org/zap"
+)
+
+// Client - typed access to the v1 HTTP API. Problems reported by the API are
+// returned as *errors.Error values; any other error means the API couldn't be
+// reached or sent something which isn't an API response.
+type Client struct {
+	api *connect.APIClient
+}
+
+// New - create a client which sends its requests through an API connection
+func New(api *connect.APIClient) *Client {
+	return &Client{
+		api: api,
+	}
+}
+
+// ListQuery - paging parameters of a list request. Zero values are left to
+// the API's defaults.
+type ListQuery struct {
+	Limit  int
+	After  string
+	Sort   string
+	Prefix string
+}
+
+func (q *ListQuery) values() url.Values {
+	values := url.Values{}
+	if q == nil {
+		return values
+	}
+
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if len(q.After) > 0 {
+		values.Set("after", q.After)
+	}
+	if len(q.Sort) > 0 {
+		values.Set("sort", q.Sort)
+	}
+	if len(q.Prefix) > 0 {
+		values.Set("prefix", q.Prefix)
+	}
+
+	return values
+}
+
+// result - a decoded response envelope
+type result struct {
+	response *http.Response
+	status   string
+	problems []*errors.Error
+	next     string
+}
+
+// envelope - the Response structure of the API, with the data left encoded
+// until the type it should be decoded into is known
+type envelope struct {
+	Status   string          `json:"status"`
+	Problems []*problem      `json:"problems"`
+	Data     json.RawMessage `json:"data"`
+	Next     string          `json:"next"`
+}
+
+type problem struct {
+	Code string `json:"code"`
+	errors.Error
+}
+
+// endpoint - build the URL of an endpoint, with an optional query string
+func (c *Client) endpoint(path string, query url.Values) string {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	return c.api.URL(path)
+}
+
+// send - make a request and decode the response envelope, putting its data
+// into target if it has any. Problems are returned in the result, not as an
+// error.
+func (c *Client) send(method, endpoint string, body, target interface{}, version uint) (*result, error) {
+	req, log := c.api.NewRequest(method, endpoint, body)
+	res, data, err := req.IfMatch(version).Send()
+	if err != nil {
+		return nil, err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		log.Error("Failed to decode response", zap.Int("status", res.StatusCode), zap.Error(err))
+		return nil, fmt.Errorf("unexpected response from API: %s", res.Status)
+	}
+
+	r := &result{
+		response: res,
+		status:   env.Status,
+		problems: restoreProblems(env.Problems),
+		next:     env.Next,
+	}
+
+	if target != nil && len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, target); err != nil {
+			log.Error("Failed to decode response data", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// restoreProblems - convert the problems of a response to errors, with their
+// kinds restored from their codes
+func restoreProblems(problems []*problem) []*errors.Error {
+	res := make([]*errors.Error, len(problems))
+	for i, p := range problems {
+		problem := p.Error
+		if kind, ok := errors.KindFromCode(p.Code); ok {
+			problem.Kind = kind
+		}
+
+		res[i] = &problem
+	}
+
+	return res
+}
+
+// do - make a request which either succeeds or fails as a whole, returning
+// the first problem if it failed
+func (c *Client) do(method, endpoint string, body, target interface{}, version uint) (*result, error) {
+	r, err := c.send(method, endpoint, body, target, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.status == "error" {
+		if len(r.problems) > 0 {
+			return nil, r.problems[0]
+		}
+
+		return nil, errors.EUnknown.NewErrorf("request failed: %s", r.response.Status)
+	}
+
+	return r, nil
+}
+
+// Ping - check that the API is up
+func (c *Client) Ping() error {
+	var pong struct {
+		Say string `json:"say"`
+	}
+
+	_, err := c.do("POST", c.endpoint("/ping", nil), map[string]string{"say": "ping"}, &pong, 0)
+	if err != nil {
+		return err
+	}
+
+	if pong.Say != "pong" {
+		return fmt.Errorf("unexpected ping response \"%s\"", pong.Say)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+)
+
+func locationPath(name, action string) string {
+	return fmt.Sprintf("/admin/locations/%s%s", url.PathEscape(name), action)
+}
+
+// CreateLocation - create a location in the game world
+func (c *Client) CreateLocation(location *data.Location) (*data.Location, error) {
+	var created data.Location
+	if _, err := c.do("POST", c.endpoint("/admin/locations", nil), location, &created, 0); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetLocation - get a location by name
+func (c *Client) GetLocation(name string) (*data.Location, error) {
+	path := fmt.Sprintf("/client/locations/%s", url.PathEscape(name))
+
+	var location data.Location
+	if _, err := c.do("GET", c.endpoint(path, nil), nil, &location, 0); err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// ListLocations - list one page of locations, along with the cursor of the
+// next page if there is one
+func (c *Client) ListLocations(query *ListQuery) ([]*data.Location, string, error) {
+	locations := make([]*data.Location, 0)
+	r, err := c.do("GET", c.endpoint("/client/locations", query.values()), nil, &locations, 0)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return locations, r.next, nil
+}
+
+// UpdateLocation - move or rename a location. If version is not 0, the
+// location is only updated if it hasn't changed since that version.
+func (c *Client) UpdateLocation(name string, location *data.Location, version uint) (*data.Location, error) {
+	var updated data.Location
+	if _, err := c.do("PATCH", c.endpoint(locationPath(name, ""), nil), location, &updated, version); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteLocation - delete a location, moving its players to the fallback
+// location. If version is not 0, the location is only deleted if it hasn't
+// changed since that version.
+func (c *Client) DeleteLocation(name string, version uint) error {
+	_, err := c.do("DELETE", c.endpoint(locationPath(name, ""), nil), nil, nil, version)
+	return err
+}
+
+// RestoreLocation - bring back a deleted location
+func (c *Client) RestoreLocation(name string) (*data.Location, error) {
+	var location data.Location
+	if _, err := c.do("POST", c.endpoint(locationPath(name, "/restore"), nil), nil, &location, 0); err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// LocationPlayers - list the players in a location, with their positions
+func (c *Client) LocationPlayers(name string) ([]*data.Player, error) {
+	path := fmt.Sprintf("/client/locations/%s/players", url.PathEscape(name))
+
+	players := make([]*data.Player, 0)
+	if _, err := c.do("GET", c.endpoint(path, nil), nil, &players, 0); err != nil {
+		return nil, err
+	}
+
+	return players, nil
+}
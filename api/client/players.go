@@ -0,0 +1,162 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+)
+
+// PlayerQuery - paging and filters for listing players
+type PlayerQuery struct {
+	ListQuery
+	Location string
+	Online   bool
+}
+
+func (q *PlayerQuery) values() url.Values {
+	if q == nil {
+		return url.Values{}
+	}
+
+	values := q.ListQuery.values()
+	if len(q.Location) > 0 {
+		values.Set("location", q.Location)
+	}
+	if q.Online {
+		values.Set("online", strconv.FormatBool(q.Online))
+	}
+
+	return values
+}
+
+// playerPath - the endpoint for a player, which is the logged in player's own
+// endpoint if no username is given, or else the admin endpoint
+func playerPath(username, action string) string {
+	if len(username) == 0 {
+		return "/client/player" + action
+	}
+
+	return fmt.Sprintf("/admin/players/%s%s", url.PathEscape(username), action)
+}
+
+// Login - log in as a player, sending the returned auth token with every
+// following request
+func (c *Client) Login(username, password string) (string, error) {
+	r, err := c.do("POST", c.endpoint("/client/login", nil), &data.Player{
+		Username: username,
+		Password: &password,
+	}, nil, 0)
+	if err != nil {
+		return "", err
+	}
+
+	for _, cookie := range r.response.Cookies() {
+		if cookie.Name == "AUTH" {
+			c.api.SetSession(cookie.Value)
+			return cookie.Value, nil
+		}
+	}
+
+	return "", errors.EAuth.NewError("API did not return an auth token")
+}
+
+// CreatePlayer - create a player account
+func (c *Client) CreatePlayer(username, password string) (*data.Player, error) {
+	var player data.Player
+	_, err := c.do("POST", c.endpoint("/client/players", nil), &data.Player{
+		Username: username,
+		Password: &password,
+	}, &player, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &player, nil
+}
+
+// GetPlayer - get a player and their position, or the logged in player if no
+// username is given
+func (c *Client) GetPlayer(username string) (*data.Player, error) {
+	path := "/client/player"
+	if len(username) > 0 {
+		path = fmt.Sprintf("/client/players/%s", url.PathEscape(username))
+	}
+
+	var player data.Player
+	if _, err := c.do("GET", c.endpoint(path, nil), nil, &player, 0); err != nil {
+		return nil, err
+	}
+
+	return &player, nil
+}
+
+// ListPlayers - list one page of players, along with the cursor of the next
+// page if there is one
+func (c *Client) ListPlayers(query *PlayerQuery) ([]*data.Player, string, error) {
+	players := make([]*data.Player, 0)
+	r, err := c.do("GET", c.endpoint("/client/players", query.values()), nil, &players, 0)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return players, r.next, nil
+}
+
+// UpdatePlayer - change a player's details, or the logged in player's if no
+// username is given. If version is not 0, the player is only updated if it
+// hasn't changed since that version.
+func (c *Client) UpdatePlayer(username string, player *data.Player, version uint) (*data.Player, error) {
+	var updated data.Player
+	if _, err := c.do("PATCH", c.endpoint(playerPath(username, ""), nil), player, &updated, version); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeletePlayer - delete a player, or the logged in player if no username is
+// given. If version is not 0, the player is only deleted if it hasn't changed
+// since that version.
+func (c *Client) DeletePlayer(username string, version uint) error {
+	_, err := c.do("DELETE", c.endpoint(playerPath(username, ""), nil), nil, nil, version)
+	return err
+}
+
+// RestorePlayer - bring back a deleted player
+func (c *Client) RestorePlayer(username string) (*data.Player, error) {
+	var player data.Player
+	if _, err := c.do("POST", c.endpoint(playerPath(username, "/restore"), nil), nil, &player, 0); err != nil {
+		return nil, err
+	}
+
+	return &player, nil
+}
+
+// Travel - send a player to a location, or the logged in player if no
+// username is given
+func (c *Client) Travel(username, location string) (*data.Player, error) {
+	body := map[string]string{"location": location}
+
+	var player data.Player
+	if _, err := c.do("POST", c.endpoint(playerPath(username, "/travel"), nil), body, &player, 0); err != nil {
+		return nil, err
+	}
+
+	return &player, nil
+}
+
+// Move - move a player within their location, or the logged in player if no
+// username is given
+func (c *Client) Move(username string, x, y int) (*data.Player, error) {
+	body := map[string]int{"x": x, "y": y}
+
+	var player data.Player
+	if _, err := c.do("POST", c.endpoint(playerPath(username, "/move"), nil), body, &player, 0); err != nil {
+		return nil, err
+	}
+
+	return &player, nil
+}
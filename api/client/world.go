@@ -0,0 +1,68 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/world"
+)
+
+// ExportWorld - get every location in the game world, and optionally every
+// player and their position
+func (c *Client) ExportWorld(players bool) (*world.Document, error) {
+	query := url.Values{}
+	query.Set("players", strconv.FormatBool(players))
+
+	var doc world.Document
+	if _, err := c.do("GET", c.endpoint("/admin/world", query), nil, &doc, 0); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// ImportWorld - create or update the locations and players in a world
+// document. The report lists the changes made (or that would be made, for a
+// dry run) and is nil if nothing was written; the problems say why.
+func (c *Client) ImportWorld(doc *world.Document, opts *world.Options) (*world.Report, []*errors.Error, error) {
+	query := url.Values{}
+	if opts != nil {
+		query.Set("upsert", strconv.FormatBool(opts.Upsert))
+		query.Set("dryRun", strconv.FormatBool(opts.DryRun))
+	}
+
+	var report *world.Report
+	r, err := c.send("POST", c.endpoint("/admin/world", query), doc, &report, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return report, r.problems, nil
+}
+
+// Snapshot - take a snapshot of the whole game world
+func (c *Client) Snapshot() (*world.Snapshot, error) {
+	var snapshot world.Snapshot
+	if _, err := c.do("GET", c.endpoint("/admin/snapshot", nil), nil, &snapshot, 0); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// RestoreSnapshot - restore a snapshot into the game world, merging it with
+// what is there or, if clear is set, replacing it. The report is nil if
+// nothing was restored; the problems say why.
+func (c *Client) RestoreSnapshot(snapshot *world.Snapshot, clear bool) (*world.SnapshotReport, []*errors.Error, error) {
+	query := url.Values{}
+	query.Set("clear", strconv.FormatBool(clear))
+
+	var report *world.SnapshotReport
+	r, err := c.send("POST", c.endpoint("/admin/snapshot", query), snapshot, &report, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return report, r.problems, nil
+}
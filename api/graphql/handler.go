@@ -0,0 +1,213 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	v1 "github.com/carsonmyers/bublar-assignment/api/v1"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/gorilla/mux"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+// Path - where the GraphQL endpoint is served
+const Path = "/graphql"
+
+type authKey struct{}
+
+// params - a GraphQL request, from a JSON body or the query string
+type params struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Init - initialize a router with the GraphQL endpoint. Requests are sent as
+// a JSON body with POST or in the query string with GET. Subscriptions are
+// streamed as server-sent events to requests which accept them.
+func Init(r *mux.Router) {
+	r.Use(v1.RIDMiddleware())
+	r.Use(v1.LoggingMiddleware)
+	r.Use(v1.AuthMiddleware)
+	r.Use(v1.AuditMiddleware("graphql"))
+
+	s := graphql.MustParseSchema(schema, &Resolver{})
+	h := &handler{schema: s}
+
+	r.Handle("", h).Methods("GET", "POST")
+	r.Handle("/", h).Methods("GET", "POST")
+
+	log.Info("Initialized GraphQL endpoint")
+}
+
+type handler struct {
+	schema *graphql.Schema
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, e := decodeParams(r)
+	if e != nil {
+		writeErrors(w, http.StatusBadRequest, e)
+		return
+	}
+
+	ctx := withLoaders(r.Context())
+	ctx = context.WithValue(ctx, authKey{}, v1.GetAuth(r))
+
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		h.subscribe(ctx, w, r, req)
+		return
+	}
+
+	res := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	writeJSON(w, http.StatusOK, res)
+}
+
+// subscribe - stream the responses of an operation as server-sent events. A
+// query or mutation sends one response, a subscription one per event.
+func (h *handler) subscribe(ctx context.Context, w http.ResponseWriter, r *http.Request, req *params) {
+	reqLog := v1.GetLogger(r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrors(w, http.StatusInternalServerError, errors.EInternal.NewError("streaming is not supported"))
+		return
+	}
+
+	responses, err := h.schema.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, errors.EInvalidRequest.NewError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for res := range responses {
+		payload, err := json.Marshal(res)
+		if err != nil {
+			reqLog.Error("Error encoding GraphQL response", zap.Error(err))
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "event: next\ndata: %s\n\n", payload); err != nil {
+			reqLog.Debug("GraphQL stream closed", zap.Error(err))
+			return
+		}
+
+		flusher.Flush()
+	}
+
+	fmt.Fprint(w, "event: complete\ndata:\n\n")
+	flusher.Flush()
+}
+
+// decodeParams - read a request from the query string of a GET or the JSON
+// body of a POST
+func decodeParams(r *http.Request) (*params, *errors.Error) {
+	var req params
+
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+
+		if vars := query.Get("variables"); len(vars) > 0 {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				return nil, errors.EInvalidRequest.NewErrorf("malformed variables: %s", err).WithContext("variables")
+			}
+		}
+	} else {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, errors.EInternal.NewError("Error reading request")
+		}
+
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("malformed JSON: %s", err)
+		}
+	}
+
+	if len(req.Query) == 0 {
+		return nil, errors.EInvalidRequest.NewError("a query is required").WithContext("query")
+	}
+
+	return &req, nil
+}
+
+func writeErrors(w http.ResponseWriter, status int, e *errors.Error) {
+	writeJSON(w, status, &graphql.Response{
+		Errors: []*gqlerrors.QueryError{{
+			Message:    e.Error(),
+			Extensions: extensions(e),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		log.Error("Error encoding GraphQL response", zap.Error(err))
+		status = http.StatusInternalServerError
+		payload = []byte(`{"errors":[{"message":"failed to encode response"}]}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(payload)
+}
+
+// problemError - an error returned by a resolver, carrying the code, context
+// and details of the problem as the extensions of the GraphQL error
+type problemError struct {
+	err *errors.Error
+}
+
+// problem - wrap an error for a resolver to return
+func problem(err error) error {
+	switch e := err.(type) {
+	case *problemError:
+		return e
+	case *errors.Error:
+		return &problemError{err: e}
+	default:
+		return &problemError{err: errors.ERPC.NewError(err)}
+	}
+}
+
+func (p *problemError) Error() string {
+	return p.err.Error()
+}
+
+// Extensions - the extensions of the GraphQL error
+func (p *problemError) Extensions() map[string]interface{} {
+	return extensions(p.err)
+}
+
+func extensions(e *errors.Error) map[string]interface{} {
+	ext := map[string]interface{}{
+		"code": e.Kind.Code(),
+		"kind": e.Kind,
+	}
+
+	if len(e.Ctx) > 0 {
+		ext["ctx"] = e.Ctx
+	}
+
+	if len(e.Details) > 0 {
+		ext["details"] = e.Details
+	}
+
+	return ext
+}
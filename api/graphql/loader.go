@@ -0,0 +1,265 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/proto"
+)
+
+// batchWait - how long a loader collects keys before fetching them. Fields of
+// every item in a list are resolved concurrently, so this is enough for all of
+// them to ask for what they need in one batch.
+const batchWait = 2 * time.Millisecond
+
+// batchSize - the most keys fetched at once
+const batchSize = 100
+
+// fetchFunc - fetch a batch of keys, returning the value of each key found
+type fetchFunc func(keys []string) (map[string]interface{}, error)
+
+// result - the outcome of loading one key, ready once done is closed
+type result struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// loader - collect the keys requested by resolvers into batches, so a list of
+// N items makes one RPC instead of N. Results are cached for the request.
+type loader struct {
+	fetch fetchFunc
+
+	mu      sync.Mutex
+	cache   map[string]*result
+	pending []string
+}
+
+func newLoader(fetch fetchFunc) *loader {
+	return &loader{
+		fetch: fetch,
+		cache: make(map[string]*result),
+	}
+}
+
+// load - get the value of a key, or nil if it wasn't found
+func (l *loader) load(ctx context.Context, key string) (interface{}, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result{done: make(chan struct{})}
+		l.cache[key] = res
+		l.pending = append(l.pending, key)
+
+		if len(l.pending) == 1 {
+			time.AfterFunc(batchWait, l.dispatch)
+		} else if len(l.pending) >= batchSize {
+			go l.dispatch()
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch - fetch the keys collected so far
+func (l *loader) dispatch() {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.fetch(keys)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		res := l.cache[key]
+		res.value, res.err = values[key], err
+		close(res.done)
+
+		// failures aren't cached, so a later field can try again
+		if err != nil {
+			delete(l.cache, key)
+		}
+	}
+}
+
+// loaders - the loaders of a single request
+type loaders struct {
+	players   *loader
+	locations *loader
+	occupants *loader
+
+	allOnce      sync.Once
+	allLocations []*proto.Location
+	allErr       error
+}
+
+type loadersKey struct{}
+
+func newLoaders() *loaders {
+	return &loaders{
+		players:   newLoader(fetchPlayers),
+		locations: newLoader(fetchLocations),
+		occupants: newLoader(fetchOccupants),
+	}
+}
+
+// withLoaders - give a request its own loaders
+func withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders())
+}
+
+// getLoaders - get the loaders of a request
+func getLoaders(ctx context.Context) *loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return l
+	}
+
+	// resolvers are only called through the handler, but don't cache anything
+	// across requests if one isn't
+	return newLoaders()
+}
+
+// player - load a player by username, or nil if they don't exist
+func (l *loaders) player(ctx context.Context, username string) (*proto.Player, error) {
+	value, err := l.players.load(ctx, username)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	return value.(*proto.Player), nil
+}
+
+// location - load a location by name, or nil if it doesn't exist
+func (l *loaders) location(ctx context.Context, name string) (*proto.Location, error) {
+	value, err := l.locations.load(ctx, name)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	return value.(*proto.Location), nil
+}
+
+// occupantsOf - load the players in a location
+func (l *loaders) occupantsOf(ctx context.Context, name string) ([]*proto.Player, error) {
+	value, err := l.occupants.load(ctx, name)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	return value.([]*proto.Player), nil
+}
+
+// everyLocation - list every location in the game, once per request
+func (l *loaders) everyLocation() ([]*proto.Location, error) {
+	l.allOnce.Do(func() {
+		locationSvc, err := connect.Locations()
+		if err != nil {
+			l.allErr = errors.ERPCConnection.NewError(err)
+			return
+		}
+
+		after := ""
+		for {
+			page, next, err := locationSvc.List(&proto.LocationListRequest{
+				Limit: paging.MaxLimit,
+				After: after,
+			})
+			if err != nil {
+				l.allErr = err
+				return
+			}
+
+			l.allLocations = append(l.allLocations, page...)
+			if len(next) == 0 {
+				return
+			}
+
+			after = next
+		}
+	})
+
+	return l.allLocations, l.allErr
+}
+
+func fetchPlayers(usernames []string) (map[string]interface{}, error) {
+	playerSvc, err := connect.Players()
+	if err != nil {
+		return nil, errors.ERPCConnection.NewError(err)
+	}
+
+	players, err := playerSvc.GetMany(usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]interface{}, len(players))
+	for _, player := range players {
+		res[player.GetUsername()] = player
+	}
+
+	return res, nil
+}
+
+func fetchLocations(names []string) (map[string]interface{}, error) {
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		return nil, errors.ERPCConnection.NewError(err)
+	}
+
+	locations, err := locationSvc.GetMany(names)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]interface{}, len(locations))
+	for _, location := range locations {
+		res[location.GetName()] = location
+	}
+
+	return res, nil
+}
+
+func fetchOccupants(names []string) (map[string]interface{}, error) {
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		return nil, errors.ERPCConnection.NewError(err)
+	}
+
+	players, err := locationSvc.Occupants(names)
+	if err != nil {
+		return nil, err
+	}
+
+	byLocation := make(map[string][]*proto.Player, len(names))
+	for _, player := range players {
+		byLocation[player.GetLocation()] = append(byLocation[player.GetLocation()], player)
+	}
+
+	res := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		if members, ok := byLocation[name]; ok {
+			res[name] = members
+		} else {
+			res[name] = make([]*proto.Player, 0)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,609 @@
+package graphql
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"github.com/carsonmyers/bublar-assignment/ratelimit"
+	"github.com/carsonmyers/bublar-assignment/validate"
+	"github.com/gbrlsnchs/jwt/v2"
+	"go.uber.org/zap"
+)
+
+// Resolver - the root of the schema
+type Resolver struct{}
+
+type playerArgs struct {
+	Username string
+}
+
+type playersArgs struct {
+	First    *int32
+	After    *string
+	Sort     *string
+	Prefix   *string
+	Location *string
+	Online   *bool
+}
+
+type locationArgs struct {
+	Name string
+}
+
+type locationsArgs struct {
+	First  *int32
+	After  *string
+	Sort   *string
+	Prefix *string
+}
+
+type occupantsArgs struct {
+	Locations []string
+}
+
+type routesArgs struct {
+	From  string
+	First *int32
+}
+
+type travelArgs struct {
+	Location string
+	Username *string
+}
+
+type moveArgs struct {
+	X        int32
+	Y        int32
+	Username *string
+}
+
+type eventsArgs struct {
+	Location *string
+}
+
+// Player - get a player by username
+func (r *Resolver) Player(ctx context.Context, args playerArgs) (*playerResolver, error) {
+	l := getLoaders(ctx)
+	player, err := l.player(ctx, args.Username)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	if player == nil {
+		return nil, nil
+	}
+
+	return &playerResolver{l: l, username: player.GetUsername(), player: player}, nil
+}
+
+// Players - list one page of players
+func (r *Resolver) Players(ctx context.Context, args playersArgs) (*playerConnection, error) {
+	playerSvc, err := connect.Players()
+	if err != nil {
+		return nil, problem(errors.ERPCConnection.NewError(err))
+	}
+
+	players, next, err := playerSvc.List(&proto.PlayerListRequest{
+		Limit:    int32Value(args.First),
+		After:    stringValue(args.After),
+		Sort:     stringValue(args.Sort),
+		Prefix:   stringValue(args.Prefix),
+		Location: stringValue(args.Location),
+		Online:   args.Online != nil && *args.Online,
+	})
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	l := getLoaders(ctx)
+	nodes := make([]*playerResolver, len(players))
+	for i, player := range players {
+		nodes[i] = &playerResolver{l: l, username: player.GetUsername(), player: player}
+	}
+
+	return &playerConnection{nodes: nodes, next: optional(next)}, nil
+}
+
+// Location - get a location by name
+func (r *Resolver) Location(ctx context.Context, args locationArgs) (*locationResolver, error) {
+	l := getLoaders(ctx)
+	location, err := l.location(ctx, args.Name)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	if location == nil {
+		return nil, nil
+	}
+
+	return &locationResolver{l: l, location: location}, nil
+}
+
+// Locations - list one page of locations
+func (r *Resolver) Locations(ctx context.Context, args locationsArgs) (*locationConnection, error) {
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		return nil, problem(errors.ERPCConnection.NewError(err))
+	}
+
+	locations, next, err := locationSvc.List(&proto.LocationListRequest{
+		Limit:  int32Value(args.First),
+		After:  stringValue(args.After),
+		Sort:   stringValue(args.Sort),
+		Prefix: stringValue(args.Prefix),
+	})
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	l := getLoaders(ctx)
+	nodes := make([]*locationResolver, len(locations))
+	for i, location := range locations {
+		nodes[i] = &locationResolver{l: l, location: location}
+	}
+
+	return &locationConnection{nodes: nodes, next: optional(next)}, nil
+}
+
+// Occupants - list the players in several locations
+func (r *Resolver) Occupants(ctx context.Context, args occupantsArgs) ([]*occupantsResolver, error) {
+	l := getLoaders(ctx)
+	res := make([]*occupantsResolver, len(args.Locations))
+	for i, name := range args.Locations {
+		res[i] = &occupantsResolver{l: l, location: name}
+	}
+
+	return res, nil
+}
+
+// Routes - list the routes from a location to every other location
+func (r *Resolver) Routes(ctx context.Context, args routesArgs) ([]*routeResolver, error) {
+	l := getLoaders(ctx)
+	from, err := l.location(ctx, args.From)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	if from == nil {
+		return nil, problem(errors.EUnknownLocation.NewErrorf("unknown location `%s`", args.From).WithContext("from").WithDetail("location", args.From))
+	}
+
+	return routes(l, from, args.First)
+}
+
+// Travel - send a player to a location
+func (r *Resolver) Travel(ctx context.Context, args travelArgs) (*playerResolver, error) {
+	if err := validate.New().Check("location", args.Location, validate.Required).Err(); err != nil {
+		return nil, problem(err)
+	}
+
+	username, err := gameplayActor(ctx, args.Username)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		return nil, problem(errors.ERPCConnection.NewError(err))
+	}
+
+	res, err := playerSvc.With(ctx).Travel(username, args.Location)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	player := res.GetPlayer()
+	player.X, player.Y = res.GetPosition().GetX(), res.GetPosition().GetY()
+	return &playerResolver{l: getLoaders(ctx), username: username, player: player}, nil
+}
+
+// Move - move a player within their location
+func (r *Resolver) Move(ctx context.Context, args moveArgs) (*playerResolver, error) {
+	username, err := gameplayActor(ctx, args.Username)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		return nil, problem(errors.ERPCConnection.NewError(err))
+	}
+
+	// players moving themselves are held to the speed limit
+	self := args.Username == nil || len(*args.Username) == 0
+	if _, err := playerSvc.With(ctx).Move(username, args.X, args.Y, self); err != nil {
+		return nil, problem(err)
+	}
+
+	// the version isn't part of the move response, so the player is loaded
+	// if it is asked for
+	return &playerResolver{l: getLoaders(ctx), username: username}, nil
+}
+
+// LocationEvents - stream changes to locations until the subscriber goes away
+func (r *Resolver) LocationEvents(ctx context.Context, args eventsArgs) (<-chan *eventResolver, error) {
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		return nil, problem(errors.ERPCConnection.NewError(err))
+	}
+
+	stream, err := locationSvc.Events(ctx)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	name := stringValue(args.Location)
+	res := make(chan *eventResolver)
+	go func() {
+		defer close(res)
+
+		for event := range stream {
+			if len(name) > 0 && event.GetLocation() != name && event.GetPrevious() != name {
+				continue
+			}
+
+			// each event gets its own loaders, so it sees the location as it
+			// is after the change rather than as it was at the first event
+			select {
+			case res <- &eventResolver{l: newLoaders(), event: event}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return res, nil
+}
+
+// actor - the player a mutation acts on. Naming a player is an admin action;
+// otherwise it is the logged in player.
+func actor(ctx context.Context, username *string) (string, error) {
+	if username != nil && len(*username) > 0 {
+		if !configure.GetAPI().EnableAdmin {
+			return "", errors.EForbidden.NewError("acting on other players requires the admin API").WithContext("username")
+		}
+
+		return *username, nil
+	}
+
+	token, _ := ctx.Value(authKey{}).(*jwt.JWT)
+	if token == nil || len(token.Audience) == 0 {
+		return "", errors.EAuth.NewError("not logged in")
+	}
+
+	return token.Audience, nil
+}
+
+// gameplayActor - the player a gameplay mutation acts on, like actor. Players
+// acting for themselves are held to the gameplay rate limit.
+func gameplayActor(ctx context.Context, username *string) (string, error) {
+	player, err := actor(ctx, username)
+	if err != nil || (username != nil && len(*username) > 0) {
+		return player, err
+	}
+
+	retryAfter, err := ratelimit.Gameplay(player)
+	if err != nil {
+		log.Error("Failed to check rate limit", zap.Error(err))
+	} else if retryAfter > 0 {
+		return "", errors.ERateLimited.NewError("too many requests, slow down").WithDetail("retryAfter", int(math.Ceil(retryAfter.Seconds())))
+	}
+
+	return player, nil
+}
+
+// routes - the routes from a location to every other location, nearest first
+func routes(l *loaders, from *proto.Location, first *int32) ([]*routeResolver, error) {
+	locations, err := l.everyLocation()
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	res := make([]*routeResolver, 0, len(locations))
+	for _, to := range locations {
+		if to.GetName() == from.GetName() {
+			continue
+		}
+
+		res = append(res, &routeResolver{
+			l:        l,
+			from:     from,
+			to:       to,
+			distance: math.Hypot(float64(to.GetX()-from.GetX()), float64(to.GetY()-from.GetY())),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].distance != res[j].distance {
+			return res[i].distance < res[j].distance
+		}
+
+		return res[i].to.GetName() < res[j].to.GetName()
+	})
+
+	if first != nil && *first >= 0 && int(*first) < len(res) {
+		res = res[:*first]
+	}
+
+	return res, nil
+}
+
+// playerResolver - a player, loaded when a field needs more than the username
+// if it wasn't known already. The loader caches it, so its fields can each
+// ask for it.
+type playerResolver struct {
+	l        *loaders
+	username string
+	player   *proto.Player
+}
+
+func (r *playerResolver) get(ctx context.Context) (*proto.Player, error) {
+	if r.player != nil {
+		return r.player, nil
+	}
+
+	player, err := r.l.player(ctx, r.username)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	if player == nil {
+		return nil, problem(errors.ENotFound.NewErrorf("player `%s` does not exist", r.username).WithDetail("username", r.username))
+	}
+
+	return player, nil
+}
+
+func (r *playerResolver) Username() string {
+	return r.username
+}
+
+func (r *playerResolver) Version(ctx context.Context) (int32, error) {
+	player, err := r.get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(player.GetVersion()), nil
+}
+
+func (r *playerResolver) Position(ctx context.Context) (*positionResolver, error) {
+	player, err := r.get(ctx)
+	if err != nil || len(player.GetLocation()) == 0 {
+		return nil, err
+	}
+
+	return &positionResolver{player: player}, nil
+}
+
+func (r *playerResolver) Location(ctx context.Context) (*locationResolver, error) {
+	player, err := r.get(ctx)
+	if err != nil || len(player.GetLocation()) == 0 {
+		return nil, err
+	}
+
+	location, err := r.l.location(ctx, player.GetLocation())
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	if location == nil {
+		return nil, nil
+	}
+
+	return &locationResolver{l: r.l, location: location}, nil
+}
+
+type positionResolver struct {
+	player *proto.Player
+}
+
+func (r *positionResolver) Location() string {
+	return r.player.GetLocation()
+}
+
+func (r *positionResolver) X() int32 {
+	return r.player.GetX()
+}
+
+func (r *positionResolver) Y() int32 {
+	return r.player.GetY()
+}
+
+type tileResolver struct {
+	tile *proto.Tile
+}
+
+func (r *tileResolver) X() int32 {
+	return r.tile.GetX()
+}
+
+func (r *tileResolver) Y() int32 {
+	return r.tile.GetY()
+}
+
+type locationResolver struct {
+	l        *loaders
+	location *proto.Location
+}
+
+func (r *locationResolver) Name() string {
+	return r.location.GetName()
+}
+
+func (r *locationResolver) X() int32 {
+	return r.location.GetX()
+}
+
+func (r *locationResolver) Y() int32 {
+	return r.location.GetY()
+}
+
+func (r *locationResolver) Width() int32 {
+	return r.location.GetWidth()
+}
+
+func (r *locationResolver) Height() int32 {
+	return r.location.GetHeight()
+}
+
+func (r *locationResolver) Blocked() []*tileResolver {
+	res := make([]*tileResolver, len(r.location.GetBlocked()))
+	for i, tile := range r.location.GetBlocked() {
+		res[i] = &tileResolver{tile}
+	}
+
+	return res
+}
+
+func (r *locationResolver) Version() int32 {
+	return int32(r.location.GetVersion())
+}
+
+func (r *locationResolver) Occupants(ctx context.Context) ([]*playerResolver, error) {
+	return occupants(ctx, r.l, r.location.GetName())
+}
+
+func (r *locationResolver) Routes(args struct{ First *int32 }) ([]*routeResolver, error) {
+	return routes(r.l, r.location, args.First)
+}
+
+// occupants - the players in a location. Their versions aren't known from the
+// location's members, so those are loaded together if they are asked for.
+func occupants(ctx context.Context, l *loaders, name string) ([]*playerResolver, error) {
+	members, err := l.occupantsOf(ctx, name)
+	if err != nil {
+		return nil, problem(err)
+	}
+
+	res := make([]*playerResolver, len(members))
+	for i, member := range members {
+		res[i] = &playerResolver{l: l, username: member.GetUsername()}
+	}
+
+	return res, nil
+}
+
+type occupantsResolver struct {
+	l        *loaders
+	location string
+}
+
+func (r *occupantsResolver) Location() string {
+	return r.location
+}
+
+func (r *occupantsResolver) Players(ctx context.Context) ([]*playerResolver, error) {
+	return occupants(ctx, r.l, r.location)
+}
+
+type routeResolver struct {
+	l        *loaders
+	from     *proto.Location
+	to       *proto.Location
+	distance float64
+}
+
+func (r *routeResolver) From() *locationResolver {
+	return &locationResolver{l: r.l, location: r.from}
+}
+
+func (r *routeResolver) To() *locationResolver {
+	return &locationResolver{l: r.l, location: r.to}
+}
+
+func (r *routeResolver) Distance() float64 {
+	return r.distance
+}
+
+type playerConnection struct {
+	nodes []*playerResolver
+	next  *string
+}
+
+func (r *playerConnection) Nodes() []*playerResolver {
+	return r.nodes
+}
+
+func (r *playerConnection) Next() *string {
+	return r.next
+}
+
+type locationConnection struct {
+	nodes []*locationResolver
+	next  *string
+}
+
+func (r *locationConnection) Nodes() []*locationResolver {
+	return r.nodes
+}
+
+func (r *locationConnection) Next() *string {
+	return r.next
+}
+
+type eventResolver struct {
+	l     *loaders
+	event *proto.Event
+}
+
+func (r *eventResolver) Kind() string {
+	return r.event.GetKind()
+}
+
+func (r *eventResolver) Name() string {
+	return r.event.GetLocation()
+}
+
+func (r *eventResolver) Previous() *string {
+	return optional(r.event.GetPrevious())
+}
+
+func (r *eventResolver) Time() string {
+	return time.Unix(r.event.GetTime(), 0).UTC().Format(time.RFC3339)
+}
+
+func (r *eventResolver) Location(ctx context.Context) (*locationResolver, error) {
+	location, err := r.l.location(ctx, r.event.GetLocation())
+	if err != nil {
+		log.Debug("Failed to load location of event", zap.String("location", r.event.GetLocation()), zap.Error(err))
+		return nil, problem(err)
+	}
+
+	if location == nil {
+		return nil, nil
+	}
+
+	return &locationResolver{l: r.l, location: location}, nil
+}
+
+func int32Value(v *int32) int32 {
+	if v == nil {
+		return 0
+	}
+
+	return *v
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+
+	return *v
+}
+
+// optional - a nullable string, which is null when empty
+func optional(v string) *string {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return &v
+}
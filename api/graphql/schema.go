@@ -0,0 +1,109 @@
+package graphql
+
+// schema - the GraphQL schema of the game world
+const schema = `
+schema {
+	query: Query
+	mutation: Mutation
+	subscription: Subscription
+}
+
+type Query {
+	# a player by username, or null if they don't exist
+	player(username: String!): Player
+	# one page of players, optionally only those in a location or online
+	players(first: Int, after: String, sort: String, prefix: String, location: String, online: Boolean): PlayerConnection!
+	# a location by name, or null if it doesn't exist
+	location(name: String!): Location
+	# one page of locations
+	locations(first: Int, after: String, sort: String, prefix: String): LocationConnection!
+	# the players in each of several locations
+	occupants(locations: [String!]!): [Occupants!]!
+	# the routes from a location to every other location, nearest first
+	routes(from: String!, first: Int): [Route!]!
+}
+
+type Mutation {
+	# send a player to a location. The username is only accepted with the admin
+	# API enabled; otherwise the logged in player travels.
+	travel(location: String!, username: String): Player!
+	# move a player within their location, like travel
+	move(x: Int!, y: Int!, username: String): Player!
+}
+
+type Subscription {
+	# changes to locations, optionally only those to one location
+	locationEvents(location: String): LocationEvent!
+}
+
+type Player {
+	username: String!
+	version: Int!
+	position: Position
+	# the location the player is in, or null if they aren't in one
+	location: Location
+}
+
+type Position {
+	location: String!
+	x: Int!
+	y: Int!
+}
+
+type Location {
+	name: String!
+	x: Int!
+	y: Int!
+	# size in tiles, which players can't move outside of; 0 is unbounded
+	width: Int!
+	height: Int!
+	# tiles which players can't move onto
+	blocked: [Tile!]!
+	version: Int!
+	# the players in the location
+	occupants: [Player!]!
+	# the routes to every other location, nearest first
+	routes(first: Int): [Route!]!
+}
+
+type PlayerConnection {
+	nodes: [Player!]!
+	# the cursor of the next page, or null on the last page
+	next: String
+}
+
+type Tile {
+	x: Int!
+	y: Int!
+}
+
+type LocationConnection {
+	nodes: [Location!]!
+	# the cursor of the next page, or null on the last page
+	next: String
+}
+
+type Occupants {
+	location: String!
+	players: [Player!]!
+}
+
+type Route {
+	from: Location!
+	to: Location!
+	# the straight-line distance between the locations
+	distance: Float!
+}
+
+type LocationEvent {
+	# location.updated, location.renamed, location.deleted or location.restored
+	kind: String!
+	name: String!
+	# the location's name before it was renamed
+	previous: String
+	# RFC 3339 time of the change
+	time: String!
+	# the location as it is now, or null if it was deleted
+	location: Location
+}
+`
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/audit"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"go.uber.org/zap"
+)
+
+// AuditMiddleware - middleware which attaches the caller of a request to its
+// context, so the services can record who made the changes it causes. It
+// must come after the request ID and auth middleware.
+func AuditMiddleware(source string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caller := &audit.Caller{
+				Source:  source,
+				Address: clientIP(r),
+			}
+
+			if token := GetAuth(r); token != nil {
+				caller.Actor = token.Audience
+			}
+
+			if rID, ok := r.Context().Value(ctxRID).(string); ok {
+				caller.RequestID = rID
+			}
+
+			ctx := audit.WithCaller(r.Context(), caller)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func getAuditHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := DecodeListParams(w, r)
+	if err != nil {
+		return
+	}
+
+	since, err := DecodeTimeParam(w, r, "since")
+	if err != nil {
+		return
+	}
+
+	until, err := DecodeTimeParam(w, r, "until")
+	if err != nil {
+		return
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		FromError(errors.ERPCConnection.NewError(err)).Write(w)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &proto.AuditQuery{
+		Actor:     query.Get("actor"),
+		Action:    query.Get("action"),
+		Target:    query.Get("target"),
+		RequestId: query.Get("requestId"),
+		Limit:     params.Limit,
+		After:     params.After,
+	}
+
+	if !since.IsZero() {
+		req.Since = since.Unix()
+	}
+
+	if !until.IsZero() {
+		req.Until = until.Unix()
+	}
+
+	entries, next, err := playerSvc.Audit(req)
+	if err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
+	GetLogger(r).Debug("Fetched audit entries from service", zap.Int("entries", len(entries)))
+
+	res := make([]*data.AuditEntry, len(entries))
+	for i, e := range entries {
+		res[i] = &data.AuditEntry{
+			ID:        uint(e.GetId()),
+			Time:      time.Unix(e.GetTime(), 0).UTC(),
+			Actor:     e.GetActor(),
+			Source:    e.GetSource(),
+			Address:   e.GetAddress(),
+			Action:    e.GetAction(),
+			Target:    e.GetTarget(),
+			RequestID: e.GetRequestId(),
+		}
+
+		if len(e.GetBefore()) > 0 {
+			res[i].Before = json.RawMessage(e.GetBefore())
+		}
+
+		if len(e.GetAfter()) > 0 {
+			res[i].After = json.RawMessage(e.GetAfter())
+		}
+	}
+
+	FromData(res).SetNext(next).Write(w)
+}
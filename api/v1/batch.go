@@ -0,0 +1,295 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/errors/rpcstatus"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// maxBatchOperations - the most operations one batch may contain
+const maxBatchOperations = 1000
+
+// batchMethods - the methods a batched operation may use
+var batchMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// batchExcluded - routes which can't be batched: batches can't be nested,
+// and event streams never finish
+var batchExcluded = map[string]bool{
+	"/admin/batch":   true,
+	"/client/events": true,
+}
+
+// batchOperation - one request of a batch, addressed by its path under the
+// API's base path
+type batchOperation struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	IfMatch uint64          `json:"ifMatch,omitempty"`
+}
+
+// batchResult - the outcome of one operation. Operations after a failure are
+// skipped unless the batch continues on error.
+type batchResult struct {
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Skipped    bool      `json:"skipped,omitempty"`
+	Response   *Response `json:"response,omitempty"`
+}
+
+// batchResponse - a Response with its data left encoded, as recorded from an
+// operation
+type batchResponse struct {
+	Status   ResponseStatus  `json:"status"`
+	Problems []*errors.Error `json:"problems"`
+	Data     json.RawMessage `json:"data"`
+	Next     string          `json:"next,omitempty"`
+}
+
+// batchHandler - run several operations in order through the router, as if
+// each was its own request from the same client. The batch stops at the first
+// failed operation unless `continueOnError` is set; it is not a transaction,
+// so operations which already succeeded are not undone.
+func batchHandler(root *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqLog := GetLogger(r)
+
+		continueOnError, err := DecodeBoolParam(w, r, "continueOnError")
+		if err != nil {
+			return
+		}
+
+		var ops []*batchOperation
+		if err := DecodeRequest(w, r, &ops); err != nil {
+			return
+		}
+
+		if problems := checkBatch(ops); len(problems) > 0 {
+			NewResponse().AddErrors(problems).Write(w)
+			return
+		}
+
+		basePath := configure.GetAPI().BasePath
+		contentType := operationType(w)
+		results := make([]*batchResult, len(ops))
+		res := NewResponse()
+		failed := false
+
+		for i, op := range ops {
+			results[i] = &batchResult{
+				Method: op.Method,
+				Path:   op.Path,
+			}
+
+			if failed && !continueOnError {
+				results[i].Skipped = true
+				continue
+			}
+
+			statusCode, opRes := runOperation(root, r, basePath, op, contentType)
+			results[i].StatusCode = statusCode
+			results[i].Response = opRes
+
+			if statusCode >= http.StatusBadRequest || opRes.Status == StatusError {
+				failed = true
+				reqLog.Debug("Batch operation failed", zap.Int("operation", i), zap.String("method", op.Method), zap.String("path", op.Path), zap.Int("status", statusCode))
+
+				for _, problem := range opRes.Problems {
+					res.AddError(operationProblem(problem, i))
+				}
+			}
+		}
+
+		// the operations' own status codes are in their results
+		res.SetData(results).SetStatusCode(http.StatusOK).Write(w)
+	}
+}
+
+// operationProblem - copy the problem of an operation, saying which operation
+// it came from
+func operationProblem(problem *errors.Error, i int) *errors.Error {
+	copied := *problem
+	copied.Details = make(map[string]interface{}, len(problem.Details)+1)
+	for key, value := range problem.Details {
+		copied.Details[key] = value
+	}
+
+	return copied.WithDetail("operation", i)
+}
+
+// checkBatch - make sure every operation can be run before running any
+func checkBatch(ops []*batchOperation) []*errors.Error {
+	if len(ops) == 0 {
+		return []*errors.Error{errors.EInvalidRequest.NewError("a batch needs at least one operation")}
+	}
+
+	if len(ops) > maxBatchOperations {
+		return []*errors.Error{errors.EInvalidRequest.NewErrorf("a batch may have at most %d operations", maxBatchOperations)}
+	}
+
+	problems := make([]*errors.Error, 0)
+	for i, op := range ops {
+		if op == nil {
+			problems = append(problems, errors.EInvalidRequest.NewErrorf("operation %d is empty", i).WithContext(fmt.Sprint(i)))
+			continue
+		}
+
+		if !batchMethods[op.Method] {
+			problems = append(problems, errors.EInvalidRequest.NewErrorf("operation %d has unsupported method \"%s\"", i, op.Method).WithContext(fmt.Sprintf("%d.method", i)))
+		}
+
+		target, err := url.Parse(op.Path)
+		if err != nil || len(target.Path) == 0 || target.Path[0] != '/' || target.IsAbs() {
+			problems = append(problems, errors.EInvalidRequest.NewErrorf("operation %d has invalid path \"%s\"", i, op.Path).WithContext(fmt.Sprintf("%d.path", i)))
+			continue
+		}
+
+		if batchExcluded[path.Clean(target.Path)] {
+			problems = append(problems, errors.EInvalidRequest.NewErrorf("%s can't be batched", target.Path).WithContext(fmt.Sprintf("%d.path", i)))
+		}
+	}
+
+	return problems
+}
+
+// operationType - the format operations are recorded in. Protobuf batches
+// record protobuf, so the operations' data keeps its messages; anything else
+// is recorded as JSON.
+func operationType(w http.ResponseWriter) string {
+	if responseType(w) == ContentTypeProtobuf {
+		return ContentTypeProtobuf
+	}
+
+	return ContentTypeJSON
+}
+
+// runOperation - serve one operation through the router, recording its
+// response in the given format. The operation is sent with the batch
+// request's cookies and request ID, so it is authenticated and logged as part
+// of the batch.
+func runOperation(root *mux.Router, r *http.Request, basePath string, op *batchOperation, contentType string) (int, *Response) {
+	target := basePath + op.Path
+
+	req, err := http.NewRequest(op.Method, target, bytes.NewReader(op.Body))
+	if err != nil {
+		return http.StatusBadRequest, FromError(errors.EInvalidRequest.NewErrorf("invalid operation: %s", err))
+	}
+
+	req = req.WithContext(r.Context())
+	req.RequestURI = target
+	req.RemoteAddr = r.RemoteAddr
+	req.Header.Set("Accept", contentType)
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set("Request-ID", r.Header.Get("Request-ID"))
+	for _, cookie := range r.Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	if op.IfMatch > 0 {
+		req.Header.Set("If-Match", fmt.Sprintf("\"%d\"", op.IfMatch))
+	}
+
+	rec := newRecorder()
+	root.ServeHTTP(rec, req)
+
+	res, err := rec.response(contentType)
+	if err != nil {
+		GetLogger(r).Error("Failed to decode batch operation response", zap.String("path", op.Path), zap.Error(err))
+		return http.StatusInternalServerError, FromError(errors.EInternal.NewError("operation did not return an API response"))
+	}
+
+	if res.Problems == nil {
+		res.Problems = make([]*errors.Error, 0)
+	}
+
+	return rec.statusCode, res
+}
+
+// recorder - a response writer which keeps the response of an operation
+type recorder struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		header:     make(http.Header),
+		statusCode: http.StatusOK,
+	}
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) Write(data []byte) (int, error) {
+	return rec.body.Write(data)
+}
+
+func (rec *recorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+}
+
+// response - decode the recorded response. The data is left encoded: as JSON,
+// or as the packed message of a protobuf response.
+func (rec *recorder) response(contentType string) (*Response, error) {
+	if contentType == ContentTypeProtobuf {
+		var envelope proto.Response
+		if err := protobuf.Unmarshal(rec.body.Bytes(), &envelope); err != nil {
+			return nil, err
+		}
+
+		res := &Response{
+			Status:   ResponseStatus(envelope.GetStatus()),
+			Problems: make([]*errors.Error, len(envelope.GetProblems())),
+			Next:     envelope.GetNext(),
+		}
+
+		for i, problem := range envelope.GetProblems() {
+			res.Problems[i] = rpcstatus.FromProblem(problem)
+		}
+
+		if envelope.GetData() != nil {
+			res.Data = envelope.GetData()
+		}
+
+		return res, nil
+	}
+
+	var recorded batchResponse
+	if err := json.Unmarshal(rec.body.Bytes(), &recorded); err != nil {
+		return nil, err
+	}
+
+	res := &Response{
+		Status:   recorded.Status,
+		Problems: recorded.Problems,
+		Data:     recorded.Data,
+		Next:     recorded.Next,
+	}
+
+	if len(recorded.Data) == 0 {
+		res.Data = nil
+	}
+
+	return res, nil
+}
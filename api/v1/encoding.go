@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/golang/protobuf/jsonpb"
+	protobuf "github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/vmihailenco/msgpack/v4"
+)
+
+const (
+	// ContentTypeJSON - the default format of requests and responses
+	ContentTypeJSON = "application/json"
+
+	// ContentTypeProtobuf - a proto.Response envelope, whose data is the
+	// message of the response's data. Request bodies are a
+	// google.protobuf.Value.
+	ContentTypeProtobuf = "application/x-protobuf"
+
+	// ContentTypeMsgpack - the JSON response or request, as MessagePack
+	ContentTypeMsgpack = "application/msgpack"
+)
+
+// contentTypes - the formats the API can read and write, including aliases
+var contentTypes = map[string]string{
+	"application/json":       ContentTypeJSON,
+	"application/x-protobuf": ContentTypeProtobuf,
+	"application/protobuf":   ContentTypeProtobuf,
+	"application/msgpack":    ContentTypeMsgpack,
+	"application/x-msgpack":  ContentTypeMsgpack,
+}
+
+// negotiatedWriter - a response writer which knows the format the client
+// asked for
+type negotiatedWriter struct {
+	http.ResponseWriter
+	contentType string
+}
+
+// Flush - flush buffered data to the client, for streaming responses
+func (n *negotiatedWriter) Flush() {
+	if f, ok := n.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// NegotiateMiddleware - middleware which picks the format of the response
+// from the Accept header. JSON is used if the client accepts none of the
+// other formats.
+func NegotiateMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		next.ServeHTTP(&negotiatedWriter{w, negotiate(r.Header.Get("Accept"))}, r)
+	})
+}
+
+// negotiate - choose the most preferred supported format of an Accept header
+func negotiate(accept string) string {
+	type candidate struct {
+		contentType string
+		q           float64
+	}
+
+	candidates := make([]candidate, 0)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		contentType, ok := contentTypes[mediaType]
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			candidates = append(candidates, candidate{contentType, q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return ContentTypeJSON
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	return candidates[0].contentType
+}
+
+// responseType - the format negotiated for a response writer
+func responseType(w http.ResponseWriter) string {
+	if n, ok := w.(*negotiatedWriter); ok {
+		return n.contentType
+	}
+
+	return ContentTypeJSON
+}
+
+// requestType - the format of a request body. Anything which isn't one of the
+// binary formats is read as JSON.
+func requestType(r *http.Request) string {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ContentTypeJSON
+	}
+
+	if contentType, ok := contentTypes[mediaType]; ok {
+		return contentType
+	}
+
+	return ContentTypeJSON
+}
+
+// encode - serialize a response in a format
+func (r *Response) encode(contentType string) ([]byte, error) {
+	switch contentType {
+	case ContentTypeProtobuf:
+		return r.encodeProtobuf()
+	case ContentTypeMsgpack:
+		return r.encodeMsgpack()
+	default:
+		return json.Marshal(r)
+	}
+}
+
+// encodeProtobuf - encode the response as its envelope message
+func (r *Response) encodeProtobuf() ([]byte, error) {
+	envelope, err := r.message()
+	if err != nil {
+		return nil, err
+	}
+
+	return protobuf.Marshal(envelope)
+}
+
+// encodeMsgpack - encode the response as MessagePack, with the same structure
+// as its JSON encoding
+func (r *Response) encodeMsgpack() ([]byte, error) {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return msgpack.Marshal(numbers(value))
+}
+
+// numbers - replace the JSON numbers in a decoded value with integers where
+// they are whole, and floats otherwise
+func numbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = numbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = numbers(item)
+		}
+	}
+
+	return value
+}
+
+// toJSON - convert a request body in one of the binary formats to JSON, so it
+// is decoded by the same rules as a JSON body
+func toJSON(contentType string, data []byte) ([]byte, *errors.Error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	switch contentType {
+	case ContentTypeProtobuf:
+		var value structpb.Value
+		if err := protobuf.Unmarshal(data, &value); err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("malformed protobuf: %s", err)
+		}
+
+		var buf bytes.Buffer
+		if err := (&jsonpb.Marshaler{}).Marshal(&buf, &value); err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("invalid protobuf value: %s", err)
+		}
+
+		return buf.Bytes(), nil
+	case ContentTypeMsgpack:
+		reader := bytes.NewReader(data)
+
+		var value interface{}
+		if err := msgpack.NewDecoder(reader).Decode(&value); err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("malformed MessagePack: %s", err)
+		}
+
+		if reader.Len() > 0 {
+			return nil, errors.EInvalidRequest.NewError("unexpected data after MessagePack value")
+		}
+
+		payload, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("invalid MessagePack value: %s", err)
+		}
+
+		return payload, nil
+	default:
+		return data, nil
+	}
+}
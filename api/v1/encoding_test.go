@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// TestProtobufData - protobuf responses carry the data as its own message,
+// so versions above 2^53 survive
+func TestProtobufData(t *testing.T) {
+	const version = 1<<53 + 1
+
+	payload, err := FromData([]*data.Player{
+		{Username: "bob", Position: &data.Position{Location: "town", X: 3, Y: 4}, Version: version},
+	}).SetNext("bob").encode(ContentTypeProtobuf)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	var envelope proto.Response
+	if err := protobuf.Unmarshal(payload, &envelope); err != nil {
+		t.Fatalf("decoding envelope: %v", err)
+	}
+
+	if envelope.GetStatus() != string(StatusOK) || envelope.GetNext() != "bob" {
+		t.Errorf("got status %q and next %q", envelope.GetStatus(), envelope.GetNext())
+	}
+
+	var list proto.List
+	if err := ptypes.UnmarshalAny(envelope.GetData(), &list); err != nil {
+		t.Fatalf("decoding list: %v", err)
+	}
+
+	if len(list.GetItems()) != 1 {
+		t.Fatalf("got %d items, want 1", len(list.GetItems()))
+	}
+
+	var player proto.Player
+	if err := ptypes.UnmarshalAny(list.GetItems()[0], &player); err != nil {
+		t.Fatalf("decoding player: %v", err)
+	}
+
+	want := &proto.Player{Username: "bob", Location: "town", X: 3, Y: 4, Version: version}
+	if !protobuf.Equal(&player, want) {
+		t.Errorf("got player %v, want %v", &player, want)
+	}
+}
+
+// TestProtobufProblems - problems are sent without data
+func TestProtobufProblems(t *testing.T) {
+	payload, err := FromError(errors.ENotFound.NewError("player not found")).encode(ContentTypeProtobuf)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	var envelope proto.Response
+	if err := protobuf.Unmarshal(payload, &envelope); err != nil {
+		t.Fatalf("decoding envelope: %v", err)
+	}
+
+	if envelope.GetData() != nil {
+		t.Errorf("got data %v, want none", envelope.GetData())
+	}
+
+	if len(envelope.GetProblems()) != 1 || envelope.GetProblems()[0].GetCode() != "NOT_FOUND" {
+		t.Errorf("got problems %v", envelope.GetProblems())
+	}
+}
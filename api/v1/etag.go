@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+)
+
+// SetETag sets the ETag header of a response to the version of the resource
+func SetETag(w http.ResponseWriter, version uint64) {
+	if version == 0 {
+		return
+	}
+
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// IfMatch reads the version a request expects the resource to be at from
+// its If-Match header, or 0 if any version will do
+func IfMatch(r *http.Request) (uint64, *errors.Error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if len(header) == 0 || header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, errors.EInvalidRequest.NewErrorf("invalid If-Match header `%s` (expected a single ETag)", header).WithContext("If-Match")
+	}
+
+	version, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version == 0 {
+		return 0, errors.EInvalidRequest.NewErrorf("unknown ETag %s", tag).WithContext("If-Match")
+	}
+
+	return version, nil
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/events"
+	"go.uber.org/zap"
+)
+
+// eventsHandler - stream changes to the game world as server-sent events.
+// The server's write timeout ends the stream periodically, and clients are
+// expected to reconnect as EventSource does automatically.
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	reqLog := GetLogger(r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		FromError(errors.EInternal.NewError("streaming is not supported")).Write(w)
+		return
+	}
+
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		FromError(errors.ERPCConnection.NewError(err)).Write(w)
+		return
+	}
+
+	stream, err := locationSvc.Events(r.Context())
+	if err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for msg := range stream {
+		payload, err := json.Marshal(&events.Event{
+			Kind:     msg.GetKind(),
+			Location: msg.GetLocation(),
+			Previous: msg.GetPrevious(),
+			Time:     time.Unix(msg.GetTime(), 0),
+		})
+		if err != nil {
+			reqLog.Error("Error encoding event", zap.String("kind", msg.GetKind()), zap.Error(err))
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.GetKind(), payload); err != nil {
+			reqLog.Debug("Event stream closed", zap.Error(err))
+			return
+		}
+
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/idempotency"
+	"go.uber.org/zap"
+)
+
+// IdempotencyHeader - the header a client sets to make a request safe to retry
+const IdempotencyHeader = "Idempotency-Key"
+
+// maxIdempotencyKey - the longest key a client may send
+const maxIdempotencyKey = 255
+
+// idempotentMethods - the methods whose responses are kept for their key
+var idempotentMethods = map[string]bool{
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// idempotencyExcluded - routes which ignore the key: logging in is safe to
+// repeat, and replaying it would store the auth cookie it sets
+var idempotencyExcluded = map[string]bool{
+	"/client/login": true,
+}
+
+// unreplayedHeaders - headers of a response which are not stored with it
+var unreplayedHeaders = map[string]bool{
+	"Set-Cookie": true,
+	"Date":       true,
+}
+
+// IdempotencyMiddleware - middleware which keeps the response to a mutating
+// request sent with an Idempotency-Key, and replays it when the request is
+// repeated with the same key. A key is scoped to the logged in player, and
+// reusing it for a different request is rejected. Responses with a server
+// error aren't kept, so the request can be retried. If the store fails, the
+// request is handled without its key. Must follow the NegotiateMiddleware.
+func IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyHeader)
+		if len(key) == 0 || !idempotentMethods[r.Method] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		conf := configure.GetAPI()
+		if idempotencyExcluded[strings.TrimPrefix(r.URL.Path, conf.BasePath)] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reqLog := GetLogger(r).With(zap.String("idempotencyKey", key))
+
+		if len(key) > maxIdempotencyKey {
+			FromError(errors.EInvalidRequest.NewErrorf("an idempotency key may be at most %d characters", maxIdempotencyKey).WithContext(IdempotencyHeader)).Write(w)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			reqLog.Error("Error reading request body", zap.Error(err))
+			FromError(errors.EInternal.NewError("Error reading request")).Write(w)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var audience string
+		if token := GetAuth(r); token != nil {
+			audience = token.Audience
+		}
+
+		scoped := fmt.Sprintf("%s/%s", audience, key)
+		fingerprint := requestFingerprint(r, body, responseType(w))
+
+		store := idempotency.GetStore()
+		existing, err := store.Begin(scoped, fingerprint)
+		if err != nil {
+			// as with rate limits, the request isn't refused because the
+			// store failed; it just can't be replayed
+			reqLog.Error("Failed to claim idempotency key, handling the request without it", zap.Error(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if existing != nil {
+			if existing.Fingerprint != fingerprint {
+				reqLog.Debug("Idempotency key reused for a different request")
+				FromError(errors.EIdempotencyMismatch.NewErrorf("key \"%s\" was used for a different request", key).WithContext(IdempotencyHeader)).Write(w)
+				return
+			}
+
+			if !existing.Complete {
+				reqLog.Debug("Request with idempotency key is in progress")
+				w.Header().Set("Retry-After", "1")
+				FromError(errors.EIdempotencyInProgress.NewErrorf("a request with key \"%s\" is in progress", key).WithContext(IdempotencyHeader)).Write(w)
+				return
+			}
+
+			reqLog.Debug("Replaying response", zap.Int("status", existing.StatusCode))
+			replay(w, existing)
+			return
+		}
+
+		rec := &idempotentWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(&negotiatedWriter{rec, responseType(w)}, r)
+
+		if rec.statusCode >= http.StatusInternalServerError {
+			if err := store.Abandon(scoped); err != nil {
+				reqLog.Error("Failed to release idempotency key", zap.Error(err))
+			}
+
+			return
+		}
+
+		record := &idempotency.Record{
+			Fingerprint: fingerprint,
+			Complete:    true,
+			StatusCode:  rec.statusCode,
+			Header:      make(http.Header),
+			Body:        rec.body.Bytes(),
+		}
+
+		for name, values := range w.Header() {
+			if !unreplayedHeaders[name] {
+				record.Header[name] = values
+			}
+		}
+
+		if err := store.Finish(scoped, record, conf.IdempotencyWindow); err != nil {
+			reqLog.Error("Failed to store response for idempotency key", zap.Error(err))
+		}
+	})
+}
+
+// requestFingerprint - identify a request by its method, URL, body and the
+// format of its response, so a key can't be reused for a different request,
+// or to replay a response in a format the client didn't ask for
+func requestFingerprint(r *http.Request, body []byte, contentType string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n%s\n", r.Method, r.URL.RequestURI(), contentType)
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replay - send a stored response again
+func replay(w http.ResponseWriter, record *idempotency.Record) {
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// idempotentWriter - a response writer which keeps a copy of the response it
+// writes
+type idempotentWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (i *idempotentWriter) WriteHeader(code int) {
+	i.statusCode = code
+	i.ResponseWriter.WriteHeader(code)
+}
+
+func (i *idempotentWriter) Write(data []byte) (int, error) {
+	i.body.Write(data)
+	return i.ResponseWriter.Write(data)
+}
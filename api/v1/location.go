@@ -6,7 +6,9 @@ import (
 	"github.com/carsonmyers/bublar-assignment/connect"
 	"github.com/carsonmyers/bublar-assignment/data"
 	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/locations/rpc"
 	"github.com/carsonmyers/bublar-assignment/proto"
+	"github.com/carsonmyers/bublar-assignment/validate"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -24,14 +26,12 @@ func createLocationHandler(w http.ResponseWriter, r *http.Request) {
 		id = req.Name
 	}
 
-	res := NewResponse()
+	v := validate.New().Check("name", req.Name, validate.LocationName...)
 	if id != req.Name && len(req.Name) > 0 {
-		res.AddError(errors.EInvalidRequest.NewError("location name does not match route").WithContext("name"))
-	}
-	if len(req.Name) == 0 {
-		res.AddError(errors.EInvalidRequest.NewError("location name is required").WithContext("name"))
+		v.Add(errors.EInvalidRequest.NewError("location name does not match route").WithContext("name"))
 	}
 
+	res := NewResponse().AddErrors(v.Problems())
 	if res.Status == StatusError {
 		res.Write(w)
 		return
@@ -43,21 +43,14 @@ func createLocationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	location, err := locationSvc.Create(&proto.Location{
-		Name: req.Name,
-		X:    int32(req.X),
-		Y:    int32(req.Y),
-	})
+	location, err := locationSvc.With(r.Context()).Create(rpc.LocationMessage(&req))
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
-	FromData(&data.Location{
-		Name: location.GetName(),
-		X:    int(location.GetX()),
-		Y:    int(location.GetY()),
-	}).Write(w)
+	SetETag(w, location.GetVersion())
+	FromData(rpc.LocationData(location)).Write(w)
 }
 
 func updateLocationHandler(w http.ResponseWriter, r *http.Request) {
@@ -73,8 +66,19 @@ func updateLocationHandler(w http.ResponseWriter, r *http.Request) {
 		id = req.Name
 	}
 
-	if len(id) == 0 {
-		FromError(errors.EInvalidRequest.NewError("location name is required").WithContext("name")).Write(w)
+	v := validate.New().Check("name", id, validate.Required)
+	if req.Name != id {
+		v.Check("name", req.Name, validate.LocationName...)
+	}
+
+	if problems := v.Problems(); len(problems) > 0 {
+		NewResponse().AddErrors(problems).Write(w)
+		return
+	}
+
+	version, e := IfMatch(r)
+	if e != nil {
+		FromError(e).Write(w)
 		return
 	}
 
@@ -84,25 +88,19 @@ func updateLocationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	location, err := locationSvc.Update(&proto.LocationUpdate{
-		Id: id,
-		Location: &proto.Location{
-			Name: req.Name,
-			X:    int32(req.X),
-			Y:    int32(req.Y),
-		},
+	req.Version = uint(version)
+	location, err := locationSvc.With(r.Context()).Update(&proto.LocationUpdate{
+		Id:       id,
+		Location: rpc.LocationMessage(&req),
 	})
 
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
-	FromData(&data.Location{
-		Name: location.GetName(),
-		X:    int(location.GetX()),
-		Y:    int(location.GetY()),
-	}).Write(w)
+	SetETag(w, location.GetVersion())
+	FromData(rpc.LocationData(location)).Write(w)
 }
 
 func getLocationHandler(w http.ResponseWriter, r *http.Request) {
@@ -125,27 +123,34 @@ func getLocationHandler(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
-	FromData(&data.Location{
-		Name: location.GetName(),
-		X:    int(location.GetX()),
-		Y:    int(location.GetY()),
-	}).Write(w)
+	SetETag(w, location.GetVersion())
+	FromData(rpc.LocationData(location)).Write(w)
 }
 
 func listLocationsHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := DecodeListParams(w, r)
+	if err != nil {
+		return
+	}
+
 	locationSvc, err := connect.Locations()
 	if err != nil {
 		FromError(errors.ERPCConnection.NewError(err)).Write(w)
 		return
 	}
 
-	locations, err := locationSvc.List()
+	locations, next, err := locationSvc.List(&proto.LocationListRequest{
+		Limit:  params.Limit,
+		After:  params.After,
+		Sort:   params.Sort,
+		Prefix: params.Prefix,
+	})
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
@@ -153,14 +158,10 @@ func listLocationsHandler(w http.ResponseWriter, r *http.Request) {
 
 	res := make([]*data.Location, len(locations))
 	for i, l := range locations {
-		res[i] = &data.Location{
-			Name: l.GetName(),
-			X:    int(l.GetX()),
-			Y:    int(l.GetY()),
-		}
+		res[i] = rpc.LocationData(l)
 	}
 
-	FromData(res).Write(w)
+	FromData(res).SetNext(next).Write(w)
 }
 
 func deleteLocationHandler(w http.ResponseWriter, r *http.Request) {
@@ -171,20 +172,50 @@ func deleteLocationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, e := IfMatch(r)
+	if e != nil {
+		FromError(e).Write(w)
+		return
+	}
+
 	locationSvc, err := connect.Locations()
 	if err != nil {
 		FromError(errors.ERPCConnection.NewError(err)).Write(w)
 		return
 	}
 
-	if err = locationSvc.Delete(id); err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+	if err = locationSvc.With(r.Context()).Delete(id, version); err != nil {
+		FromRPCError(err).Write(w)
 		return
 	}
 
 	FromData(nil).Write(w)
 }
 
+func restoreLocationHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || len(id) == 0 {
+		FromError(errors.EInvalidRequest.NewError("location name is required")).Write(w)
+		return
+	}
+
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		FromError(errors.ERPCConnection.NewError(err)).Write(w)
+		return
+	}
+
+	location, err := locationSvc.With(r.Context()).Restore(id)
+	if err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
+	SetETag(w, location.GetVersion())
+	FromData(rpc.LocationData(location)).Write(w)
+}
+
 func getPlayersInLocationHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, ok := vars["id"]
@@ -204,7 +235,7 @@ func getPlayersInLocationHandler(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
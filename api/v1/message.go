@@ -0,0 +1,202 @@
+package v1
+
+import (
+	"encoding/json"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors/rpcstatus"
+	"github.com/carsonmyers/bublar-assignment/locations/rpc"
+	playersRPC "github.com/carsonmyers/bublar-assignment/players/rpc"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"github.com/carsonmyers/bublar-assignment/world"
+	"github.com/golang/protobuf/jsonpb"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+)
+
+// listMessage - pack the items of a list response
+func listMessage(items []protobuf.Message) (*proto.List, error) {
+	list := &proto.List{
+		Items: make([]*any.Any, len(items)),
+	}
+
+	for i, item := range items {
+		packed, err := ptypes.MarshalAny(item)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Items[i] = packed
+	}
+
+	return list, nil
+}
+
+// message - convert the response to its envelope message
+func (r *Response) message() (*proto.Response, error) {
+	envelope := &proto.Response{
+		Status:   string(r.Status),
+		Problems: make([]*proto.Problem, len(r.Problems)),
+		Next:     r.Next,
+	}
+
+	for i, problem := range r.Problems {
+		envelope.Problems[i] = rpcstatus.Problem(problem)
+	}
+
+	if packed, ok := r.Data.(*any.Any); ok {
+		envelope.Data = packed
+		return envelope, nil
+	}
+
+	if r.Data != nil {
+		msg, err := dataMessage(r.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		packed, err := ptypes.MarshalAny(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		envelope.Data = packed
+	}
+
+	return envelope, nil
+}
+
+// dataMessage - convert the data of a response to its message. Data without a
+// message of its own is sent as a google.protobuf.Value of its JSON encoding.
+func dataMessage(value interface{}) (protobuf.Message, error) {
+	switch v := value.(type) {
+	case protobuf.Message:
+		return v, nil
+	case PingData:
+		return &proto.Ping{Say: v.Say}, nil
+	case *data.Player:
+		return playersRPC.PlayerMessage(v), nil
+	case []*data.Player:
+		items := make([]protobuf.Message, len(v))
+		for i, player := range v {
+			items[i] = playersRPC.PlayerMessage(player)
+		}
+
+		return listMessage(items)
+	case *data.Location:
+		return rpc.LocationMessage(v), nil
+	case []*data.Location:
+		items := make([]protobuf.Message, len(v))
+		for i, location := range v {
+			items[i] = rpc.LocationMessage(location)
+		}
+
+		return listMessage(items)
+	case []*data.Violation:
+		items := make([]protobuf.Message, len(v))
+		for i, violation := range v {
+			items[i] = violationMessage(violation)
+		}
+
+		return listMessage(items)
+	case []*data.AuditEntry:
+		items := make([]protobuf.Message, len(v))
+		for i, entry := range v {
+			items[i] = auditMessage(entry)
+		}
+
+		return listMessage(items)
+	case *world.Document:
+		return v.Message(), nil
+	case *world.Report:
+		return v.Message(), nil
+	case *world.Snapshot:
+		return v.Message(), nil
+	case *world.SnapshotReport:
+		return v.Message(), nil
+	case []*batchResult:
+		items := make([]protobuf.Message, len(v))
+		for i, result := range v {
+			msg, err := result.message()
+			if err != nil {
+				return nil, err
+			}
+
+			items[i] = msg
+		}
+
+		return listMessage(items)
+	}
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg structpb.Value
+	if err := jsonpb.UnmarshalString(string(payload), &msg); err != nil {
+		return nil, err
+	}
+
+	return &msg, nil
+}
+
+func violationMessage(violation *data.Violation) *proto.Violation {
+	msg := &proto.Violation{
+		Id:       uint64(violation.ID),
+		Username: violation.Username,
+		Location: violation.Location,
+		Distance: violation.Distance,
+		Allowed:  violation.Allowed,
+		Clamped:  violation.Clamped,
+		Time:     violation.Time.Unix(),
+	}
+
+	if violation.From != nil {
+		msg.FromX, msg.FromY = int32(violation.From.X), int32(violation.From.Y)
+	}
+
+	if violation.To != nil {
+		msg.ToX, msg.ToY = int32(violation.To.X), int32(violation.To.Y)
+	}
+
+	return msg
+}
+
+func auditMessage(entry *data.AuditEntry) *proto.AuditEntry {
+	return &proto.AuditEntry{
+		Id:        uint64(entry.ID),
+		Time:      entry.Time.Unix(),
+		Actor:     entry.Actor,
+		Source:    entry.Source,
+		Address:   entry.Address,
+		Action:    entry.Action,
+		Target:    entry.Target,
+		RequestId: entry.RequestID,
+		Before:    string(entry.Before),
+		After:     string(entry.After),
+	}
+}
+
+// message - convert a batch result to its message
+func (b *batchResult) message() (*proto.BatchResult, error) {
+	msg := &proto.BatchResult{
+		Method:     b.Method,
+		Path:       b.Path,
+		StatusCode: int32(b.StatusCode),
+		Skipped:    b.Skipped,
+	}
+
+	if b.Response != nil {
+		res, err := b.Response.message()
+		if err != nil {
+			return nil, err
+		}
+
+		msg.Response = res
+	}
+
+	return msg, nil
+}
@@ -37,7 +37,8 @@ func GetAuth(r *http.Request) *jwt.JWT {
 	return nil
 }
 
-func authMiddleware(next http.Handler) http.Handler {
+// AuthMiddleware - middleware that reads the auth cookie into the request context
+func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		reqLog := GetLogger(r)
 
@@ -80,9 +81,12 @@ func SetAuth(w http.ResponseWriter, r *http.Request, token *jwt.JWT) *errors.Err
 
 	encoded := base64.StdEncoding.EncodeToString(payload)
 
+	// the cookie is sent to every API version and the GraphQL endpoint, not
+	// just the path it was set from
 	cookie := &http.Cookie{
 		Name:  "AUTH",
 		Value: encoded,
+		Path:  "/",
 	}
 
 	reqLog.Info("Set auth cookie", zap.String("username", token.Audience), zap.String("value", encoded))
@@ -152,6 +156,13 @@ func (l *loggingResponseWriter) Write(data []byte) (bytes int, err error) {
 	return
 }
 
+// Flush - flush buffered data to the client, for streaming responses
+func (l *loggingResponseWriter) Flush() {
+	if f, ok := l.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // LoggingMiddleware - middleware which logs every request and response
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
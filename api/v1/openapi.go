@@ -0,0 +1,530 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/events"
+	"github.com/carsonmyers/bublar-assignment/world"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// parameter - a query string parameter of an operation
+type parameter struct {
+	name        string
+	kind        string
+	description string
+}
+
+// operation - what the OpenAPI document says about a route. The routes
+// themselves are found by walking the router, so a route missing from this
+// table is still documented, just without its schemas.
+type operation struct {
+	summary string
+
+	// request and response are zero values of the request body and the data
+	// in the response envelope; their schemas are generated from the types
+	request  interface{}
+	response interface{}
+
+	query   []parameter
+	list    bool
+	auth    bool
+	etag    bool
+	ifMatch bool
+	yaml    bool
+	stream  bool
+}
+
+var listParams = []parameter{
+	{"limit", "integer", "Maximum number of results in the page"},
+	{"after", "string", "Cursor from the `next` field of the previous page"},
+	{"sort", "string", "Field to sort by, prefixed with `-` for descending order"},
+	{"prefix", "string", "Only include names starting with this prefix"},
+}
+
+var operations = map[string]*operation{
+	"POST /ping":        {summary: "Check that the API is up", request: PingData{}, response: PingData{}},
+	"GET /openapi.json": {summary: "This document"},
+
+	"PUT /admin/players/{id}":            {summary: "Create a player", request: data.Player{}, response: data.Player{}, etag: true},
+	"PATCH /admin/players/{id}":          {summary: "Change a player's username or password", request: data.Player{}, response: data.Player{}, etag: true, ifMatch: true},
+	"DELETE /admin/players/{id}":         {summary: "Delete a player", ifMatch: true},
+	"POST /admin/players/{id}/restore":   {summary: "Restore a deleted player", response: data.Player{}, etag: true},
+	"POST /admin/players/{id}/move":      {summary: "Move a player within their location", request: moveRequest{}, response: data.Player{}},
+	"POST /admin/players/{id}/travel":    {summary: "Send a player to a location", request: travelRequest{}, response: data.Player{}},
+	"GET /admin/players/{id}/violations": {summary: "List a player's moves which broke the speed limit, newest first", response: []data.Violation{}},
+
+	"POST /admin/locations":              {summary: "Create a location", request: data.Location{}, response: data.Location{}, etag: true},
+	"PUT /admin/locations/{id}":          {summary: "Create a location", request: data.Location{}, response: data.Location{}, etag: true},
+	"PATCH /admin/locations/{id}":        {summary: "Update or rename a location", request: data.Location{}, response: data.Location{}, etag: true, ifMatch: true},
+	"DELETE /admin/locations/{id}":       {summary: "Delete a location, moving its players to the fallback", ifMatch: true},
+	"POST /admin/locations/{id}/restore": {summary: "Restore a deleted location", response: data.Location{}, etag: true},
+
+	"GET /admin/world": {
+		summary:  "Export the world",
+		response: world.Document{},
+		query:    []parameter{{"players", "boolean", "Include players and their positions"}},
+	},
+	"POST /admin/world": {
+		summary:  "Import a world document",
+		request:  world.Document{},
+		response: world.Report{},
+		yaml:     true,
+		query: []parameter{
+			{"upsert", "boolean", "Update locations and players which already exist"},
+			{"dryRun", "boolean", "Report the changes without making them"},
+		},
+	},
+	"GET /admin/snapshot": {summary: "Take a snapshot of the world", response: world.Snapshot{}},
+	"POST /admin/snapshot": {
+		summary:  "Restore a snapshot",
+		request:  world.Snapshot{},
+		response: world.SnapshotReport{},
+		query:    []parameter{{"clear", "boolean", "Remove anything which is not in the snapshot"}},
+	},
+
+	"GET /admin/audit": {
+		summary:  "List changes made through the services, newest first",
+		response: []data.AuditEntry{},
+		list:     true,
+		query: []parameter{
+			{"actor", "string", "Only include changes made by this player"},
+			{"action", "string", "Only include this kind of change, like `player.delete`"},
+			{"target", "string", "Only include changes to this player or location"},
+			{"requestId", "string", "Only include changes made by the request with this ID"},
+			{"since", "string", "Only include changes made at or after this RFC 3339 time"},
+			{"until", "string", "Only include changes made before this RFC 3339 time"},
+		},
+	},
+	"POST /admin/batch": {
+		summary:  "Run several operations in order",
+		request:  []batchOperation{},
+		response: []batchResult{},
+		query:    []parameter{{"continueOnError", "boolean", "Run every operation, instead of stopping at the first which fails"}},
+	},
+
+	"POST /client/login":   {summary: "Log in, setting the AUTH cookie", request: data.Player{}},
+	"POST /client/players": {summary: "Create a player", request: data.Player{}, response: data.Player{}, etag: true},
+	"GET /client/players": {
+		summary:  "List players",
+		response: []data.Player{},
+		list:     true,
+		query: []parameter{
+			{"location", "string", "Only include players in this location"},
+			{"online", "boolean", "Only include players who are in a location"},
+		},
+	},
+	"GET /client/players/{id}":           {summary: "Get a player", response: data.Player{}, etag: true},
+	"GET /client/locations":              {summary: "List locations", response: []data.Location{}, list: true},
+	"GET /client/locations/{id}":         {summary: "Get a location", response: data.Location{}, etag: true},
+	"GET /client/locations/{id}/players": {summary: "List the players in a location", response: []data.Player{}},
+	"GET /client/events":                 {summary: "Stream location events", response: events.Event{}, stream: true},
+
+	"GET /client/player":         {summary: "Get the logged in player", response: data.Player{}, auth: true, etag: true},
+	"PATCH /client/player":       {summary: "Change the logged in player's username or password, renewing the AUTH cookie", request: data.Player{}, response: data.Player{}, auth: true, etag: true, ifMatch: true},
+	"DELETE /client/player":      {summary: "Delete the logged in player", auth: true, ifMatch: true},
+	"POST /client/player/move":   {summary: "Move within the current location, no faster than the speed limit", request: moveRequest{}, response: data.Player{}, auth: true},
+	"POST /client/player/travel": {summary: "Travel to a location", request: travelRequest{}, response: data.Player{}, auth: true},
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+func openAPIHandler(root *mux.Router) http.HandlerFunc {
+	var once sync.Once
+	var doc []byte
+	var err error
+
+	// the document is built on first use, once every route is registered
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			doc, err = json.Marshal(buildOpenAPI(root))
+		})
+
+		if err != nil {
+			log.Error("Failed to serialize OpenAPI document", zap.Error(err))
+			FromError(errors.EInternal.NewError(err)).Write(w)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		if _, err := w.Write(doc); err != nil {
+			log.Error("Failed to write OpenAPI document", zap.Error(err))
+		}
+	}
+}
+
+// buildOpenAPI - describe every route of the router as an OpenAPI 3 document
+func buildOpenAPI(root *mux.Router) map[string]interface{} {
+	basePath := configure.GetAPI().BasePath
+	s := newSchemas()
+	paths := make(map[string]map[string]interface{})
+
+	err := walkRoutes(root, func(method, path string) {
+		op, ok := operations[method+" "+path]
+		if !ok {
+			log.Warn("Route has no OpenAPI description", zap.String("method", method), zap.String("path", path))
+			op = &operation{}
+		}
+
+		key := pathParamPattern.ReplaceAllString(path, "{$1}")
+		if paths[key] == nil {
+			paths[key] = make(map[string]interface{})
+		}
+
+		paths[key][strings.ToLower(method)] = s.operation(method, path, op)
+	})
+	if err != nil {
+		log.Error("Failed to walk routes for OpenAPI document", zap.Error(err))
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Bublar API",
+			"version": strings.Trim(basePath, "/"),
+		},
+		"servers": []interface{}{
+			map[string]interface{}{"url": basePath},
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": s.components,
+			"responses": map[string]interface{}{
+				"Problem": map[string]interface{}{
+					"description": "The request failed; the problems say why",
+					"content":     content(envelope(nil, false)),
+				},
+			},
+			"securitySchemes": map[string]interface{}{
+				"auth": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "cookie",
+					"name": "AUTH",
+				},
+			},
+		},
+	}
+}
+
+// walkRoutes - call fn with the method and path, relative to the API's base
+// path, of every route the router serves
+func walkRoutes(root *mux.Router, fn func(method, path string)) error {
+	basePath := configure.GetAPI().BasePath
+	return root.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		// subrouters and the not found handler have no methods
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		path := strings.TrimPrefix(template, strings.TrimSuffix(basePath, "/"))
+		for _, method := range methods {
+			fn(method, path)
+		}
+
+		return nil
+	})
+}
+
+// schemas - JSON schemas generated from Go types, collected as components
+type schemas struct {
+	components map[string]interface{}
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: map[string]interface{}{
+			"Status": map[string]interface{}{
+				"type": "string",
+				"enum": []string{string(StatusOK), StatusWarn, StatusError},
+			},
+			"Problem": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"code", "ctx", "kind", "message"},
+				"properties": map[string]interface{}{
+					"code":    map[string]interface{}{"type": "string", "enum": errors.Codes()},
+					"ctx":     map[string]interface{}{"type": "string", "description": "The request field at fault, if any"},
+					"kind":    map[string]interface{}{"type": "string"},
+					"message": map[string]interface{}{"type": "string"},
+					"details": map[string]interface{}{"type": "object", "additionalProperties": true},
+				},
+			},
+		},
+	}
+}
+
+func (s *schemas) operation(method, path string, op *operation) map[string]interface{} {
+	parameters := make([]interface{}, 0)
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+
+	query := op.query
+	if op.list {
+		query = append(append([]parameter{}, listParams...), query...)
+	}
+
+	for _, param := range query {
+		parameters = append(parameters, map[string]interface{}{
+			"name":        param.name,
+			"in":          "query",
+			"description": param.description,
+			"schema":      map[string]interface{}{"type": param.kind},
+		})
+	}
+
+	if op.ifMatch {
+		parameters = append(parameters, map[string]interface{}{
+			"name":        "If-Match",
+			"in":          "header",
+			"description": "Only make the change if the resource is still at this version",
+			"schema":      map[string]interface{}{"type": "string"},
+		})
+	}
+
+	success := map[string]interface{}{
+		"description": "The request succeeded",
+	}
+
+	if op.stream {
+		success["content"] = map[string]interface{}{
+			"text/event-stream": map[string]interface{}{
+				"schema": s.schema(reflect.TypeOf(op.response)),
+			},
+		}
+	} else {
+		success["content"] = content(envelope(s.data(op.response), op.list))
+	}
+
+	if op.etag {
+		success["headers"] = map[string]interface{}{
+			"ETag": map[string]interface{}{
+				"description": "The version of the resource",
+				"schema":      map[string]interface{}{"type": "string"},
+			},
+		}
+	}
+
+	result := map[string]interface{}{
+		"operationId": operationID(method, path),
+		"tags":        []string{strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]},
+		"parameters":  parameters,
+		"responses": map[string]interface{}{
+			"200":     success,
+			"default": map[string]interface{}{"$ref": "#/components/responses/Problem"},
+		},
+	}
+
+	if len(op.summary) > 0 {
+		result["summary"] = op.summary
+	}
+
+	if op.auth {
+		result["security"] = []interface{}{
+			map[string]interface{}{"auth": []string{}},
+		}
+	}
+
+	if op.request != nil {
+		body := s.schema(reflect.TypeOf(op.request))
+		bodyContent := content(body)
+		if op.yaml {
+			// world documents are decoded by the world package, as JSON or YAML
+			bodyContent = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": body},
+				"application/yaml": map[string]interface{}{"schema": body},
+			}
+		}
+
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  bodyContent,
+		}
+	}
+
+	return result
+}
+
+// data - the schema of the data in a response, which is null if there isn't any
+func (s *schemas) data(value interface{}) map[string]interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return s.schema(reflect.TypeOf(value))
+}
+
+// schema - the JSON schema of a type, adding structs to the components
+func (s *schemas) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(errors.Error{}):
+		return map[string]interface{}{"$ref": "#/components/schemas/Problem"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.schema(t.Elem())}
+	case reflect.Struct:
+		name := componentName(t)
+		if _, ok := s.components[name]; !ok {
+			// reserve the name first in case the type refers to itself
+			s.components[name] = nil
+			s.components[name] = s.object(t)
+		}
+
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func (s *schemas) object(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		properties[name] = s.schema(field.Type)
+
+		omitempty := false
+		for _, option := range parts[1:] {
+			omitempty = omitempty || option == "omitempty"
+		}
+
+		if !omitempty && field.Type.Kind() != reflect.Ptr {
+			required = append(required, name)
+		}
+	}
+
+	object := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		object["required"] = required
+	}
+
+	return object
+}
+
+// content - the formats a body can be sent in. MessagePack has the same
+// structure as JSON; protobuf is a proto.Response envelope for responses and a
+// google.protobuf.Value for requests.
+func content(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		ContentTypeJSON:    map[string]interface{}{"schema": schema},
+		ContentTypeMsgpack: map[string]interface{}{"schema": schema},
+		ContentTypeProtobuf: map[string]interface{}{
+			"schema": map[string]interface{}{"type": "string", "format": "binary"},
+		},
+	}
+}
+
+// envelope - the schema of the Response structure wrapped around data
+func envelope(data map[string]interface{}, list bool) map[string]interface{} {
+	if data == nil {
+		data = map[string]interface{}{"nullable": true}
+	}
+
+	properties := map[string]interface{}{
+		"status": map[string]interface{}{"$ref": "#/components/schemas/Status"},
+		"problems": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"$ref": "#/components/schemas/Problem"},
+		},
+		"data": data,
+	}
+
+	if list {
+		properties["next"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Cursor for the next page, if there is one",
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"required":   []string{"status", "problems", "data"},
+		"properties": properties,
+	}
+}
+
+// componentName - name a struct after its type, prefixed by its package
+// unless it is one of the shared data types or already named for the package
+func componentName(t reflect.Type) string {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	if pkg == "data" || pkg == "v1" || strings.HasPrefix(strings.ToLower(pkg), strings.ToLower(name)) {
+		return name
+	}
+
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + name
+}
+
+// operationID - a name for an operation made from its method and path, such
+// as getClientLocationsIdPlayers
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '.' || r == ':'
+	}) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	return id
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/gorilla/mux"
+)
+
+// TestOperations - every route the API serves (with the admin routes enabled)
+// must be described in the operations table, and every entry in the table
+// must be a route
+func TestOperations(t *testing.T) {
+	conf := configure.DefaultAPIConfig
+	conf.EnableAdmin = true
+	configure.API(&conf)
+	defer configure.API(nil)
+
+	root := mux.NewRouter()
+	Init(root.PathPrefix(conf.BasePath).Subrouter())
+
+	routes := make(map[string]bool)
+	if err := walkRoutes(root, func(method, path string) {
+		routes[method+" "+path] = true
+	}); err != nil {
+		t.Fatalf("walking routes: %v", err)
+	}
+
+	if len(routes) == 0 {
+		t.Fatal("found no routes")
+	}
+
+	undocumented := make([]string, 0)
+	for route := range routes {
+		if _, ok := operations[route]; !ok {
+			undocumented = append(undocumented, route)
+		}
+	}
+
+	unrouted := make([]string, 0)
+	for route := range operations {
+		if !routes[route] {
+			unrouted = append(unrouted, route)
+		}
+	}
+
+	sort.Strings(undocumented)
+	for _, route := range undocumented {
+		t.Errorf("route %s has no entry in operations", route)
+	}
+
+	sort.Strings(unrouted)
+	for _, route := range unrouted {
+		t.Errorf("operations entry %s has no route", route)
+	}
+}
@@ -3,11 +3,14 @@ package v1
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/carsonmyers/bublar-assignment/connect"
 	"github.com/carsonmyers/bublar-assignment/data"
 	"github.com/carsonmyers/bublar-assignment/errors"
+	playersRPC "github.com/carsonmyers/bublar-assignment/players/rpc"
 	"github.com/carsonmyers/bublar-assignment/proto"
+	"github.com/carsonmyers/bublar-assignment/validate"
 	"github.com/gbrlsnchs/jwt/v2"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -26,17 +29,19 @@ func createPlayerHandler(w http.ResponseWriter, r *http.Request) {
 		id = req.Username
 	}
 
-	res := NewResponse()
-	if id != req.Username && len(req.Username) > 0 {
-		res.AddError(errors.EInvalidRequest.NewError("username does not match route").WithContext("username"))
-	}
-	if len(req.Username) == 0 {
-		res.AddError(errors.EInvalidRequest.NewError("username is required").WithContext("username"))
+	var password string
+	if req.Password != nil {
+		password = *req.Password
 	}
-	if req.Password == nil || len(*req.Password) == 0 {
-		res.AddError(errors.EInvalidRequest.NewError("password is required").WithContext("password"))
+
+	v := validate.New().
+		Check("username", req.Username, validate.Username...).
+		Check("password", password, validate.Password...)
+	if id != req.Username && len(req.Username) > 0 {
+		v.Add(errors.EInvalidRequest.NewError("username does not match route").WithContext("username"))
 	}
 
+	res := NewResponse().AddErrors(v.Problems())
 	if res.Status == StatusError {
 		res.Write(w)
 		return
@@ -48,15 +53,16 @@ func createPlayerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	player, err := playerSvc.Create(&proto.Player{
+	player, err := playerSvc.With(r.Context()).Create(&proto.Player{
 		Username: req.Username,
-		Password: *req.Password,
+		Password: password,
 	})
 	if err != nil {
-		res.AddError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
+	SetETag(w, player.GetVersion())
 	res.SetData(player).Write(w)
 }
 
@@ -66,19 +72,25 @@ func loginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res := NewResponse()
-	if len(req.Username) == 0 {
-		res.AddError(errors.EInvalidRequest.NewError("username is required").WithContext("username"))
-	}
-	if req.Password == nil || len(*req.Password) == 0 {
-		res.AddError(errors.EInvalidRequest.NewError("password is required").WithContext("password"))
+	var password string
+	if req.Password != nil {
+		password = *req.Password
 	}
 
+	// only presence is checked, so accounts made before the rules still log in
+	res := NewResponse().AddErrors(validate.New().
+		Check("username", req.Username, validate.Required).
+		Check("password", password, validate.Required).
+		Problems())
 	if res.Status == StatusError {
 		res.Write(w)
 		return
 	}
 
+	if !allowLogin(w, r, req.Username) {
+		return
+	}
+
 	playerSvc, err := connect.Players()
 	if err != nil {
 		res.AddError(errors.ERPCConnection.NewError(err)).Write(w)
@@ -87,13 +99,19 @@ func loginHandler(w http.ResponseWriter, r *http.Request) {
 
 	tokenResponse, err := playerSvc.Auth(&proto.Player{
 		Username: req.Username,
-		Password: *req.Password,
+		Password: password,
 	})
 	if err != nil {
-		res.AddError(errors.ERPC.NewError(err)).Write(w)
+		if errors.IsKind(err, errors.EAuth) {
+			recordLogin(r, req.Username, true)
+		}
+
+		FromRPCError(err).Write(w)
 		return
 	}
 
+	recordLogin(r, req.Username, false)
+
 	var token *jwt.JWT
 	if err := json.Unmarshal([]byte(tokenResponse.Token), &token); err != nil {
 		res.AddError(errors.EInternal.NewError(err)).Write(w)
@@ -142,35 +160,41 @@ func getPlayerHandler(w http.ResponseWriter, r *http.Request) {
 		Username: id,
 	})
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
-	result := &data.Player{
-		Username: player.GetUsername(),
-	}
+	SetETag(w, player.GetVersion())
+	FromData(playersRPC.PlayerData(player)).Write(w)
+}
 
-	if len(player.GetLocation()) > 0 {
-		result.Position = &data.Position{
-			Location: player.GetLocation(),
-			X:        int(player.GetX()),
-			Y:        int(player.GetY()),
-		}
+func listPlayersHandler(w http.ResponseWriter, r *http.Request) {
+	params, err := DecodeListParams(w, r)
+	if err != nil {
+		return
 	}
 
-	FromData(result).Write(w)
-}
+	online, err := DecodeBoolParam(w, r, "online")
+	if err != nil {
+		return
+	}
 
-func listPlayersHandler(w http.ResponseWriter, r *http.Request) {
 	playerSvc, err := connect.Players()
 	if err != nil {
 		FromError(errors.ERPCConnection.NewError(err)).Write(w)
 		return
 	}
 
-	players, err := playerSvc.List()
+	players, next, err := playerSvc.List(&proto.PlayerListRequest{
+		Limit:    params.Limit,
+		After:    params.After,
+		Sort:     params.Sort,
+		Prefix:   params.Prefix,
+		Location: r.URL.Query().Get("location"),
+		Online:   online,
+	})
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
@@ -178,24 +202,79 @@ func listPlayersHandler(w http.ResponseWriter, r *http.Request) {
 
 	results := make([]*data.Player, len(players))
 	for i, p := range players {
-		results[i] = &data.Player{
-			Username: p.GetUsername(),
-		}
-
-		if len(p.GetLocation()) > 0 {
-			results[i].Position = &data.Position{
-				Location: p.GetLocation(),
-				X:        int(p.GetX()),
-				Y:        int(p.GetY()),
-			}
-		}
+		results[i] = playersRPC.PlayerData(p)
 	}
 
-	FromData(results).Write(w)
+	FromData(results).SetNext(next).Write(w)
 }
 
 func updatePlayerHandler(w http.ResponseWriter, r *http.Request) {
-	FromError(errors.ENotImplemented.NewError("update not implemented")).Write(w)
+	vars := mux.Vars(r)
+	auth := GetAuth(r)
+
+	var req data.Player
+	if err := DecodeRequest(w, r, &req); err != nil {
+		return
+	}
+
+	id, ok := vars["id"]
+	if !ok || len(id) == 0 {
+		if auth != nil {
+			id = auth.Audience
+		} else {
+			FromError(errors.EInvalidRequest.NewError("id or auth token is required")).Write(w)
+			return
+		}
+	}
+
+	if len(id) == 0 {
+		FromError(errors.EAuth.NewError("not logged in")).Write(w)
+		return
+	}
+
+	version, e := IfMatch(r)
+	if e != nil {
+		FromError(e).Write(w)
+		return
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		FromError(errors.ERPCConnection.NewError(err)).Write(w)
+		return
+	}
+
+	update := &proto.Player{
+		Username: req.Username,
+		Version:  version,
+	}
+
+	if req.Password != nil {
+		update.Password = *req.Password
+	}
+
+	player, err := playerSvc.With(r.Context()).Update(&proto.PlayerUpdate{
+		Id:     id,
+		Player: update,
+	})
+	if err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
+	// the auth token names the player, so players renaming themselves are
+	// given a new one
+	if auth != nil && auth.Audience == id && player.GetUsername() != id {
+		renamed := *auth
+		renamed.Audience = player.GetUsername()
+		if err := SetAuth(w, r, &renamed); err != nil {
+			FromError(err).Write(w)
+			return
+		}
+	}
+
+	SetETag(w, player.GetVersion())
+	FromData(playersRPC.PlayerData(player)).Write(w)
 }
 
 func deletePlayerHandler(w http.ResponseWriter, r *http.Request) {
@@ -217,20 +296,50 @@ func deletePlayerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, e := IfMatch(r)
+	if e != nil {
+		FromError(e).Write(w)
+		return
+	}
+
 	playerSvc, err := connect.Players()
 	if err != nil {
 		FromError(errors.ERPCConnection.NewError(err)).Write(w)
 		return
 	}
 
-	if err := playerSvc.Delete(id); err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+	if err := playerSvc.With(r.Context()).Delete(id, version); err != nil {
+		FromRPCError(err).Write(w)
 		return
 	}
 
 	FromData(nil).Write(w)
 }
 
+func restorePlayerHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || len(id) == 0 {
+		FromError(errors.EInvalidRequest.NewError("username is required")).Write(w)
+		return
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		FromError(errors.ERPCConnection.NewError(err)).Write(w)
+		return
+	}
+
+	player, err := playerSvc.With(r.Context()).Restore(id)
+	if err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
+	SetETag(w, player.GetVersion())
+	FromData(playersRPC.PlayerData(player)).Write(w)
+}
+
 type moveRequest struct {
 	X int `json:"x"`
 	Y int `json:"y"`
@@ -245,8 +354,11 @@ func movePlayerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// players moving themselves are held to the speed limit, admins moving
+	// them through the admin routes aren't
 	id, ok := vars["id"]
-	if !ok || len(id) == 0 {
+	self := !ok || len(id) == 0
+	if self {
 		if auth != nil {
 			id = auth.Audience
 		} else {
@@ -266,9 +378,9 @@ func movePlayerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	position, err := playerSvc.Move(id, int32(req.X), int32(req.Y))
+	position, err := playerSvc.With(r.Context()).Move(id, int32(req.X), int32(req.Y), self)
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
@@ -282,6 +394,52 @@ func movePlayerHandler(w http.ResponseWriter, r *http.Request) {
 	}).Write(w)
 }
 
+func getViolationsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok || len(id) == 0 {
+		FromError(errors.EInvalidRequest.NewError("username is required")).Write(w)
+		return
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		FromError(errors.ERPCConnection.NewError(err)).Write(w)
+		return
+	}
+
+	violations, err := playerSvc.Violations(id)
+	if err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
+	res := make([]*data.Violation, len(violations))
+	for i, v := range violations {
+		res[i] = &data.Violation{
+			ID:       uint(v.GetId()),
+			Username: v.GetUsername(),
+			Location: v.GetLocation(),
+			From: &data.Position{
+				Location: v.GetLocation(),
+				X:        int(v.GetFromX()),
+				Y:        int(v.GetFromY()),
+			},
+			To: &data.Position{
+				Location: v.GetLocation(),
+				X:        int(v.GetToX()),
+				Y:        int(v.GetToY()),
+			},
+			Distance: v.GetDistance(),
+			Allowed:  v.GetAllowed(),
+			Clamped:  v.GetClamped(),
+			Time:     time.Unix(v.GetTime(), 0).UTC(),
+		}
+	}
+
+	FromData(res).Write(w)
+}
+
 type travelRequest struct {
 	Location string `json:"location"`
 }
@@ -295,6 +453,11 @@ func travelPlayerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validate.New().Check("location", req.Location, validate.Required).Err(); err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
 	id, ok := vars["id"]
 	if !ok || len(id) == 0 {
 		if auth != nil {
@@ -316,9 +479,9 @@ func travelPlayerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := playerSvc.Travel(id, req.Location)
+	res, err := playerSvc.With(r.Context()).Travel(id, req.Location)
 	if err != nil {
-		FromError(errors.ERPC.NewError(err)).Write(w)
+		FromRPCError(err).Write(w)
 		return
 	}
 
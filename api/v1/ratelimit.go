@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/ratelimit"
+	"go.uber.org/zap"
+)
+
+// clientIP - the address a request was sent from
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// writeRetryAfter - respond to a limited request, saying how long to wait
+// before trying again
+func writeRetryAfter(w http.ResponseWriter, retryAfter time.Duration, err *errors.Error) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	FromError(err.WithDetail("retryAfter", seconds)).Write(w)
+}
+
+// RateLimitMiddleware - middleware which limits how often a player can make
+// gameplay requests, like moving. Requests without a login are limited by
+// their address.
+func RateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := clientIP(r)
+		if auth := GetAuth(r); auth != nil && len(auth.Audience) > 0 {
+			key = auth.Audience
+		}
+
+		// the limit fails open, so an unavailable store doesn't stop the game
+		retryAfter, err := ratelimit.Gameplay(key)
+		if err != nil {
+			GetLogger(r).Error("Failed to check rate limit", zap.Error(err))
+		} else if retryAfter > 0 {
+			GetLogger(r).Debug("Request rate limited", zap.String("key", key), zap.Duration("retryAfter", retryAfter))
+			writeRetryAfter(w, retryAfter, errors.ERateLimited.NewError("too many requests, slow down"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loginKeys - the keys a login attempt is limited by: the address it came
+// from and the username it tried
+func loginKeys(r *http.Request, username string) []string {
+	return []string{"login/ip/" + clientIP(r), ratelimit.LoginKey(username)}
+}
+
+// allowLogin - check that a login may be attempted, responding if it may not.
+// Logins from one address are limited to a number per window, and addresses or
+// usernames which failed too often are locked out for a while.
+func allowLogin(w http.ResponseWriter, r *http.Request, username string) bool {
+	reqLog := GetLogger(r)
+	keys := loginKeys(r, username)
+
+	for _, key := range keys {
+		lockout, err := ratelimit.Locked(key)
+		if err != nil {
+			reqLog.Error("Failed to check login lockout", zap.String("key", key), zap.Error(err))
+			continue
+		}
+
+		if lockout > 0 {
+			reqLog.Warn("Login locked out", zap.String("key", key), zap.Duration("retryAfter", lockout))
+			writeRetryAfter(w, lockout, errors.ERateLimited.NewError("too many failed logins, try again later"))
+			return false
+		}
+	}
+
+	conf := configure.GetRateLimit()
+	if conf.LoginRate == 0 {
+		return true
+	}
+
+	retryAfter, err := ratelimit.Allow(keys[0], conf.LoginRate, conf.LoginWindow)
+	if err != nil {
+		reqLog.Error("Failed to check login rate", zap.Error(err))
+	} else if retryAfter > 0 {
+		reqLog.Warn("Login rate limited", zap.String("key", keys[0]), zap.Duration("retryAfter", retryAfter))
+		writeRetryAfter(w, retryAfter, errors.ERateLimited.NewError("too many logins, try again later"))
+		return false
+	}
+
+	return true
+}
+
+// recordLogin - count a failed login against its address and username, or
+// forget the failures of the username after a success. The address keeps its
+// failures, so logging into one account doesn't excuse guessing at others.
+func recordLogin(r *http.Request, username string, failed bool) {
+	reqLog := GetLogger(r)
+	keys := loginKeys(r, username)
+
+	if !failed {
+		if err := ratelimit.Succeed(keys[1]); err != nil {
+			reqLog.Error("Failed to reset login failures", zap.Error(err))
+		}
+
+		return
+	}
+
+	for _, key := range keys {
+		lockout, err := ratelimit.Fail(key)
+		if err != nil {
+			reqLog.Error("Failed to count failed login", zap.String("key", key), zap.Error(err))
+		} else if lockout > 0 {
+			reqLog.Warn("Locked out after failed logins", zap.String("key", key), zap.Duration("lockout", lockout))
+		}
+	}
+}
@@ -1,15 +1,24 @@
 package v1
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/carsonmyers/bublar-assignment/errors"
 	"go.uber.org/zap"
 )
 
-// DecodeRequest unmarshals a JSON request body into a supplied interface
+// DecodeRequest unmarshals a request body into a supplied interface. Bodies
+// are JSON unless the Content-Type says they are protobuf or MessagePack, in
+// which case they are converted to JSON first. Malformed JSON, fields the
+// target doesn't have, and anything after the JSON value are rejected as
+// invalid requests.
 func DecodeRequest(w http.ResponseWriter, r *http.Request, target interface{}) error {
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -18,12 +27,119 @@ func DecodeRequest(w http.ResponseWriter, r *http.Request, target interface{}) e
 		return err
 	}
 
-	err = json.Unmarshal(data, target)
-	if err != nil {
-		log.Error("Failed to unmarshal request body", zap.Error(err))
-		FromError(errors.EInternal.NewError("Error reading request")).Write(w)
+	data, e := toJSON(requestType(r), data)
+	if e != nil {
+		log.Debug("Invalid request body", zap.Error(e))
+		FromError(e).Write(w)
+		return e
+	}
+
+	if err := decodeJSON(data, target); err != nil {
+		log.Debug("Invalid request body", zap.Error(err))
+		FromError(err).Write(w)
 		return err
 	}
 
 	return nil
 }
+
+func decodeJSON(data []byte, target interface{}) *errors.Error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return errors.EInvalidRequest.NewError("request body is required")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(target); err != nil {
+		switch e := err.(type) {
+		case *json.SyntaxError:
+			return errors.EInvalidRequest.NewErrorf("malformed JSON at offset %d: %s", e.Offset, e)
+		case *json.UnmarshalTypeError:
+			return errors.EInvalidRequest.NewErrorf("%s must be %s", e.Field, e.Type).WithContext(e.Field)
+		default:
+			if field := strings.TrimPrefix(err.Error(), "json: unknown field "); field != err.Error() {
+				field = strings.Trim(field, "\"")
+				return errors.EInvalidRequest.NewErrorf("unknown field \"%s\"", field).WithContext(field)
+			}
+
+			return errors.EInvalidRequest.NewErrorf("malformed JSON: %s", err)
+		}
+	}
+
+	// anything but the end of the body is left over, including a stray closing
+	// bracket which More would pass over
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.EInvalidRequest.NewError("unexpected data after JSON value")
+	}
+
+	return nil
+}
+
+// DecodeBoolParam reads a true/false query string parameter, which is false if
+// it is missing, writing an error response if it is invalid
+func DecodeBoolParam(w http.ResponseWriter, r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		err := errors.EInvalidRequest.NewErrorf("invalid %s flag \"%s\"", name, value).WithContext(name)
+		FromError(err).Write(w)
+		return false, err
+	}
+
+	return b, nil
+}
+
+// ListParams - paging parameters taken from the query string of a list request
+type ListParams struct {
+	Limit  int32
+	After  string
+	Sort   string
+	Prefix string
+}
+
+// DecodeListParams reads the paging parameters of a list request, writing an
+// error response if they are invalid
+func DecodeListParams(w http.ResponseWriter, r *http.Request) (*ListParams, error) {
+	query := r.URL.Query()
+	params := &ListParams{
+		After:  query.Get("after"),
+		Sort:   query.Get("sort"),
+		Prefix: query.Get("prefix"),
+	}
+
+	if limit := query.Get("limit"); len(limit) > 0 {
+		n, err := strconv.ParseInt(limit, 10, 32)
+		if err != nil || n < 0 {
+			err := errors.EInvalidRequest.NewErrorf("invalid limit \"%s\"", limit).WithContext("limit")
+			FromError(err).Write(w)
+			return nil, err
+		}
+
+		params.Limit = int32(n)
+	}
+
+	return params, nil
+}
+
+// DecodeTimeParam reads an RFC 3339 time from the query string, which is zero
+// if it is missing, writing an error response if it is invalid
+func DecodeTimeParam(w http.ResponseWriter, r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		err := errors.EInvalidRequest.NewErrorf("invalid %s time \"%s\" (expected RFC 3339)", name, value).WithContext(name)
+		FromError(err).Write(w)
+		return time.Time{}, err
+	}
+
+	return t, nil
+}
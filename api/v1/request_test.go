@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		ok   bool
+	}{
+		{"object", `{"x": 1, "y": 2}`, true},
+		{"trailing whitespace", "{\"x\": 1}\n\t ", true},
+		{"empty", "", false},
+		{"whitespace", "  \n", false},
+		{"malformed", `{"x": }`, false},
+		{"wrong type", `{"x": "one"}`, false},
+		{"unknown field", `{"z": 1}`, false},
+		{"second value", `{"x": 1} {"y": 2}`, false},
+		{"trailing brace", `{"x": 1}}`, false},
+		{"trailing bracket", `{"x": 1}]`, false},
+		{"trailing garbage", `{"x": 1} x`, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var req moveRequest
+			err := decodeJSON([]byte(c.body), &req)
+			if c.ok && err != nil {
+				t.Errorf("got error %v, want none", err)
+			} else if !c.ok && err == nil {
+				t.Errorf("decoded %+v, want an error", req)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -28,6 +27,7 @@ type Response struct {
 	Status     ResponseStatus  `json:"status"`
 	Problems   []*errors.Error `json:"problems"`
 	Data       interface{}     `json:"data"`
+	Next       string          `json:"next,omitempty"`
 	statusCode int
 }
 
@@ -48,6 +48,16 @@ func FromError(err *errors.Error) *Response {
 	}
 }
 
+// FromRPCError creates a response from an error returned by an RPC service,
+// keeping the kind of error the service returned
+func FromRPCError(err error) *Response {
+	if e, ok := err.(*errors.Error); ok {
+		return FromError(e)
+	}
+
+	return FromError(errors.ERPC.NewError(err))
+}
+
 // FromData creates a successful response from a data struct
 func FromData(data interface{}) *Response {
 	return &Response{
@@ -70,6 +80,15 @@ func (r *Response) AddError(err *errors.Error) *Response {
 	return r
 }
 
+// AddErrors adds several problems to the response at once
+func (r *Response) AddErrors(errs []*errors.Error) *Response {
+	for _, err := range errs {
+		r.AddError(err)
+	}
+
+	return r
+}
+
 // SetData sets the payload data for the response, upgrading its status if it was earlier erroneous
 func (r *Response) SetData(data interface{}) *Response {
 	r.Data = data
@@ -80,6 +99,12 @@ func (r *Response) SetData(data interface{}) *Response {
 	return r
 }
 
+// SetNext sets the cursor which fetches the next page of a list
+func (r *Response) SetNext(cursor string) *Response {
+	r.Next = cursor
+	return r
+}
+
 // SetStatusCode specifies the status code of the eventual response. If one has
 // been set already, the higher (more specific/erroneous, in general) code applies.
 func (r *Response) SetStatusCode(statusCode int) *Response {
@@ -93,25 +118,29 @@ func (r *Response) SetStatusCode(statusCode int) *Response {
 const serializeError = `{
 	"status": "error",
 	"problems": [{
+		"code": "INTERNAL",
 		"ctx": "",
-		"errorType": "E_INTERNAL",
-		"message": "Error generating response",
+		"kind": "internal error",
+		"message": "Error generating response"
 	}],
 	"data": null
 }`
 
-func (r *Response) serialize() []byte {
-	data, err := json.Marshal(r)
+// serialize - encode the response in the negotiated format, falling back to
+// a JSON error if it can't be encoded. Returns the format used.
+func (r *Response) serialize(contentType string) ([]byte, string) {
+	data, err := r.encode(contentType)
 	if err != nil {
-		log.Error("Failed to serialize response", zap.Error(err))
+		log.Error("Failed to serialize response", zap.String("contentType", contentType), zap.Error(err))
 		r.SetStatusCode(http.StatusInternalServerError)
-		return []byte(serializeError)
+		return []byte(serializeError), ContentTypeJSON
 	}
 
-	return data
+	return data, contentType
 }
 
-// Write sends the response to a responseWriter
+// Write sends the response to a responseWriter, in the format negotiated by
+// NegotiateMiddleware or JSON
 func (r *Response) Write(w http.ResponseWriter) error {
 	statusCode := http.StatusOK
 	if r.statusCode == 0 {
@@ -125,8 +154,8 @@ func (r *Response) Write(w http.ResponseWriter) error {
 		statusCode = r.statusCode
 	}
 
-	data := r.serialize()
-	w.Header().Add("Content-Type", "application/json")
+	data, contentType := r.serialize(responseType(w))
+	w.Header().Add("Content-Type", contentType)
 	w.WriteHeader(statusCode)
 	_, writeErr := w.Write(data)
 
@@ -15,9 +15,13 @@ var log = logger.GetLogger()
 func Init(r *mux.Router) {
 	r.Use(RIDMiddleware())
 	r.Use(LoggingMiddleware)
-	r.Use(authMiddleware)
+	r.Use(AuthMiddleware)
+	r.Use(AuditMiddleware("v1"))
+	r.Use(NegotiateMiddleware)
+	r.Use(IdempotencyMiddleware)
 
 	r.HandleFunc("/ping", pingHandler).Methods("POST")
+	r.HandleFunc("/openapi.json", openAPIHandler(r)).Methods("GET")
 
 	conf := configure.GetAPI()
 
@@ -39,13 +43,25 @@ func initAdminRoutes(conf *configure.APIConfig, base *mux.Router) {
 	r.HandleFunc("/players/{id}", createPlayerHandler).Methods("PUT")
 	r.HandleFunc("/players/{id}", updatePlayerHandler).Methods("PATCH")
 	r.HandleFunc("/players/{id}", deletePlayerHandler).Methods("DELETE")
+	r.HandleFunc("/players/{id}/restore", restorePlayerHandler).Methods("POST")
 	r.HandleFunc("/players/{id}/move", movePlayerHandler).Methods("POST")
 	r.HandleFunc("/players/{id}/travel", travelPlayerHandler).Methods("POST")
+	r.HandleFunc("/players/{id}/violations", getViolationsHandler).Methods("GET")
 
 	r.HandleFunc("/locations", createLocationHandler).Methods("POST")
 	r.HandleFunc("/locations/{id}", createLocationHandler).Methods("PUT")
 	r.HandleFunc("/locations/{id}", updateLocationHandler).Methods("PATCH")
 	r.HandleFunc("/locations/{id}", deleteLocationHandler).Methods("DELETE")
+	r.HandleFunc("/locations/{id}/restore", restoreLocationHandler).Methods("POST")
+
+	r.HandleFunc("/world", exportWorldHandler).Methods("GET")
+	r.HandleFunc("/world", importWorldHandler).Methods("POST")
+	r.HandleFunc("/snapshot", snapshotHandler).Methods("GET")
+	r.HandleFunc("/snapshot", restoreSnapshotHandler).Methods("POST")
+
+	r.HandleFunc("/audit", getAuditHandler).Methods("GET")
+
+	r.HandleFunc("/batch", batchHandler(base)).Methods("POST")
 }
 
 func initClientRoutes(base *mux.Router) {
@@ -60,11 +76,13 @@ func initClientRoutes(base *mux.Router) {
 	r.HandleFunc("/locations/{id}", getLocationHandler).Methods("GET")
 	r.HandleFunc("/locations/{id}/players", getPlayersInLocationHandler).Methods("GET")
 
+	r.HandleFunc("/events", eventsHandler).Methods("GET")
+
 	r.HandleFunc("/player", getPlayerHandler).Methods("GET")
 	r.HandleFunc("/player", updatePlayerHandler).Methods("PATCH")
 	r.HandleFunc("/player", deletePlayerHandler).Methods("DELETE")
-	r.HandleFunc("/player/move", movePlayerHandler).Methods("POST")
-	r.HandleFunc("/player/travel", travelPlayerHandler).Methods("POST")
+	r.Handle("/player/move", RateLimitMiddleware(http.HandlerFunc(movePlayerHandler))).Methods("POST")
+	r.Handle("/player/travel", RateLimitMiddleware(http.HandlerFunc(travelPlayerHandler))).Methods("POST")
 }
 
 func notFoundHandler(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,97 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/world"
+)
+
+func exportWorldHandler(w http.ResponseWriter, r *http.Request) {
+	players, err := DecodeBoolParam(w, r, "players")
+	if err != nil {
+		return
+	}
+
+	doc, err := world.Export(players)
+	if err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
+	FromData(doc).Write(w)
+}
+
+func importWorldHandler(w http.ResponseWriter, r *http.Request) {
+	upsert, err := DecodeBoolParam(w, r, "upsert")
+	if err != nil {
+		return
+	}
+
+	dryRun, err := DecodeBoolParam(w, r, "dryRun")
+	if err != nil {
+		return
+	}
+
+	format := world.FormatJSON
+	if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+		format = world.FormatYAML
+	}
+
+	doc, err := world.Decode(r.Body, format)
+	if err != nil {
+		if e, ok := err.(*errors.Error); ok {
+			FromError(e).Write(w)
+		} else {
+			FromError(errors.EInternal.NewError("Error reading request")).Write(w)
+		}
+
+		return
+	}
+
+	report, problems := world.Import(r.Context(), doc, &world.Options{
+		Upsert: upsert,
+		DryRun: dryRun,
+	})
+
+	res := NewResponse().AddErrors(problems)
+
+	if report != nil {
+		res.SetData(report)
+	}
+
+	res.Write(w)
+}
+
+func snapshotHandler(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := world.TakeSnapshot()
+	if err != nil {
+		FromRPCError(err).Write(w)
+		return
+	}
+
+	FromData(snapshot).Write(w)
+}
+
+func restoreSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	clear, err := DecodeBoolParam(w, r, "clear")
+	if err != nil {
+		return
+	}
+
+	var snapshot world.Snapshot
+	if err := DecodeRequest(w, r, &snapshot); err != nil {
+		return
+	}
+
+	report, problems := world.RestoreSnapshot(r.Context(), &snapshot, clear)
+
+	res := NewResponse().AddErrors(problems)
+
+	if report != nil {
+		res.SetData(report)
+	}
+
+	res.Write(w)
+}
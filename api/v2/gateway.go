@@ -0,0 +1,220 @@
+package v2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+
+	v1 "github.com/carsonmyers/bublar-assignment/api/v1"
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	_ "github.com/carsonmyers/bublar-assignment/proto" // registers the service descriptors
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+)
+
+var log = logger.GetLogger()
+
+// BasePath - the prefix of every path in the HTTP annotations of the services
+const BasePath = "/v2"
+
+// protoFile - the file the service descriptors are registered under
+const protoFile = "proto/services.proto"
+
+// conns - connect to the service of each RPC, by service name
+var conns = map[string]func() (*grpc.ClientConn, error){
+	"Players": func() (*grpc.ClientConn, error) {
+		client, err := connect.Players()
+		if err != nil {
+			return nil, err
+		}
+
+		return client.Conn(), nil
+	},
+	"Locations": func() (*grpc.ClientConn, error) {
+		client, err := connect.Locations()
+		if err != nil {
+			return nil, err
+		}
+
+		return client.Conn(), nil
+	},
+}
+
+// Init - initialize a router with a route for every RPC which has an HTTP
+// annotation. Admin routes are only added if the admin API is enabled.
+func Init(r *mux.Router) {
+	r.Use(v1.RIDMiddleware())
+	r.Use(v1.LoggingMiddleware)
+	r.Use(v1.AuthMiddleware)
+	r.Use(v1.AuditMiddleware("v2"))
+
+	methods, err := loadMethods()
+	if err != nil {
+		log.Error("Failed to load HTTP annotations, v2 routes are unavailable", zap.Error(err))
+	}
+
+	conf := configure.GetAPI()
+	for _, m := range methods {
+		if strings.HasPrefix(m.path, "/admin/") && !conf.EnableAdmin {
+			continue
+		}
+
+		r.Handle(m.path, m).Methods(m.verb)
+		log.Debug("Added transcoded route", zap.String("method", m.verb), zap.String("path", BasePath+m.path), zap.String("rpc", m.name))
+	}
+
+	r.PathPrefix("/").HandlerFunc(notFoundHandler)
+
+	log.Info("Initialized v2 router", zap.Int("routes", len(methods)))
+}
+
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	v1.FromError(errors.ENotFound.NewError("endpoint does not exist")).Write(w)
+}
+
+// loadMethods - read the HTTP annotations of every service method from the
+// registered descriptors
+func loadMethods() ([]*method, error) {
+	file, err := decodeFile(proto.FileDescriptor(protoFile))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make(map[string]*descriptor.DescriptorProto)
+	for _, msg := range file.GetMessageType() {
+		messages[fmt.Sprintf(".%s.%s", file.GetPackage(), msg.GetName())] = msg
+	}
+
+	methods := make([]*method, 0)
+	for _, svc := range file.GetService() {
+		conn, ok := conns[svc.GetName()]
+		if !ok {
+			log.Warn("No connection for service, skipping its HTTP annotations", zap.String("service", svc.GetName()))
+			continue
+		}
+
+		for _, md := range svc.GetMethod() {
+			if md.GetOptions() == nil || !proto.HasExtension(md.GetOptions(), annotations.E_Http) {
+				continue
+			}
+
+			ext, err := proto.GetExtension(md.GetOptions(), annotations.E_Http)
+			if err != nil {
+				return nil, err
+			}
+
+			name := fmt.Sprintf("/%s.%s/%s", file.GetPackage(), svc.GetName(), md.GetName())
+			m, err := newMethod(name, ext.(*annotations.HttpRule), md, messages)
+			if err != nil {
+				return nil, err
+			}
+
+			m.conn = conn
+			methods = append(methods, m)
+		}
+	}
+
+	return methods, nil
+}
+
+func decodeFile(gz []byte) (*descriptor.FileDescriptorProto, error) {
+	if gz == nil {
+		return nil, fmt.Errorf("%s is not registered", protoFile)
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var file descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+func newMethod(name string, rule *annotations.HttpRule, md *descriptor.MethodDescriptorProto, messages map[string]*descriptor.DescriptorProto) (*method, error) {
+	var verb, path string
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		verb, path = http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Put:
+		verb, path = http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Post:
+		verb, path = http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Delete:
+		verb, path = http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		verb, path = http.MethodPatch, pattern.Patch
+	default:
+		return nil, fmt.Errorf("%s: unsupported HTTP pattern", name)
+	}
+
+	if !strings.HasPrefix(path, BasePath+"/") {
+		return nil, fmt.Errorf("%s: path %s is not under %s", name, path, BasePath)
+	}
+
+	input, ok := messages[md.GetInputType()]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown input type %s", name, md.GetInputType())
+	}
+
+	output, ok := messages[md.GetOutputType()]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown output type %s", name, md.GetOutputType())
+	}
+
+	m := &method{
+		name:         name,
+		verb:         verb,
+		path:         strings.TrimPrefix(path, BasePath),
+		body:         rule.GetBody(),
+		responseBody: rule.GetResponseBody(),
+		stream:       md.GetServerStreaming(),
+		input:        input,
+		inputType:    proto.MessageType(strings.TrimPrefix(md.GetInputType(), ".")),
+		outputType:   proto.MessageType(strings.TrimPrefix(md.GetOutputType(), ".")),
+		messages:     messages,
+	}
+
+	if md.GetClientStreaming() {
+		return nil, fmt.Errorf("%s: client streams can't be transcoded", name)
+	}
+
+	if m.inputType == nil || m.outputType == nil {
+		return nil, fmt.Errorf("%s: message types are not registered", name)
+	}
+
+	if len(m.body) > 0 && m.body != "*" && field(input, m.body) == nil {
+		return nil, fmt.Errorf("%s: body field %s does not exist", name, m.body)
+	}
+
+	if len(m.responseBody) > 0 && field(output, m.responseBody) == nil {
+		return nil, fmt.Errorf("%s: response body field %s does not exist", name, m.responseBody)
+	}
+
+	return m, nil
+}
+
+// newMessage - create an empty message of a registered type
+func newMessage(t reflect.Type) proto.Message {
+	return reflect.New(t.Elem()).Interface().(proto.Message)
+}
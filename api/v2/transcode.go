@@ -0,0 +1,319 @@
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/carsonmyers/bublar-assignment/api/v1"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// method - an RPC exposed as an HTTP route by its annotation
+type method struct {
+	name         string
+	verb         string
+	path         string
+	body         string
+	responseBody string
+	stream       bool
+	input        *descriptor.DescriptorProto
+	inputType    reflect.Type
+	outputType   reflect.Type
+	messages     map[string]*descriptor.DescriptorProto
+	conn         func() (*grpc.ClientConn, error)
+}
+
+var marshaler = jsonpb.Marshaler{EmitDefaults: true}
+
+// ServeHTTP - build the request message from the body, path and query string,
+// call the RPC, and write its response in the v1 response envelope
+func (m *method) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	in, e := m.decode(r)
+	if e != nil {
+		v1.FromError(e).Write(w)
+		return
+	}
+
+	conn, err := m.conn()
+	if err != nil {
+		v1.FromError(errors.ERPCConnection.NewError(err)).Write(w)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	if m.stream {
+		res, next, err := m.invokeStream(ctx, conn, in)
+		if err != nil {
+			v1.FromRPCError(err).Write(w)
+			return
+		}
+
+		v1.FromData(json.RawMessage(res)).SetNext(next).Write(w)
+		return
+	}
+
+	out := newMessage(m.outputType)
+	if err := conn.Invoke(ctx, m.name, in, out); err != nil {
+		v1.FromRPCError(err).Write(w)
+		return
+	}
+
+	if versioned, ok := out.(interface{ GetVersion() uint64 }); ok && versioned.GetVersion() > 0 {
+		v1.SetETag(w, versioned.GetVersion())
+	}
+
+	res, err := m.encode(out)
+	if err != nil {
+		v1.FromError(errors.EInternal.NewError(err)).Write(w)
+		return
+	}
+
+	v1.FromData(json.RawMessage(res)).Write(w)
+}
+
+// decode - assemble the request message. The body is mapped onto the whole
+// message or one field of it, then path and query parameters are set on top.
+func (m *method) decode(r *http.Request) (proto.Message, *errors.Error) {
+	obj := make(map[string]interface{})
+
+	if len(m.body) > 0 {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, errors.EInternal.NewError("Error reading request")
+		}
+
+		var body interface{}
+		if len(bytes.TrimSpace(data)) > 0 {
+			if err := json.Unmarshal(data, &body); err != nil {
+				return nil, errors.EInvalidRequest.NewErrorf("malformed JSON: %s", err)
+			}
+		}
+
+		if m.body == "*" {
+			if body != nil {
+				o, ok := body.(map[string]interface{})
+				if !ok {
+					return nil, errors.EInvalidRequest.NewError("request body must be an object")
+				}
+
+				obj = o
+			}
+		} else if body != nil {
+			obj[m.body] = body
+		}
+	}
+
+	for name, value := range mux.Vars(r) {
+		if e := m.set(obj, m.input, strings.Split(name, "."), value); e != nil {
+			return nil, e
+		}
+	}
+
+	// with a `*` body every field comes from the body
+	if m.body != "*" {
+		for name, values := range r.URL.Query() {
+			for _, value := range values {
+				if e := m.set(obj, m.input, strings.Split(name, "."), value); e != nil {
+					return nil, e
+				}
+			}
+		}
+	}
+
+	if e := m.setVersion(obj, r); e != nil {
+		return nil, e
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.EInternal.NewError(err)
+	}
+
+	in := newMessage(m.inputType)
+	if err := jsonpb.Unmarshal(bytes.NewReader(data), in); err != nil {
+		return nil, errors.EInvalidRequest.NewErrorf("invalid request: %s", err)
+	}
+
+	return in, nil
+}
+
+// setVersion - put the version from an If-Match header into the message the
+// body maps to, if it has a version
+func (m *method) setVersion(obj map[string]interface{}, r *http.Request) *errors.Error {
+	version, e := v1.IfMatch(r)
+	if e != nil || version == 0 {
+		return e
+	}
+
+	path := []string{"version"}
+	if len(m.body) > 0 && m.body != "*" {
+		path = []string{m.body, "version"}
+	}
+
+	return m.set(obj, m.input, path, strconv.FormatUint(version, 10))
+}
+
+// set - set a field of the request from a string parameter, converting it to
+// the field's type. Nested fields are set through a path of field names.
+func (m *method) set(obj map[string]interface{}, msg *descriptor.DescriptorProto, path []string, value string) *errors.Error {
+	f := field(msg, path[0])
+	if f == nil {
+		return errors.EInvalidRequest.NewErrorf("unknown parameter \"%s\"", path[0]).WithContext(path[0])
+	}
+
+	name := f.GetName()
+	if len(path) > 1 {
+		child, ok := m.messages[f.GetTypeName()]
+		if f.GetType() != descriptor.FieldDescriptorProto_TYPE_MESSAGE || !ok {
+			return errors.EInvalidRequest.NewErrorf("\"%s\" has no fields", name).WithContext(name)
+		}
+
+		sub, ok := obj[name].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			obj[name] = sub
+		}
+
+		return m.set(sub, child, path[1:], value)
+	}
+
+	converted, e := convert(f, value)
+	if e != nil {
+		return e
+	}
+
+	if f.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		values, _ := obj[name].([]interface{})
+		obj[name] = append(values, converted)
+	} else {
+		obj[name] = converted
+	}
+
+	return nil
+}
+
+func convert(f *descriptor.FieldDescriptorProto, value string) (interface{}, *errors.Error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("invalid %s flag \"%s\"", f.GetName(), value).WithContext(f.GetName())
+		}
+
+		return b, nil
+	case descriptor.FieldDescriptorProto_TYPE_STRING, descriptor.FieldDescriptorProto_TYPE_BYTES, descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return value, nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return nil, errors.EInvalidRequest.NewErrorf("\"%s\" can't be set from a parameter", f.GetName()).WithContext(f.GetName())
+	default:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("invalid %s \"%s\"", f.GetName(), value).WithContext(f.GetName())
+		}
+
+		return json.Number(value), nil
+	}
+}
+
+// field - find a field of a message by its proto or JSON name
+func field(msg *descriptor.DescriptorProto, name string) *descriptor.FieldDescriptorProto {
+	for _, f := range msg.GetField() {
+		if f.GetName() == name || f.GetJsonName() == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// invokeStream - call a server streaming RPC, collecting its messages into
+// an array. The cursor of the next page is read from the `next` trailer.
+func (m *method) invokeStream(ctx context.Context, conn *grpc.ClientConn, in proto.Message) ([]byte, string, error) {
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, m.name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := stream.SendMsg(in); err != nil {
+		return nil, "", err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, "", err
+	}
+
+	items := make([]json.RawMessage, 0)
+	for {
+		out := newMessage(m.outputType)
+		if err := stream.RecvMsg(out); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, "", err
+		}
+
+		item, err := m.encode(out)
+		if err != nil {
+			return nil, "", errors.EInternal.NewError(err)
+		}
+
+		items = append(items, item)
+	}
+
+	res, err := json.Marshal(items)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return res, nextCursor(stream.Trailer()), nil
+}
+
+// encode - marshal a response message, or just the field named as the
+// response body
+func (m *method) encode(out proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, out); err != nil {
+		log.Error("Failed to marshal RPC response", zap.String("rpc", m.name), zap.Error(err))
+		return nil, err
+	}
+
+	if len(m.responseBody) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		return nil, err
+	}
+
+	for name, value := range fields {
+		if name == m.responseBody || strings.EqualFold(name, strings.Replace(m.responseBody, "_", "", -1)) {
+			return value, nil
+		}
+	}
+
+	return []byte("null"), nil
+}
+
+func nextCursor(trailer metadata.MD) string {
+	if next := trailer.Get("next"); len(next) > 0 {
+		return next[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// metadata keys which carry the caller of an RPC
+const (
+	actorKey     = "audit-actor"
+	sourceKey    = "audit-source"
+	addressKey   = "audit-address"
+	requestIDKey = "request-id"
+)
+
+// Caller - who made a request, and through which API
+type Caller struct {
+	// Actor - the logged in player, or empty if nobody was logged in
+	Actor string
+
+	// Source - the name of the API the request was made through
+	Source string
+
+	// Address - the network address the request came from
+	Address string
+
+	// RequestID - the ID of the API request which led to the call
+	RequestID string
+}
+
+// WithCaller - attach a caller to a context, to be sent with every RPC made
+// with it. Any caller already attached is replaced.
+func WithCaller(ctx context.Context, caller *Caller) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	md.Set(actorKey, caller.Actor)
+	md.Set(sourceKey, caller.Source)
+	md.Set(addressKey, caller.Address)
+	md.Set(requestIDKey, caller.RequestID)
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// CallerFrom - get the caller of an RPC from its context. The metadata is
+// trusted as sent: the services don't authenticate their callers, so anything
+// which can reach them can claim to be any actor from any address. Only the
+// APIs should be able to reach the services.
+func CallerFrom(ctx context.Context) *Caller {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return &Caller{}
+	}
+
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+
+		return ""
+	}
+
+	return &Caller{
+		Actor:     first(actorKey),
+		Source:    first(sourceKey),
+		Address:   first(addressKey),
+		RequestID: first(requestIDKey),
+	}
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"go.uber.org/zap"
+)
+
+type gormStore struct{}
+
+// NewGormStore - create an audit log backed by an SQL database
+func NewGormStore() Store {
+	return &gormStore{}
+}
+
+func (s *gormStore) Append(entry *Entry) error {
+	db, err := connect.Database()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	if err := db.Create(entry).Error; err != nil {
+		log.Error("Failed to append audit entry", zap.String("action", entry.Action), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) List(filter *Filter, query *paging.Query) ([]*Entry, error) {
+	after, err := before(query)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	q := db.Model(&Entry{}).Where(&Entry{
+		Actor:     filter.Actor,
+		Action:    filter.Action,
+		Target:    filter.Target,
+		RequestID: filter.RequestID,
+	})
+
+	if after != 0 {
+		q = q.Where("id < ?", after)
+	}
+
+	if !filter.Since.IsZero() {
+		q = q.Where("created_at >= ?", filter.Since)
+	}
+
+	if !filter.Until.IsZero() {
+		q = q.Where("created_at < ?", filter.Until)
+	}
+
+	var entries []*Entry
+	if err := q.Order("id DESC").Limit(query.Limit).Find(&entries).Error; err != nil {
+		log.Error("Error fetching audit entries", zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"sync"
+
+	"github.com/carsonmyers/bublar-assignment/paging"
+)
+
+type memoryStore struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// NewMemoryStore - create an audit log held in process memory
+func NewMemoryStore() Store {
+	return &memoryStore{
+		entries: make([]Entry, 0),
+	}
+}
+
+func (s *memoryStore) Append(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.ID = uint(len(s.entries) + 1)
+	s.entries = append(s.entries, *entry)
+	return nil
+}
+
+func (s *memoryStore) List(filter *Filter, query *paging.Query) ([]*Entry, error) {
+	after, err := before(query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]*Entry, 0)
+	for i := len(s.entries) - 1; i >= 0 && len(entries) < query.Limit; i-- {
+		entry := s.entries[i]
+		if after != 0 && entry.ID >= after {
+			continue
+		}
+
+		if filter.matches(&entry) {
+			entries = append(entries, &entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// matches - whether an entry passes a filter
+func (f *Filter) matches(entry *Entry) bool {
+	switch {
+	case len(f.Actor) > 0 && entry.Actor != f.Actor:
+		return false
+	case len(f.Action) > 0 && entry.Action != f.Action:
+		return false
+	case len(f.Target) > 0 && entry.Target != f.Target:
+		return false
+	case len(f.RequestID) > 0 && entry.RequestID != f.RequestID:
+		return false
+	case !f.Since.IsZero() && entry.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !entry.CreatedAt.Before(f.Until):
+		return false
+	}
+
+	return true
+}
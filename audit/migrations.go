@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/migrate"
+	"github.com/jinzhu/gorm"
+)
+
+// migrations - schema history for the audit log, which both services write to
+var migrations = []*migrate.Migration{
+	{
+		Version: 1,
+		Name:    "create_audit_entry",
+		Up: func(tx *gorm.DB) error {
+			type entry struct {
+				ID        uint      `gorm:"primary_key"`
+				CreatedAt time.Time `gorm:"type:timestamp;index"`
+				Actor     string    `gorm:"index"`
+				Source    string
+				Address   string
+				Action    string `gorm:"index"`
+				Target    string `gorm:"index"`
+				RequestID string `gorm:"index"`
+				Before    string `gorm:"type:text"`
+				After     string `gorm:"type:text"`
+			}
+
+			// the other service may have created it first
+			if tx.HasTable(tableName) {
+				return nil
+			}
+
+			return tx.Table(tableName).CreateTable(&entry{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists(tableName).Error
+		},
+	},
+}
@@ -0,0 +1,200 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/carsonmyers/bublar-assignment/migrate"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+// Entry - a change made through a service, and who made it. Entries are only
+// ever added, never changed or removed.
+type Entry struct {
+	ID        uint      `json:"id" gorm:"primary_key"`
+	CreatedAt time.Time `json:"time" gorm:"type:timestamp;index"`
+	Actor     string    `json:"actor" gorm:"index"`
+	Source    string    `json:"source"`
+	Address   string    `json:"address"`
+	Action    string    `json:"action" gorm:"index"`
+	Target    string    `json:"target" gorm:"index"`
+	RequestID string    `json:"requestId" gorm:"index"`
+
+	// Before, After - the JSON of the target before and after the change, or
+	// empty if it didn't exist
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// tableName - the table of the log, which is shared by both services
+const tableName = "audit_entry"
+
+// TableName - the table entries are kept in
+func (Entry) TableName() string {
+	return tableName
+}
+
+// Filter - which entries to list. Empty fields match every entry.
+type Filter struct {
+	Actor     string
+	Action    string
+	Target    string
+	RequestID string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Cursor - get the position of an entry in the log, which is always listed
+// newest first. IDs only increase, so the ID alone orders the log.
+func (e *Entry) Cursor() *paging.Cursor {
+	return &paging.Cursor{Key: strconv.FormatUint(uint64(e.ID), 10)}
+}
+
+// before - the ID which entries on a page must be older than, or 0 for the
+// first page
+func before(query *paging.Query) (uint, error) {
+	if query.After == nil {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(query.After.Key, 10, 64)
+	if err != nil {
+		return 0, errors.EInvalidRequest.NewError("invalid cursor").WithContext("after")
+	}
+
+	return uint(id), nil
+}
+
+// Store - storage for the audit log
+type Store interface {
+	// Append - add an entry to the log
+	Append(entry *Entry) error
+
+	// List - list one page of the entries matching a filter, newest first
+	List(filter *Filter, query *paging.Query) ([]*Entry, error)
+}
+
+var store Store
+
+// SetStore - override the configured storage backend
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore - get the configured storage backend
+func GetStore() Store {
+	if store != nil {
+		return store
+	}
+
+	switch configure.GetStorage().Backend {
+	case configure.BackendMemory:
+		store = NewMemoryStore()
+	default:
+		store = NewGormStore()
+	}
+
+	return store
+}
+
+// appendRetries - how long to wait before each retry of an entry which
+// couldn't be added to the log
+var appendRetries = []time.Duration{50 * time.Millisecond, 250 * time.Millisecond, time.Second}
+
+// Record - add an entry for a change to the log, with the caller of the RPC
+// which made it. The caller is taken from the RPC's metadata as the API sent
+// it, without being checked. An entry which can't be added is retried a few
+// times; the change has already happened, so if it still can't be recorded
+// the failure is logged rather than returned.
+func Record(ctx context.Context, action, target string, before, after interface{}) {
+	caller := CallerFrom(ctx)
+
+	entry := &Entry{
+		CreatedAt: time.Now(),
+		Actor:     caller.Actor,
+		Source:    caller.Source,
+		Address:   caller.Address,
+		Action:    action,
+		Target:    target,
+		RequestID: caller.RequestID,
+		Before:    encode(before),
+		After:     encode(after),
+	}
+
+	err := GetStore().Append(entry)
+	for _, wait := range appendRetries {
+		if err == nil {
+			return
+		}
+
+		log.Warn("Failed to record audit entry, retrying", zap.String("action", action), zap.String("target", target), zap.Duration("wait", wait), zap.Error(err))
+		time.Sleep(wait)
+		err = GetStore().Append(entry)
+	}
+
+	if err != nil {
+		log.Error("Failed to record audit entry", zap.String("action", action), zap.String("target", target), zap.String("requestID", caller.RequestID), zap.Any("entry", entry), zap.Error(err))
+	}
+}
+
+// encode - the JSON of a value, or empty for nil
+func encode(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	payload, err := json.Marshal(value)
+	if err != nil || string(payload) == "null" {
+		return ""
+	}
+
+	return string(payload)
+}
+
+// List - list one page of the log, returning the cursor of the next page
+func List(filter *Filter, query *paging.Query) ([]*Entry, *paging.Cursor, error) {
+	entries, err := GetStore().List(filter, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(entries) < query.Limit {
+		return entries, nil, nil
+	}
+
+	return entries, entries[len(entries)-1].Cursor(), nil
+}
+
+// Migrator - get the schema migrator for the audit log, or nil if it isn't
+// kept in the SQL database
+func Migrator() (*migrate.Migrator, error) {
+	if _, ok := GetStore().(*gormStore); !ok {
+		return nil, nil
+	}
+
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	return migrate.New(db, "audit", migrations), nil
+}
+
+// Migrate - apply all pending migrations for the audit log
+func Migrate() error {
+	m, err := Migrator()
+	if err != nil || m == nil {
+		return err
+	}
+
+	return m.Up()
+}
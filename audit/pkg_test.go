@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/paging"
+)
+
+// flakyStore - a store which fails to append a number of times before
+// appending to a memory store
+type flakyStore struct {
+	Store
+	failures int
+	attempts int
+}
+
+func (s *flakyStore) Append(entry *Entry) error {
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("database is locked")
+	}
+
+	return s.Store.Append(entry)
+}
+
+func TestRecordRetries(t *testing.T) {
+	retries := appendRetries
+	appendRetries = []time.Duration{time.Millisecond, time.Millisecond}
+	defer func() {
+		appendRetries = retries
+		SetStore(nil)
+	}()
+
+	cases := []struct {
+		name     string
+		failures int
+		attempts int
+		recorded bool
+	}{
+		{"first attempt", 0, 1, true},
+		{"after retries", 2, 3, true},
+		{"gives up", 5, 3, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := &flakyStore{Store: NewMemoryStore(), failures: c.failures}
+			SetStore(store)
+
+			Record(context.Background(), "player.delete", "bob", nil, nil)
+
+			if store.attempts != c.attempts {
+				t.Errorf("got %d attempts, want %d", store.attempts, c.attempts)
+			}
+
+			entries, err := store.List(&Filter{}, &paging.Query{Limit: 10})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+
+			if recorded := len(entries) == 1; recorded != c.recorded {
+				t.Errorf("got %d entries, want recorded=%v", len(entries), c.recorded)
+			}
+		})
+	}
+}
@@ -27,12 +27,16 @@ type config struct {
 	API       *configure.APIConfig
 	Locations *configure.LocationsConfig
 	Players   *configure.PlayersConfig
+	Redis     *configure.RedisConfig
+	RateLimit *configure.RateLimitConfig
 }
 
 var defaultConfig = config{
 	API:       &configure.DefaultAPIConfig,
 	Locations: &configure.DefaultLocationsConfig,
 	Players:   &configure.DefaultPlayersConfig,
+	Redis:     &configure.DefaultRedisConfig,
+	RateLimit: &configure.DefaultRateLimitConfig,
 }
 
 func main() {
@@ -40,6 +44,8 @@ func main() {
 	envconfig.MustProcess("api", conf.API)
 	envconfig.MustProcess("locations", conf.Locations)
 	envconfig.MustProcess("players", conf.Players)
+	envconfig.MustProcess("redis", conf.Redis)
+	envconfig.MustProcess("ratelimit", conf.RateLimit)
 
 	confJSON, _ := json.MarshalIndent(conf, "", "\t")
 	log.Info(fmt.Sprintf("Configuration: %s", confJSON))
@@ -73,6 +79,8 @@ func start(conf config) {
 	configure.API(conf.API)
 	configure.Players(conf.Players)
 	configure.Locations(conf.Locations)
+	configure.Redis(conf.Redis)
+	configure.RateLimit(conf.RateLimit)
 
 	server = &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", conf.API.Host, conf.API.Port),
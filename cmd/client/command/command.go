@@ -83,7 +83,7 @@ func (c *Command) Execute() error {
 	}
 
 	if err := c.main(c); err != nil {
-		return fmt.Errorf("%s: %s", c.name, err)
+		return fmt.Errorf("%s: %s", c.name, Describe(err))
 	}
 
 	return nil
@@ -152,6 +152,20 @@ func (c *Command) Args() []string {
 	return c.args
 }
 
+// IsSet - whether a flag was given on the command line
+func (c *Command) IsSet(name string) bool {
+	var set bool
+	if c.flags != nil {
+		c.flags.Visit(func(f *flag.Flag) {
+			if f.Name == name {
+				set = true
+			}
+		})
+	}
+
+	return set
+}
+
 // Next - get the next command
 func (c *Command) Next() (*Command, error) {
 	return c.nextFrom(c.next)
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+)
+
+// Print - write a result to stdout as indented JSON
+func Print(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	return enc.Encode(v)
+}
+
+// PrintNext - tell the user how to fetch the next page of a list, if there is one
+func PrintNext(next string) {
+	if len(next) > 0 {
+		fmt.Fprintf(os.Stderr, "More results: -after %s\n", next)
+	}
+}
+
+// PrintProblems - write the problems reported by the API to stderr
+func PrintProblems(problems []*errors.Error) {
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, Describe(problem))
+	}
+}
+
+// Describe - explain an error for the user, including its code and context if
+// it is a problem reported by the API
+func Describe(err error) string {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		return err.Error()
+	}
+
+	description := fmt.Sprintf("%s: %s", e.Kind.Code(), e.Kind)
+	if msg := e.Error(); len(msg) > 0 {
+		description += ": " + msg
+	}
+
+	if len(e.Ctx) > 0 {
+		description += fmt.Sprintf(" (%s)", e.Ctx)
+	}
+
+	return description
+}
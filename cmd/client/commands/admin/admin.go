@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/world"
+)
+
+// Command - admin subcommand
+func Command() *command.Command {
+	cmd := command.New("admin", "Administer the game world", nil, run)
+	cmd.AddCommand(exportCommand())
+	cmd.AddCommand(importCommand())
+	cmd.AddCommand(tiledCommand())
+	cmd.AddCommand(snapshotCommand())
+	cmd.AddCommand(restoreCommand())
+	cmd.AddCommand(batchCommand())
+	cmd.AddCommand(auditCommand())
+
+	return cmd
+}
+
+func run(cmd *command.Command) error {
+	next, err := cmd.Next()
+	if err != nil {
+		return err
+	}
+
+	if next == nil {
+		fmt.Print(cmd.Help())
+		return nil
+	}
+
+	return next.Execute()
+}
+
+// checkProblems - show the problems reported with an import or restore,
+// failing if they stopped anything from being written
+func checkProblems(written bool, problems []*errors.Error) error {
+	command.PrintProblems(problems)
+
+	if !written {
+		return fmt.Errorf("nothing was changed (%d problems)", len(problems))
+	}
+
+	return nil
+}
+
+// fileFormat - pick a world document format, from the file extension if it
+// isn't given
+func fileFormat(format, file string) string {
+	if len(format) > 0 {
+		return format
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return world.FormatYAML
+	default:
+		return world.FormatJSON
+	}
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+)
+
+var auditOpts struct {
+	limit   int
+	after   string
+	actor   string
+	action  string
+	target  string
+	request string
+	since   string
+	until   string
+}
+
+func auditCommand() *command.Command {
+	flagSet := flag.NewFlagSet("audit", flag.ExitOnError)
+	flagSet.IntVar(&auditOpts.limit, "limit", 0, "Maximum number of entries to list (server default if omitted)")
+	flagSet.StringVar(&auditOpts.after, "after", "", "Cursor returned as `next` by the previous page")
+	flagSet.StringVar(&auditOpts.actor, "actor", "", "Only list changes made by this player")
+	flagSet.StringVar(&auditOpts.action, "action", "", "Only list this kind of change, like player.delete")
+	flagSet.StringVar(&auditOpts.target, "target", "", "Only list changes to this player or location")
+	flagSet.StringVar(&auditOpts.request, "request", "", "Only list changes made by the request with this ID")
+	flagSet.StringVar(&auditOpts.since, "since", "", "Only list changes made at or after this RFC 3339 time, or this long ago (like 2h)")
+	flagSet.StringVar(&auditOpts.until, "until", "", "Only list changes made before this RFC 3339 time, or this long ago")
+
+	return command.New("audit", "List changes made to the game world, newest first", flagSet, runAudit)
+}
+
+func runAudit(cmd *command.Command) error {
+	since, err := parseTime("since", auditOpts.since)
+	if err != nil {
+		return err
+	}
+
+	until, err := parseTime("until", auditOpts.until)
+	if err != nil {
+		return err
+	}
+
+	entries, next, err := client.New(connect.API()).Audit(&client.AuditQuery{
+		ListQuery: client.ListQuery{
+			Limit: auditOpts.limit,
+			After: auditOpts.after,
+		},
+		Actor:     auditOpts.actor,
+		Action:    auditOpts.action,
+		Target:    auditOpts.target,
+		RequestID: auditOpts.request,
+		Since:     since,
+		Until:     until,
+	})
+	if err != nil {
+		return err
+	}
+
+	command.PrintNext(next)
+	return command.Print(entries)
+}
+
+// parseTime - read a time flag, which is either an RFC 3339 time or a
+// duration before now
+func parseTime(name, value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid -%s \"%s\" (expected an RFC 3339 time or a duration)", name, value)
+	}
+
+	return time.Now().Add(-d), nil
+}
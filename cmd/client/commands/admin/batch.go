@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+)
+
+var batchOpts struct {
+	file            string
+	continueOnError bool
+}
+
+func batchCommand() *command.Command {
+	flagSet := flag.NewFlagSet("batch", flag.ExitOnError)
+	flagSet.StringVar(&batchOpts.file, "f", "", "JSON file with an array of operations ({\"method\", \"path\", \"body\"})")
+	flagSet.BoolVar(&batchOpts.continueOnError, "continue", false, "Run every operation, instead of stopping at the first which fails")
+
+	return command.New("batch", "Run several API operations in one request", flagSet, runBatch)
+}
+
+func runBatch(cmd *command.Command) error {
+	f, err := os.Open(batchOpts.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var ops []*client.BatchOperation
+	if err := json.NewDecoder(f).Decode(&ops); err != nil {
+		return err
+	}
+
+	results, problems, err := client.New(connect.API()).Batch(ops, batchOpts.continueOnError)
+	if err != nil {
+		return err
+	}
+
+	if err := checkProblems(results != nil, problems); err != nil {
+		return err
+	}
+
+	return command.Print(results)
+}
@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/world"
+)
+
+var exportOpts struct {
+	file    string
+	format  string
+	players bool
+}
+
+func exportCommand() *command.Command {
+	flagSet := flag.NewFlagSet("export", flag.ExitOnError)
+	flagSet.StringVar(&exportOpts.file, "o", "", "File to write to (stdout if omitted)")
+	flagSet.StringVar(&exportOpts.format, "format", "", "json or yaml (from the file extension if omitted)")
+	flagSet.BoolVar(&exportOpts.players, "players", false, "Include players and their positions")
+
+	return command.New("export", "Export the game world", flagSet, runExport)
+}
+
+func runExport(cmd *command.Command) error {
+	doc, err := client.New(connect.API()).ExportWorld(exportOpts.players)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if len(exportOpts.file) > 0 {
+		f, err := os.Create(exportOpts.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	return world.Encode(w, doc, fileFormat(exportOpts.format, exportOpts.file))
+}
@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"flag"
+	"os"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/world"
+)
+
+var importOpts struct {
+	file   string
+	format string
+	upsert bool
+	dryRun bool
+}
+
+func importCommand() *command.Command {
+	flagSet := flag.NewFlagSet("import", flag.ExitOnError)
+	flagSet.StringVar(&importOpts.file, "f", "", "File to read from")
+	flagSet.StringVar(&importOpts.format, "format", "", "json or yaml (from the file extension if omitted)")
+	flagSet.BoolVar(&importOpts.upsert, "upsert", false, "Update locations and players which already exist")
+	flagSet.BoolVar(&importOpts.dryRun, "dry-run", false, "Report what would change without changing anything")
+
+	return command.New("import", "Import locations and players into the game world", flagSet, runImport)
+}
+
+func runImport(cmd *command.Command) error {
+	f, err := os.Open(importOpts.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	doc, err := world.Decode(f, fileFormat(importOpts.format, importOpts.file))
+	if err != nil {
+		return err
+	}
+
+	report, problems, err := client.New(connect.API()).ImportWorld(doc, &world.Options{
+		Upsert: importOpts.upsert,
+		DryRun: importOpts.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := checkProblems(report != nil, problems); err != nil {
+		return err
+	}
+
+	return command.Print(report)
+}
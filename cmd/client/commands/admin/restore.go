@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/world"
+)
+
+var restoreOpts struct {
+	file  string
+	clear bool
+}
+
+func restoreCommand() *command.Command {
+	flagSet := flag.NewFlagSet("restore", flag.ExitOnError)
+	flagSet.StringVar(&restoreOpts.file, "f", "", "Snapshot file to restore")
+	flagSet.BoolVar(&restoreOpts.clear, "clear", false, "Delete everything which is not in the snapshot, instead of merging into it")
+
+	return command.New("restore", "Restore the game world from a snapshot", flagSet, runRestore)
+}
+
+func runRestore(cmd *command.Command) error {
+	f, err := os.Open(restoreOpts.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var snapshot world.Snapshot
+	if err := json.NewDecoder(f).Decode(&snapshot); err != nil {
+		return err
+	}
+
+	report, problems, err := client.New(connect.API()).RestoreSnapshot(&snapshot, restoreOpts.clear)
+	if err != nil {
+		return err
+	}
+
+	if err := checkProblems(report != nil, problems); err != nil {
+		return err
+	}
+
+	return command.Print(report)
+}
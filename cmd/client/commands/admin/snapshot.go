@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+)
+
+var snapshotOpts struct {
+	file string
+}
+
+func snapshotCommand() *command.Command {
+	flagSet := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	flagSet.StringVar(&snapshotOpts.file, "o", "", "File to write to (stdout if omitted)")
+
+	return command.New("snapshot", "Save a snapshot of the whole game world", flagSet, runSnapshot)
+}
+
+func runSnapshot(cmd *command.Command) error {
+	snapshot, err := client.New(connect.API()).Snapshot()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if len(snapshotOpts.file) > 0 {
+		f, err := os.Create(snapshotOpts.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snapshot)
+}
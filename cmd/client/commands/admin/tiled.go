@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/world"
+	"github.com/carsonmyers/bublar-assignment/world/tiled"
+)
+
+var tiledOpts struct {
+	file    string
+	layers  string
+	blocked string
+	pixels  bool
+	upsert  bool
+	dryRun  bool
+}
+
+func tiledCommand() *command.Command {
+	flagSet := flag.NewFlagSet("tiled", flag.ExitOnError)
+	flagSet.StringVar(&tiledOpts.file, "f", "", "Tiled map file (.tmx or .json)")
+	flagSet.StringVar(&tiledOpts.layers, "layers", "", "Comma-separated object layers to take locations from (all if omitted)")
+	flagSet.StringVar(&tiledOpts.blocked, "blocked", "", "Comma-separated tile layers whose tiles players can't move onto")
+	flagSet.BoolVar(&tiledOpts.pixels, "pixels", false, "Use pixel coordinates instead of tile coordinates")
+	flagSet.BoolVar(&tiledOpts.upsert, "upsert", false, "Move locations which already exist")
+	flagSet.BoolVar(&tiledOpts.dryRun, "dry-run", false, "Report what would change without changing anything")
+
+	return command.New("tiled", "Import locations from the objects in a Tiled map", flagSet, runTiled)
+}
+
+func runTiled(cmd *command.Command) error {
+	f, err := os.Open(tiledOpts.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	format := tiled.FormatJSON
+	if strings.ToLower(filepath.Ext(tiledOpts.file)) == ".tmx" {
+		format = tiled.FormatTMX
+	}
+
+	m, err := tiled.Decode(f, format)
+	if err != nil {
+		return err
+	}
+
+	opts := &tiled.Options{Pixels: tiledOpts.pixels}
+	if len(tiledOpts.layers) > 0 {
+		opts.Layers = strings.Split(tiledOpts.layers, ",")
+	}
+	if len(tiledOpts.blocked) > 0 {
+		opts.Blocked = strings.Split(tiledOpts.blocked, ",")
+	}
+
+	locations, err := m.Locations(opts)
+	if err != nil {
+		return err
+	}
+
+	report, problems, err := client.New(connect.API()).ImportWorld(&world.Document{
+		Version:   world.FormatVersion,
+		Locations: locations,
+	}, &world.Options{
+		Upsert: tiledOpts.upsert,
+		DryRun: tiledOpts.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := checkProblems(report != nil, problems); err != nil {
+		return err
+	}
+
+	return command.Print(report)
+}
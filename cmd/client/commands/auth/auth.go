@@ -3,7 +3,6 @@ package auth
 import (
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -11,15 +10,18 @@ import (
 	"path/filepath"
 	"syscall"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
-	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/logger"
 	"github.com/gbrlsnchs/jwt"
 	"github.com/mitchellh/go-homedir"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
+var log = logger.GetLogger()
+
 var authOpts struct {
 	user string
 	pass string
@@ -51,36 +53,18 @@ func runLogin(cmd *command.Command) error {
 		authOpts.pass = string(passwdBytes)
 	}
 
-	api := connect.API()
-
-	req, log := api.NewRequest("POST", api.URL("/client/login"), &data.Player{
-		Username: authOpts.user,
-		Password: &authOpts.pass,
-	})
-
-	res, _, err := req.Do()
+	token, err := client.New(connect.API()).Login(authOpts.user, authOpts.pass)
 	if err != nil {
 		return err
 	}
 
-	cookies := res.Cookies()
-	for _, cookie := range cookies {
-		log.Debug("Processing cookie", zap.String("name", cookie.Name), zap.String("value", cookie.Value))
-		if cookie.Name != "AUTH" {
-			continue
-		}
-
-		if err := writeSession(cookie.Value); err != nil {
-			log.Error("Failed to save session", zap.Error(err))
-			return err
-		}
-
-		log.Info("Session saved", zap.String("username", authOpts.user))
-		return nil
+	if err := writeSession(token); err != nil {
+		log.Error("Failed to save session", zap.Error(err))
+		return err
 	}
 
-	log.Error("Endpoint did not return auth token")
-	return errors.New("auth cookie not found")
+	log.Info("Session saved", zap.String("username", authOpts.user))
+	return nil
 }
 
 func runLogout(cmd *command.Command) error {
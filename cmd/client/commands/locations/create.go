@@ -2,17 +2,19 @@ package locations
 
 import (
 	"flag"
-	"fmt"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
 	"github.com/carsonmyers/bublar-assignment/data"
 )
 
 var createOpts struct {
-	name string
-	x    int
-	y    int
+	name   string
+	x      int
+	y      int
+	width  int
+	height int
 }
 
 func createCommand() *command.Command {
@@ -20,25 +22,23 @@ func createCommand() *command.Command {
 	flagSet.StringVar(&createOpts.name, "n", "", "Location name")
 	flagSet.IntVar(&createOpts.x, "x", 0, "X-position of location")
 	flagSet.IntVar(&createOpts.y, "y", 0, "Y-position of location")
+	flagSet.IntVar(&createOpts.width, "width", 0, "Width of location in tiles (unbounded if omitted)")
+	flagSet.IntVar(&createOpts.height, "height", 0, "Height of location in tiles (unbounded if omitted)")
 
 	return command.New("create", "Create a new location", flagSet, runCreate)
 }
 
 func runCreate(cmd *command.Command) error {
-	api := connect.API()
-
-	req, _ := api.NewRequest("POST", api.URL("/admin/locations"), &data.Location{
-		Name: createOpts.name,
-		X:    createOpts.x,
-		Y:    createOpts.y,
+	location, err := client.New(connect.API()).CreateLocation(&data.Location{
+		Name:   createOpts.name,
+		X:      createOpts.x,
+		Y:      createOpts.y,
+		Width:  createOpts.width,
+		Height: createOpts.height,
 	})
-
-	_, output, err := req.Do()
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-
-	return nil
+	return command.Print(location)
 }
@@ -2,8 +2,8 @@ package locations
 
 import (
 	"flag"
-	"fmt"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
 )
@@ -20,15 +20,10 @@ func getCommand() *command.Command {
 }
 
 func runGet(cmd *command.Command) error {
-	api := connect.API()
-
-	url := api.URL(fmt.Sprintf("/client/locations/%s", getOpts.name))
-	req, _ := api.NewRequest("GET", url, nil)
-	_, output, err := req.Do()
+	location, err := client.New(connect.API()).GetLocation(getOpts.name)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	return command.Print(location)
 }
@@ -2,14 +2,18 @@ package locations
 
 import (
 	"flag"
-	"fmt"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
 )
 
 var listOpts struct {
-	name string
+	name   string
+	limit  int
+	after  string
+	sort   string
+	prefix string
 }
 
 func listCommand() *command.Command {
@@ -18,7 +22,13 @@ func listCommand() *command.Command {
 
 	players := command.New("players", "List players in a location", flagSet, runListPlayers)
 
-	cmd := command.New("list", "List all locations", nil, runList)
+	listFlags := flag.NewFlagSet("list", flag.ExitOnError)
+	listFlags.IntVar(&listOpts.limit, "limit", 0, "Maximum number of locations to list (server default if omitted)")
+	listFlags.StringVar(&listOpts.after, "after", "", "Cursor returned as `next` by the previous page")
+	listFlags.StringVar(&listOpts.sort, "sort", "", "Sort by name or createdAt, prefixed with - for descending order")
+	listFlags.StringVar(&listOpts.prefix, "prefix", "", "Only list locations whose name starts with this")
+
+	cmd := command.New("list", "List all locations", listFlags, runList)
 	cmd.AddCommand(players)
 
 	return cmd
@@ -38,28 +48,25 @@ func runList(cmd *command.Command) error {
 }
 
 func runListLocations(cmd *command.Command) error {
-	api := connect.API()
-
-	req, _ := api.NewRequest("GET", api.URL("/client/locations"), nil)
-	_, output, err := req.Do()
+	locations, next, err := client.New(connect.API()).ListLocations(&client.ListQuery{
+		Limit:  listOpts.limit,
+		After:  listOpts.after,
+		Sort:   listOpts.sort,
+		Prefix: listOpts.prefix,
+	})
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	command.PrintNext(next)
+	return command.Print(locations)
 }
 
 func runListPlayers(cmd *command.Command) error {
-	api := connect.API()
-
-	url := api.URL(fmt.Sprintf("/client/locations/%s/players", listOpts.name))
-	req, _ := api.NewRequest("GET", url, nil)
-	_, output, err := req.Do()
+	players, err := client.New(connect.API()).LocationPlayers(listOpts.name)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	return command.Print(players)
 }
@@ -14,6 +14,7 @@ func Command() *command.Command {
 	cmd.AddCommand(listCommand())
 	cmd.AddCommand(updateCommand())
 	cmd.AddCommand(deleteCommand())
+	cmd.AddCommand(restoreCommand())
 
 	return cmd
 }
@@ -0,0 +1,29 @@
+package locations
+
+import (
+	"flag"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+)
+
+var restoreOpts struct {
+	name string
+}
+
+func restoreCommand() *command.Command {
+	flagSet := flag.NewFlagSet("restore", flag.ExitOnError)
+	flagSet.StringVar(&restoreOpts.name, "n", "", "Location name")
+
+	return command.New("restore", "Restore a deleted location", flagSet, runRestore)
+}
+
+func runRestore(cmd *command.Command) error {
+	location, err := client.New(connect.API()).RestoreLocation(restoreOpts.name)
+	if err != nil {
+		return err
+	}
+
+	return command.Print(location)
+}
@@ -2,11 +2,10 @@ package locations
 
 import (
 	"flag"
-	"fmt"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
-	"github.com/carsonmyers/bublar-assignment/data"
 )
 
 var updateOpts struct {
@@ -14,33 +13,62 @@ var updateOpts struct {
 	newName string
 	newX    int
 	newY    int
+	width   int
+	height  int
+	version uint
 }
 
 func updateCommand() *command.Command {
 	flagSet := flag.NewFlagSet("update", flag.ExitOnError)
 	flagSet.StringVar(&updateOpts.name, "n", "", "Location name")
 	flagSet.StringVar(&updateOpts.newName, "nn", "", "New location name")
-	flagSet.IntVar(&updateOpts.newX, "x", 0, "New X-position for location")
-	flagSet.IntVar(&updateOpts.newY, "y", 0, "New Y-position for location")
+	flagSet.IntVar(&updateOpts.newX, "x", 0, "New X-position for location (unchanged if omitted)")
+	flagSet.IntVar(&updateOpts.newY, "y", 0, "New Y-position for location (unchanged if omitted)")
+	flagSet.IntVar(&updateOpts.width, "width", 0, "New width of location in tiles, or 0 for unbounded (unchanged if omitted)")
+	flagSet.IntVar(&updateOpts.height, "height", 0, "New height of location in tiles, or 0 for unbounded (unchanged if omitted)")
+	flagSet.UintVar(&updateOpts.version, "version", 0, "Only update if the location is still at this version (the fetched version if omitted)")
 
 	return command.New("update", "Update a location's details", flagSet, runUpdate)
 }
 
 func runUpdate(cmd *command.Command) error {
-	api := connect.API()
+	c := client.New(connect.API())
 
-	url := api.URL(fmt.Sprintf("/admin/locations/%s", updateOpts.name))
-	req, _ := api.NewRequest("PATCH", url, &data.Location{
-		Name: updateOpts.newName,
-		X:    updateOpts.newX,
-		Y:    updateOpts.newY,
-	})
+	// the location keeps any details which aren't given, including its blocked
+	// tiles which come from map imports
+	existing, err := c.GetLocation(updateOpts.name)
+	if err != nil {
+		return err
+	}
+
+	update := *existing
+	if len(updateOpts.newName) > 0 {
+		update.Name = updateOpts.newName
+	}
+	if cmd.IsSet("x") {
+		update.X = updateOpts.newX
+	}
+	if cmd.IsSet("y") {
+		update.Y = updateOpts.newY
+	}
+	if cmd.IsSet("width") {
+		update.Width = updateOpts.width
+	}
+	if cmd.IsSet("height") {
+		update.Height = updateOpts.height
+	}
+
+	// without a version, the update only applies to the location as it was
+	// fetched, so changes made in between aren't overwritten
+	version := updateOpts.version
+	if !cmd.IsSet("version") {
+		version = existing.Version
+	}
 
-	_, output, err := req.Do()
+	location, err := c.UpdateLocation(updateOpts.name, &update, version)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	return command.Print(location)
 }
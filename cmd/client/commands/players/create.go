@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"syscall"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
-	"github.com/carsonmyers/bublar-assignment/data"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
@@ -36,19 +36,10 @@ func runCreate(cmd *command.Command) error {
 		createOpts.pass = string(passwdBytes)
 	}
 
-	api := connect.API()
-
-	req, _ := api.NewRequest("POST", api.URL("/client/players"), &data.Player{
-		Username: createOpts.user,
-		Password: &createOpts.pass,
-	})
-
-	_, output, err := req.Do()
+	player, err := client.New(connect.API()).CreatePlayer(createOpts.user, createOpts.pass)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-
-	return nil
+	return command.Print(player)
 }
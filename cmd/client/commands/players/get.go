@@ -2,8 +2,8 @@ package players
 
 import (
 	"flag"
-	"fmt"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
 )
@@ -20,21 +20,10 @@ func getCommand() *command.Command {
 }
 
 func runGet(cmd *command.Command) error {
-	api := connect.API()
-
-	var req *connect.Request
-	if len(getOpts.user) == 0 {
-		req, _ = api.NewRequest("GET", api.URL("/client/player"), nil)
-	} else {
-		url := api.URL(fmt.Sprintf("/client/players/%s", getOpts.user))
-		req, _ = api.NewRequest("GET", url, nil)
-	}
-
-	_, output, err := req.Do()
+	player, err := client.New(connect.API()).GetPlayer(getOpts.user)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	return command.Print(player)
 }
@@ -1,25 +1,49 @@
 package players
 
 import (
-	"fmt"
+	"flag"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
 )
 
+var listOpts struct {
+	limit    int
+	after    string
+	sort     string
+	prefix   string
+	location string
+	online   bool
+}
+
 func listCommand() *command.Command {
-	return command.New("list", "List all players", nil, runList)
+	flagSet := flag.NewFlagSet("list", flag.ExitOnError)
+	flagSet.IntVar(&listOpts.limit, "limit", 0, "Maximum number of players to list (server default if omitted)")
+	flagSet.StringVar(&listOpts.after, "after", "", "Cursor returned as `next` by the previous page")
+	flagSet.StringVar(&listOpts.sort, "sort", "", "Sort by username or createdAt, prefixed with - for descending order")
+	flagSet.StringVar(&listOpts.prefix, "prefix", "", "Only list players whose username starts with this")
+	flagSet.StringVar(&listOpts.location, "location", "", "Only list players in this location")
+	flagSet.BoolVar(&listOpts.online, "online", false, "Only list players who are in a location")
+
+	return command.New("list", "List all players", flagSet, runList)
 }
 
 func runList(cmd *command.Command) error {
-	api := connect.API()
-
-	req, _ := api.NewRequest("GET", api.URL("/client/players"), nil)
-	_, output, err := req.Do()
+	players, next, err := client.New(connect.API()).ListPlayers(&client.PlayerQuery{
+		ListQuery: client.ListQuery{
+			Limit:  listOpts.limit,
+			After:  listOpts.after,
+			Sort:   listOpts.sort,
+			Prefix: listOpts.prefix,
+		},
+		Location: listOpts.location,
+		Online:   listOpts.online,
+	})
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	command.PrintNext(next)
+	return command.Print(players)
 }
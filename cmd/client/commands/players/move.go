@@ -2,8 +2,8 @@ package players
 
 import (
 	"flag"
-	"fmt"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
 )
@@ -24,29 +24,10 @@ func moveCommand() *command.Command {
 }
 
 func runMove(cmd *command.Command) error {
-	api := connect.API()
-
-	body := struct {
-		X int `json:"x"`
-		Y int `json:"y"`
-	}{
-		X: moveOpts.x,
-		Y: moveOpts.y,
-	}
-
-	var req *connect.Request
-	if len(moveOpts.user) == 0 {
-		req, _ = api.NewRequest("POST", api.URL("/client/player/move"), &body)
-	} else {
-		url := api.URL(fmt.Sprintf("/admin/players/%s/move", moveOpts.user))
-		req, _ = api.NewRequest("POST", url, &body)
-	}
-
-	_, output, err := req.Do()
+	player, err := client.New(connect.API()).Move(moveOpts.user, moveOpts.x, moveOpts.y)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	return command.Print(player)
 }
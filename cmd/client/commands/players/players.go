@@ -16,6 +16,7 @@ func Command() *command.Command {
 	cmd.AddCommand(moveCommand())
 	cmd.AddCommand(travelCommand())
 	cmd.AddCommand(deleteCommand())
+	cmd.AddCommand(restoreCommand())
 
 	return cmd
 }
@@ -0,0 +1,29 @@
+package players
+
+import (
+	"flag"
+
+	"github.com/carsonmyers/bublar-assignment/api/client"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/connect"
+)
+
+var restoreOpts struct {
+	user string
+}
+
+func restoreCommand() *command.Command {
+	flagSet := flag.NewFlagSet("restore", flag.ExitOnError)
+	flagSet.StringVar(&restoreOpts.user, "u", "", "Username")
+
+	return command.New("restore", "Restore a deleted player", flagSet, runRestore)
+}
+
+func runRestore(cmd *command.Command) error {
+	player, err := client.New(connect.API()).RestorePlayer(restoreOpts.user)
+	if err != nil {
+		return err
+	}
+
+	return command.Print(player)
+}
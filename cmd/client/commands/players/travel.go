@@ -2,8 +2,8 @@ package players
 
 import (
 	"flag"
-	"fmt"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
 )
@@ -22,27 +22,10 @@ func travelCommand() *command.Command {
 }
 
 func runTravel(cmd *command.Command) error {
-	api := connect.API()
-
-	body := struct {
-		Location string `json:"location"`
-	}{
-		Location: travelOpts.location,
-	}
-
-	var req *connect.Request
-	if len(travelOpts.user) == 0 {
-		req, _ = api.NewRequest("POST", api.URL("/client/player/travel"), &body)
-	} else {
-		url := api.URL(fmt.Sprintf("/admin/players/%s/travel", travelOpts.user))
-		req, _ = api.NewRequest("POST", url, &body)
-	}
-
-	_, output, err := req.Do()
+	player, err := client.New(connect.API()).Travel(travelOpts.user, travelOpts.location)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	return command.Print(player)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"syscall"
 
+	"github.com/carsonmyers/bublar-assignment/api/client"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
 	"github.com/carsonmyers/bublar-assignment/connect"
 	"github.com/carsonmyers/bublar-assignment/data"
@@ -15,6 +16,7 @@ var updateOpts struct {
 	user        string
 	newUser     string
 	newPassword string
+	version     uint
 }
 
 func updateCommand() *command.Command {
@@ -22,6 +24,7 @@ func updateCommand() *command.Command {
 	flagSet.StringVar(&updateOpts.user, "u", "", "Username")
 	flagSet.StringVar(&updateOpts.newUser, "nu", "", "New username")
 	flagSet.StringVar(&updateOpts.newPassword, "p", "", "New password (will prompt if omitted)")
+	flagSet.UintVar(&updateOpts.version, "version", 0, "Only update if the player is still at this version")
 
 	return command.New("update", "Update a player's details", flagSet, runUpdate)
 }
@@ -35,29 +38,16 @@ func runUpdate(cmd *command.Command) error {
 		}
 		fmt.Println()
 
-		createOpts.pass = string(passwdBytes)
+		updateOpts.newPassword = string(passwdBytes)
 	}
 
-	body := &data.Player{
+	player, err := client.New(connect.API()).UpdatePlayer(updateOpts.user, &data.Player{
 		Username: updateOpts.newUser,
 		Password: &updateOpts.newPassword,
-	}
-
-	api := connect.API()
-
-	var req *connect.Request
-	if len(updateOpts.user) == 0 {
-		req, _ = api.NewRequest("PATCH", api.URL("/client/player"), body)
-	} else {
-		url := api.URL(fmt.Sprintf("/admin/players/%s", updateOpts.user))
-		req, _ = api.NewRequest("PATCH", url, body)
-	}
-
-	_, output, err := req.Do()
+	}, updateOpts.version)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(output)
-	return nil
+	return command.Print(player)
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/carsonmyers/bublar-assignment/cmd/client/command"
+	"github.com/carsonmyers/bublar-assignment/cmd/client/commands/admin"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/commands/auth"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/commands/locations"
 	"github.com/carsonmyers/bublar-assignment/cmd/client/commands/players"
@@ -44,6 +45,7 @@ func main() {
 	cmd.AddCommand(auth.LogoutCommand())
 	cmd.AddCommand(players.Command())
 	cmd.AddCommand(locations.Command())
+	cmd.AddCommand(admin.Command())
 
 	if err := cmd.Execute(); err != nil {
 		fmt.Println(err)
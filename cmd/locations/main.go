@@ -9,9 +9,13 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/carsonmyers/bublar-assignment/audit"
 	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/errors/rpcstatus"
 	"github.com/carsonmyers/bublar-assignment/locations"
+	locationsServer "github.com/carsonmyers/bublar-assignment/locations/server"
 	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/carsonmyers/bublar-assignment/migrate"
 	"github.com/carsonmyers/bublar-assignment/proto"
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
@@ -28,12 +32,14 @@ type config struct {
 	Locations *configure.LocationsConfig
 	Postgres  *configure.PostgresConfig
 	Redis     *configure.RedisConfig
+	Storage   *configure.StorageConfig
 }
 
 var defaultConfig = config{
 	Locations: &configure.DefaultLocationsConfig,
 	Postgres:  &configure.DefaultPostgresConfig,
 	Redis:     &configure.DefaultRedisConfig,
+	Storage:   &configure.DefaultStorageConfig,
 }
 
 func main() {
@@ -41,10 +47,18 @@ func main() {
 	envconfig.MustProcess("locations", conf.Locations)
 	envconfig.MustProcess("postgres", conf.Postgres)
 	envconfig.MustProcess("redis", conf.Redis)
+	envconfig.MustProcess("storage", conf.Storage)
 
 	confJSON, _ := json.MarshalIndent(conf, "", "\t")
 	log.Info(fmt.Sprintf("Configuration: %s", confJSON))
 
+	setup(conf)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	start(conf)
 
 	signal.Notify(signals,
@@ -80,22 +94,54 @@ func main() {
 	}
 }
 
-func start(conf config) {
+func setup(conf config) {
 	configure.Locations(conf.Locations)
 	configure.Postgres(conf.Postgres)
 	configure.Redis(conf.Redis)
+	configure.Storage(conf.Storage)
+}
+
+func runMigrate(args []string) {
+	migrators := make([]*migrate.Migrator, 0)
+	for _, getMigrator := range []func() (*migrate.Migrator, error){locations.Migrator, audit.Migrator} {
+		m, err := getMigrator()
+		if err != nil {
+			log.Fatal("Could not connect to database", zap.Error(err))
+		}
+
+		if m != nil {
+			migrators = append(migrators, m)
+		}
+	}
 
-	if err := locations.Migrate(); err != nil {
-		log.Fatal("Could not migrate data", zap.Error(err))
+	if err := migrate.Run(os.Stdout, args, migrators...); err != nil {
+		log.Fatal("Migration failed", zap.Error(err))
 	}
+}
+
+func start(conf config) {
+	if conf.Storage.AutoMigrate {
+		if err := locations.Migrate(); err != nil {
+			log.Fatal("Could not migrate data", zap.Error(err))
+		}
+
+		if err := audit.Migrate(); err != nil {
+			log.Fatal("Could not migrate audit data", zap.Error(err))
+		}
+	}
+
+	locations.StartPurge()
 
 	listen, err := net.Listen(conf.Locations.Protocol, fmt.Sprintf("%s:%d", conf.Locations.Host, conf.Locations.Port))
 	if err != nil {
 		log.Fatal("Could not create listener", zap.Error(err))
 	}
 
-	server = grpc.NewServer()
-	proto.RegisterLocationsServer(server, &Server{})
+	server = grpc.NewServer(
+		grpc.UnaryInterceptor(rpcstatus.UnaryServerInterceptor),
+		grpc.StreamInterceptor(rpcstatus.StreamServerInterceptor),
+	)
+	proto.RegisterLocationsServer(server, &locationsServer.Server{})
 
 	go func() {
 		if err := server.Serve(listen); err != nil {
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/api"
+	"github.com/carsonmyers/bublar-assignment/audit"
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/errors/rpcstatus"
+	"github.com/carsonmyers/bublar-assignment/locations"
+	locationsServer "github.com/carsonmyers/bublar-assignment/locations/server"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/carsonmyers/bublar-assignment/players"
+	playersServer "github.com/carsonmyers/bublar-assignment/players/server"
+	"github.com/carsonmyers/bublar-assignment/positions"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+var (
+	apiServer    *http.Server
+	playersRPC   *grpc.Server
+	locationsRPC *grpc.Server
+	log          = logger.GetLogger()
+	signals      = make(chan os.Signal, 1)
+)
+
+type config struct {
+	API       *configure.APIConfig
+	Locations *configure.LocationsConfig
+	Players   *configure.PlayersConfig
+	Postgres  *configure.PostgresConfig
+	Redis     *configure.RedisConfig
+	Storage   *configure.StorageConfig
+	RateLimit *configure.RateLimitConfig
+}
+
+var defaultConfig = config{
+	API:       &configure.DefaultAPIConfig,
+	Locations: &configure.DefaultLocationsConfig,
+	Players:   &configure.DefaultPlayersConfig,
+	Postgres:  &configure.DefaultPostgresConfig,
+	Redis:     &configure.DefaultRedisConfig,
+	Storage: &configure.StorageConfig{
+		Backend:       configure.BackendMemory,
+		File:          configure.DefaultStorageConfig.File,
+		AutoMigrate:   true,
+		Retention:     configure.DefaultStorageConfig.Retention,
+		PurgeInterval: configure.DefaultStorageConfig.PurgeInterval,
+	},
+	RateLimit: &configure.DefaultRateLimitConfig,
+}
+
+func main() {
+	conf := defaultConfig
+	envconfig.MustProcess("api", conf.API)
+	envconfig.MustProcess("locations", conf.Locations)
+	envconfig.MustProcess("players", conf.Players)
+	envconfig.MustProcess("postgres", conf.Postgres)
+	envconfig.MustProcess("redis", conf.Redis)
+	envconfig.MustProcess("storage", conf.Storage)
+	envconfig.MustProcess("ratelimit", conf.RateLimit)
+
+	confJSON, _ := json.MarshalIndent(conf, "", "\t")
+	log.Info(fmt.Sprintf("Configuration: %s", confJSON))
+
+	start(conf)
+
+	signal.Notify(signals,
+		syscall.SIGHUP,
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT)
+
+	quitting := false
+	for s := range signals {
+		if quitting || s == syscall.SIGHUP {
+			log.Error("Forcing shutdown", zap.String("signal", s.String()))
+			apiServer.Close()
+			playersRPC.Stop()
+			locationsRPC.Stop()
+			os.Exit(1)
+		}
+
+		log.Info("Attempting to shut down gracefully", zap.String("signal", s.String()))
+		quitting = true
+
+		shutdownComplete := make(chan bool)
+
+		go func() {
+			if err := apiServer.Shutdown(context.Background()); err != nil {
+				log.Error("Shutdown error", zap.Error(err))
+			}
+
+			playersRPC.GracefulStop()
+			locationsRPC.GracefulStop()
+			shutdownComplete <- true
+		}()
+
+		select {
+		case <-shutdownComplete:
+			return
+		case <-time.After(10 * time.Second):
+			log.Fatal("Shutdown timeout")
+		}
+	}
+}
+
+func start(conf config) {
+	configure.API(conf.API)
+	configure.Locations(conf.Locations)
+	configure.Players(conf.Players)
+	configure.Postgres(conf.Postgres)
+	configure.Redis(conf.Redis)
+	configure.Storage(conf.Storage)
+	configure.RateLimit(conf.RateLimit)
+
+	if conf.Storage.AutoMigrate {
+		if err := locations.Migrate(); err != nil {
+			log.Fatal("Could not migrate locations data", zap.Error(err))
+		}
+
+		if err := players.Migrate(); err != nil {
+			log.Fatal("Could not migrate players data", zap.Error(err))
+		}
+
+		if err := positions.Migrate(); err != nil {
+			log.Fatal("Could not migrate positions data", zap.Error(err))
+		}
+
+		if err := audit.Migrate(); err != nil {
+			log.Fatal("Could not migrate audit data", zap.Error(err))
+		}
+	}
+
+	locations.StartPurge()
+	players.StartPurge()
+
+	locationsRPC = grpc.NewServer(
+		grpc.UnaryInterceptor(rpcstatus.UnaryServerInterceptor),
+		grpc.StreamInterceptor(rpcstatus.StreamServerInterceptor),
+	)
+	proto.RegisterLocationsServer(locationsRPC, &locationsServer.Server{})
+	serveRPC(locationsRPC, conf.Locations.Protocol, conf.Locations.Host, conf.Locations.Port)
+	log.Info(fmt.Sprintf("Locations service is listening on %s", conf.Locations.String()))
+
+	playersRPC = grpc.NewServer(
+		grpc.UnaryInterceptor(rpcstatus.UnaryServerInterceptor),
+		grpc.StreamInterceptor(rpcstatus.StreamServerInterceptor),
+	)
+	proto.RegisterPlayersServer(playersRPC, &playersServer.Server{})
+	serveRPC(playersRPC, conf.Players.Protocol, conf.Players.Host, conf.Players.Port)
+	log.Info(fmt.Sprintf("Players service is listening on %s", conf.Players.String()))
+
+	apiServer = &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", conf.API.Host, conf.API.Port),
+		WriteTimeout: 15 * time.Second,
+		ReadTimeout:  15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+		Handler:      api.GetAPI(),
+	}
+
+	go func() {
+		err := apiServer.ListenAndServe()
+		if err != http.ErrServerClosed {
+			log.Fatal("Fatal server error", zap.Error(err))
+		}
+
+		close(signals)
+	}()
+
+	log.Info(fmt.Sprintf("API Server is listening on %s", conf.API.String()))
+}
+
+func serveRPC(server *grpc.Server, protocol, host string, port uint) {
+	listen, err := net.Listen(protocol, fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		log.Fatal("Could not create listener", zap.Error(err))
+	}
+
+	go func() {
+		if err := server.Serve(listen); err != nil {
+			log.Fatal("Fatal server error", zap.Error(err))
+		}
+	}()
+}
@@ -1,6 +1,9 @@
 package configure
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // APIConfig - configuration struct for API service
 type APIConfig struct {
@@ -11,6 +14,17 @@ type APIConfig struct {
 	Name        string
 	Session     string
 	EnableAdmin bool
+
+	// IdempotencyWindow - how long the API replays the response to a request
+	// made with an Idempotency-Key
+	IdempotencyWindow time.Duration
+
+	// Retries - how many times a client retries a request which failed
+	// without a response, or with a temporary error
+	Retries uint
+
+	// Timeout - how long a client waits for a response before retrying
+	Timeout time.Duration
 }
 
 func (c *APIConfig) String() string {
@@ -25,6 +39,10 @@ var DefaultAPIConfig = APIConfig{
 	BasePath:    "/v1",
 	Name:        "API",
 	EnableAdmin: false,
+
+	IdempotencyWindow: 24 * time.Hour,
+	Retries:           2,
+	Timeout:           30 * time.Second,
 }
 
 var apiConfig *APIConfig
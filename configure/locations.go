@@ -7,6 +7,10 @@ type LocationsConfig struct {
 	Host     string
 	Port     uint
 	Protocol string
+
+	// Fallback - location that players are moved to when the location they
+	// are in is deleted. If empty, they are left without a location instead.
+	Fallback string
 }
 
 func (c *LocationsConfig) String() string {
@@ -2,11 +2,28 @@ package configure
 
 import "fmt"
 
+const (
+	// SpeedReject - moves which are too fast are refused
+	SpeedReject = "reject"
+
+	// SpeedClamp - moves which are too fast are shortened to the furthest
+	// the player could have gone
+	SpeedClamp = "clamp"
+)
+
 // PlayersConfig - configuration struct for players service
 type PlayersConfig struct {
 	Host     string
 	Port     uint
 	Protocol string
+
+	// MaxSpeed - how far a player may move per second within a location. 0,
+	// the default, allows any speed.
+	MaxSpeed float64
+
+	// SpeedViolation - what happens to a move which is too fast: SpeedReject
+	// or SpeedClamp
+	SpeedViolation string
 }
 
 func (c *PlayersConfig) String() string {
@@ -18,6 +35,9 @@ var DefaultPlayersConfig = PlayersConfig{
 	Host:     "0.0.0.0",
 	Port:     49801,
 	Protocol: "tcp",
+
+	MaxSpeed:       0,
+	SpeedViolation: SpeedReject,
 }
 
 var playersConfig *PlayersConfig
@@ -0,0 +1,54 @@
+package configure
+
+import "time"
+
+// RateLimitConfig - configuration struct for limiting how often the API can
+// be called
+type RateLimitConfig struct {
+	// LoginRate - how many logins one address may attempt per LoginWindow
+	LoginRate   uint
+	LoginWindow time.Duration
+
+	// LockoutThreshold - how many failed logins in a row lock out an address
+	// or username. Every failure after that doubles the lockout, starting at
+	// LockoutBase and up to LockoutMax.
+	LockoutThreshold uint
+	LockoutBase      time.Duration
+	LockoutMax       time.Duration
+
+	// FailureWindow - how long a failed login counts towards a lockout
+	FailureWindow time.Duration
+
+	// GameplayRate - how many gameplay requests (like moving) one player may
+	// make per GameplayWindow. 0 disables the limit.
+	GameplayRate   uint
+	GameplayWindow time.Duration
+}
+
+// DefaultRateLimitConfig - configuration defaults which are overridden by options
+var DefaultRateLimitConfig = RateLimitConfig{
+	LoginRate:        20,
+	LoginWindow:      time.Minute,
+	LockoutThreshold: 5,
+	LockoutBase:      30 * time.Second,
+	LockoutMax:       time.Hour,
+	FailureWindow:    24 * time.Hour,
+	GameplayRate:     20,
+	GameplayWindow:   time.Second,
+}
+
+var rateLimitConfig *RateLimitConfig
+
+// RateLimit - set the config
+func RateLimit(config *RateLimitConfig) {
+	rateLimitConfig = config
+}
+
+// GetRateLimit - get the config
+func GetRateLimit() *RateLimitConfig {
+	if rateLimitConfig == nil {
+		return &DefaultRateLimitConfig
+	}
+
+	return rateLimitConfig
+}
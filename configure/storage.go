@@ -0,0 +1,64 @@
+package configure
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// BackendPostgres - accounts and locations in postgres, positions in redis
+	BackendPostgres = "postgres"
+
+	// BackendSQLite - everything kept in an embedded sqlite database file
+	BackendSQLite = "sqlite"
+
+	// BackendMemory - everything held in process memory, nothing is persisted
+	BackendMemory = "memory"
+)
+
+// StorageConfig - configuration struct for selecting the storage backend
+type StorageConfig struct {
+	Backend     string
+	File        string
+	AutoMigrate bool
+
+	// Retention - how long deleted players and locations can be restored
+	// before they are purged, during which their names stay reserved
+	Retention time.Duration
+
+	// PurgeInterval - how often to look for deleted records to purge
+	PurgeInterval time.Duration
+}
+
+func (c *StorageConfig) String() string {
+	if c.Backend == BackendSQLite {
+		return fmt.Sprintf("backend=%s file=%s", c.Backend, c.File)
+	}
+
+	return fmt.Sprintf("backend=%s", c.Backend)
+}
+
+// DefaultStorageConfig - configuration defaults which are overridden by options
+var DefaultStorageConfig = StorageConfig{
+	Backend:       BackendPostgres,
+	File:          "bublar.db",
+	AutoMigrate:   true,
+	Retention:     30 * 24 * time.Hour,
+	PurgeInterval: time.Hour,
+}
+
+var storageConfig *StorageConfig
+
+// Storage - set the config
+func Storage(config *StorageConfig) {
+	storageConfig = config
+}
+
+// GetStorage - get the config
+func GetStorage() *StorageConfig {
+	if storageConfig == nil {
+		return &DefaultStorageConfig
+	}
+
+	return storageConfig
+}
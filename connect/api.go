@@ -52,6 +52,23 @@ func (c *APIClient) URL(endpoint string) string {
 	return fmt.Sprintf("%s://%s:%d%s%s", c.config.Protocol, c.config.Host, c.config.Port, c.config.BasePath, endpoint)
 }
 
+// SetSession - send an auth token with every following request
+func (c *APIClient) SetSession(token string) {
+	c.config.Session = token
+}
+
+// idempotentMethods - requests which are sent with an Idempotency-Key, so they
+// can be retried without being applied twice
+var idempotentMethods = map[string]bool{
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// retryBackoff - the wait before the first retry, doubled for each one after
+const retryBackoff = 100 * time.Millisecond
+
 // Request single request for a given client
 type Request struct {
 	Header   http.Header
@@ -60,6 +77,7 @@ type Request struct {
 	request  *http.Request
 	err      error
 	bytes    int
+	retries  uint
 	logger   *zap.Logger
 	response *http.Response
 }
@@ -70,9 +88,10 @@ func (c *APIClient) NewRequest(method, url string, body interface{}) (*Request,
 	logger := c.logger.With(zap.String("requestID", rID))
 
 	r := &Request{
-		client: &http.Client{},
-		name:   c.config.Name,
-		logger: logger,
+		client:  &http.Client{Timeout: c.config.Timeout},
+		name:    c.config.Name,
+		retries: c.config.Retries,
+		logger:  logger,
 	}
 
 	var reader io.Reader
@@ -94,6 +113,12 @@ func (c *APIClient) NewRequest(method, url string, body interface{}) (*Request,
 	}
 
 	req.Header.Add("Request-ID", rID)
+	if c.config.Retries > 0 && idempotentMethods[method] {
+		// every attempt carries the same key, so a request which reached the
+		// API before failing is replayed rather than repeated
+		req.Header.Set("Idempotency-Key", rID)
+	}
+
 	if len(c.config.Session) > 0 {
 		req.AddCookie(&http.Cookie{
 			Name:  "AUTH",
@@ -107,28 +132,104 @@ func (c *APIClient) NewRequest(method, url string, body interface{}) (*Request,
 	return r, logger
 }
 
-// Do - execute a request
-func (r *Request) Do() (*http.Response, string, error) {
+// IfMatch - make the request conditional on the resource being at a version.
+// A version of 0 leaves the request unconditional.
+func (r *Request) IfMatch(version uint) *Request {
+	if version != 0 && r.Header != nil {
+		r.Header.Set("If-Match", fmt.Sprintf(`"%d"`, version))
+	}
+
+	return r
+}
+
+// Send - execute a request, returning the raw response body
+func (r *Request) Send() (*http.Response, []byte, error) {
 	if r.err != nil {
 		r.logger.Error("Request error", zap.Error(r.err))
-		return nil, "", r.err
+		return nil, nil, r.err
 	}
 
-	r.logRequest(r.request.Method, r.request.URL.String(), r.bytes)
-
-	start := time.Now()
-	res, err := r.client.Do(r.request)
-	d := time.Now().Sub(start)
-	if err == nil {
-		r.logResponse(res, d)
-	} else {
-		r.logResponseError(err, d)
-		return nil, "", err
+	res, err := r.sendAttempts()
+	if err != nil {
+		return nil, nil, err
 	}
+	defer res.Body.Close()
 
 	data, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		r.logger.Error("Failed to read response body", zap.Error(err))
+		return res, nil, err
+	}
+
+	return res, data, nil
+}
+
+// sendAttempts - send a request until it gets a response which shouldn't be
+// retried, or runs out of retries. Mutating requests are only retried when
+// they have an Idempotency-Key.
+func (r *Request) sendAttempts() (*http.Response, error) {
+	retries := r.retries
+	if idempotentMethods[r.request.Method] && len(r.request.Header.Get("Idempotency-Key")) == 0 {
+		retries = 0
+	}
+
+	for attempt := uint(0); ; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryBackoff << (attempt - 1))
+			r.logger.Warn("Retrying request", zap.Uint("attempt", attempt))
+
+			if r.request.GetBody != nil {
+				body, err := r.request.GetBody()
+				if err != nil {
+					return nil, err
+				}
+
+				r.request.Body = body
+			}
+		}
+
+		r.logRequest(r.request.Method, r.request.URL.String(), r.bytes)
+
+		start := time.Now()
+		res, err := r.client.Do(r.request)
+		d := time.Now().Sub(start)
+		if err != nil {
+			r.logResponseError(err, d)
+			if attempt < retries {
+				continue
+			}
+
+			return nil, err
+		}
+
+		r.logResponse(res, d)
+		if attempt < retries && retryable(res) {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+			continue
+		}
+
+		return res, nil
+	}
+}
+
+// retryable - whether a response is a temporary failure: the API or a gateway
+// in front of it is unavailable, or an earlier attempt is still in progress
+func retryable(res *http.Response) bool {
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		return len(res.Header.Get("Retry-After")) > 0
+	}
+
+	return false
+}
+
+// Do - execute a request, returning the response body indented for display
+func (r *Request) Do() (*http.Response, string, error) {
+	res, data, err := r.Send()
+	if err != nil {
 		return res, "", err
 	}
 
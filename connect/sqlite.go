@@ -0,0 +1,52 @@
+package connect
+
+import (
+	"errors"
+
+	// SQLite driver
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"go.uber.org/zap"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/jinzhu/gorm"
+)
+
+var sqlitedb *gorm.DB
+
+// SQLite Returns a connection to the embedded sqlite database
+func SQLite() (*gorm.DB, error) {
+	if sqlitedb != nil {
+		return sqlitedb, nil
+	}
+
+	config := configure.GetStorage()
+	log.Info("Opening sqlite database", zap.String("config", config.String()))
+
+	db, err := gorm.Open("sqlite3", config.File)
+	if err != nil {
+		log.Error("Opening sqlite database failed", zap.Error(err))
+		return nil, errors.New("SQLite connection failed")
+	}
+
+	// sqlite only allows a single writer, so share one connection rather
+	// than letting concurrent requests fail with "database is locked"
+	db.DB().SetMaxOpenConns(1)
+
+	loggerConfig := configure.GetLogger()
+	if loggerConfig.Level <= zap.DebugLevel {
+		db.LogMode(true)
+	}
+	db.SingularTable(true)
+
+	sqlitedb = db
+	return sqlitedb, nil
+}
+
+// Database Returns a connection to the SQL database selected by the storage config
+func Database() (*gorm.DB, error) {
+	if configure.GetStorage().Backend == configure.BackendSQLite {
+		return SQLite()
+	}
+
+	return Postgres()
+}
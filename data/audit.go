@@ -0,0 +1,23 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry - a change made through a service, and who made it
+type AuditEntry struct {
+	ID        uint      `json:"id"`
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Address   string    `json:"address,omitempty"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	RequestID string    `json:"requestId,omitempty"`
+
+	// Before, After - the target before and after the change, left out if it
+	// didn't exist
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
@@ -14,6 +14,17 @@ type Location struct {
 	Name string `json:"name"`
 	X    int    `json:"x"`
 	Y    int    `json:"y"`
+
+	// Width, Height - the size of the location in tiles, which players can't
+	// move outside of. A size of 0 leaves the location unbounded that way.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// Blocked - tiles within the location which players can't move onto
+	Blocked Tiles `json:"blocked,omitempty"`
+
+	// Version - incremented on every change, for optimistic concurrency
+	Version uint `json:"version,omitempty"`
 }
 
 // Encode - encode a location as a string
@@ -46,6 +57,101 @@ func (l *Location) Decode(data string) error {
 	return nil
 }
 
+// Blocks - whether a player can't stand at a point within the location
+func (l *Location) Blocks(x, y int) bool {
+	if l.Width > 0 && (x < 0 || x >= l.Width) {
+		return true
+	}
+
+	if l.Height > 0 && (y < 0 || y >= l.Height) {
+		return true
+	}
+
+	return l.Blocked.Contains(x, y)
+}
+
+// Entrance - the first tile of the location, row by row, which isn't blocked.
+// An unbounded direction is only searched as far as one tile past the blocked
+// set, since the blocked tiles can't fill any more rows or columns than that.
+func (l *Location) Entrance() (int, int, bool) {
+	width, height := l.Width, l.Height
+	if width == 0 {
+		width = len(l.Blocked) + 1
+	}
+	if height == 0 {
+		height = len(l.Blocked) + 1
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !l.Blocks(x, y) {
+				return x, y, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+// Tile - a tile within a location
+type Tile struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Tiles - a set of tiles within a location
+type Tiles []Tile
+
+// Contains - whether a point is one of the tiles
+func (t Tiles) Contains(x, y int) bool {
+	for _, tile := range t {
+		if tile.X == x && tile.Y == y {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Encode - encode tiles as a string
+func (t Tiles) Encode() string {
+	parts := make([]string, len(t))
+	for i, tile := range t {
+		parts[i] = fmt.Sprintf("%d:%d", tile.X, tile.Y)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Decode - decode tiles from a string
+func (t *Tiles) Decode(data string) error {
+	*t = nil
+	if len(data) == 0 {
+		return nil
+	}
+
+	for _, part := range strings.Split(data, ",") {
+		coords := strings.SplitN(part, ":", 2)
+		if len(coords) != 2 {
+			return errors.EInternal.NewErrorf("invalid tile encoding \"%s\"", part)
+		}
+
+		x, err := strconv.Atoi(coords[0])
+		if err != nil {
+			return errors.EInternal.NewErrorf("invalid x coordinate \"%s\"", coords[0]).Wrap(err)
+		}
+
+		y, err := strconv.Atoi(coords[1])
+		if err != nil {
+			return errors.EInternal.NewErrorf("invalid y coordinate \"%s\"", coords[1]).Wrap(err)
+		}
+
+		*t = append(*t, Tile{X: x, Y: y})
+	}
+
+	return nil
+}
+
 // Position - point within the game world relative to a location
 type Position struct {
 	Location string `json:"location"`
@@ -3,6 +3,7 @@ package data
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/carsonmyers/bublar-assignment/errors"
 	"github.com/carsonmyers/bublar-assignment/logger"
@@ -16,6 +17,9 @@ type Player struct {
 	Username string    `json:"username"`
 	Password *string   `json:"password,omitempty"`
 	Position *Position `json:"position"`
+
+	// Version - incremented on every change, for optimistic concurrency
+	Version uint `json:"version,omitempty"`
 }
 
 // Encode - encode a user and their position as a string
@@ -44,3 +48,16 @@ func (p *Player) Decode(data string) error {
 
 	return nil
 }
+
+// Violation - a move by a player which broke the speed limit
+type Violation struct {
+	ID       uint      `json:"id"`
+	Username string    `json:"username"`
+	Location string    `json:"location"`
+	From     *Position `json:"from"`
+	To       *Position `json:"to"`
+	Distance float64   `json:"distance"`
+	Allowed  float64   `json:"allowed"`
+	Clamped  bool      `json:"clamped"`
+	Time     time.Time `json:"time"`
+}
@@ -0,0 +1,13 @@
+package data
+
+// RestoreReport - what restoring a snapshot changed in one service
+type RestoreReport struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Restored  int `json:"restored"`
+	Deleted   int `json:"deleted"`
+	Positions int `json:"positions"`
+
+	// Missing - records in the snapshot which could not be brought back
+	Missing []string `json:"missing,omitempty"`
+}
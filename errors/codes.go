@@ -0,0 +1,60 @@
+package errors
+
+import "sort"
+
+// kindCodes - stable identifiers for each kind of error, which clients can
+// rely on even if the descriptions of the kinds change
+var kindCodes = map[Kind]string{
+	EInternal:              "INTERNAL",
+	EDatabaseConnection:    "DATABASE_CONNECTION",
+	EDatabase:              "DATABASE",
+	ERPCConnection:         "RPC_CONNECTION",
+	ERPC:                   "RPC",
+	EAuth:                  "AUTH",
+	EInvalidRequest:        "INVALID_REQUEST",
+	ENotFound:              "NOT_FOUND",
+	ENotImplemented:        "NOT_IMPLEMENTED",
+	EForbidden:             "FORBIDDEN",
+	EDuplicateUser:         "DUPLICATE_USER",
+	ENotInLocation:         "NOT_IN_LOCATION",
+	EDuplicateLocation:     "DUPLICATE_LOCATION",
+	EUnknownLocation:       "UNKNOWN_LOCATION",
+	EVersionMismatch:       "VERSION_MISMATCH",
+	EIdempotencyMismatch:   "IDEMPOTENCY_MISMATCH",
+	EIdempotencyInProgress: "IDEMPOTENCY_IN_PROGRESS",
+	ERateLimited:           "RATE_LIMITED",
+	ETooFast:               "TOO_FAST",
+	EBlocked:               "BLOCKED",
+	EUnknown:               "UNKNOWN",
+}
+
+// Code - get the machine-readable code of an error kind
+func (ek Kind) Code() string {
+	if code, ok := kindCodes[ek]; ok {
+		return code
+	}
+
+	return kindCodes[EUnknown]
+}
+
+// Codes - every machine-readable error code, in alphabetical order
+func Codes() []string {
+	codes := make([]string, 0, len(kindCodes))
+	for _, code := range kindCodes {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+	return codes
+}
+
+// KindFromCode - find the error kind with a machine-readable code
+func KindFromCode(code string) (Kind, bool) {
+	for kind, c := range kindCodes {
+		if c == code {
+			return kind, true
+		}
+	}
+
+	return EUnknown, false
+}
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -49,6 +50,29 @@ const (
 	// EDuplicateLocation - a location name is already taken
 	EDuplicateLocation = Kind("location already exists")
 
+	// EUnknownLocation - a player tried to travel to a location which does not exist
+	EUnknownLocation = Kind("location does not exist")
+
+	// EVersionMismatch - a resource was changed since the version the client expected
+	EVersionMismatch = Kind("version mismatch")
+
+	// EIdempotencyMismatch - an idempotency key was reused for a different request
+	EIdempotencyMismatch = Kind("idempotency key reused")
+
+	// EIdempotencyInProgress - a request with the same idempotency key hasn't finished
+	EIdempotencyInProgress = Kind("request in progress")
+
+	// ERateLimited - too many requests, or too many failed logins
+	ERateLimited = Kind("too many requests")
+
+	// ETooFast - a player tried to move further than they could have since
+	// their last move
+	ETooFast = Kind("moving too fast")
+
+	// EBlocked - a player tried to move outside of their location or onto a
+	// blocked tile
+	EBlocked = Kind("position blocked")
+
 	// EUnknown - an unknown error occurred
 	EUnknown = Kind("unknown error")
 )
@@ -90,10 +114,23 @@ func (ek Kind) NewError(contents interface{}) *Error {
 
 // Error - generalized error structure for internal and external use
 type Error struct {
-	Ctx     string      `json:"ctx"`
-	Kind    Kind        `json:"kind"`
-	Message string      `json:"message"`
-	Inner   interface{} `json:"-"`
+	Ctx     string                 `json:"ctx"`
+	Kind    Kind                   `json:"kind"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	Inner   interface{}            `json:"-"`
+}
+
+// MarshalJSON - encode the error along with the code of its kind
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type plain Error
+	return json.Marshal(&struct {
+		Code string `json:"code"`
+		*plain
+	}{
+		Code:  e.Kind.Code(),
+		plain: (*plain)(e),
+	})
 }
 
 func (e *Error) Error() string {
@@ -112,12 +149,12 @@ func (e *Error) Error() string {
 		case string:
 			sb.WriteString(target.(string))
 			target = nil
+		case *Error:
+			sb.WriteString(target.(*Error).Message)
+			target = target.(*Error).Inner
 		case error:
 			sb.WriteString(target.(error).Error())
 			target = nil
-		case *Error:
-			sb.WriteString(target.(*Error).Error())
-			target = target.(Error).Inner
 		default:
 			panic("Invalid error type")
 		}
@@ -126,12 +163,28 @@ func (e *Error) Error() string {
 	return sb.String()
 }
 
+// IsKind - check whether an error is an Error of the given kind
+func IsKind(err error, kind Kind) bool {
+	e, ok := err.(*Error)
+	return ok && e.Kind == kind
+}
+
 // WithContext - add a context (like and input parameter) to an error
 func (e *Error) WithContext(ctx string) *Error {
 	e.Ctx = ctx
 	return e
 }
 
+// WithDetail - add a piece of structured information to an error
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+
+	e.Details[key] = value
+	return e
+}
+
 // Wrap an inner error with this one
 func (e *Error) Wrap(contents interface{}) *Error {
 	outer := e
@@ -160,6 +213,10 @@ func (e Error) StatusCode() int {
 	switch e.Kind {
 	case EInternal, EDatabaseConnection, EDatabase:
 		return http.StatusInternalServerError
+	case ERPCConnection:
+		return http.StatusServiceUnavailable
+	case EAuth:
+		return http.StatusUnauthorized
 	case EInvalidRequest:
 		return http.StatusBadRequest
 	case ENotFound:
@@ -169,9 +226,17 @@ func (e Error) StatusCode() int {
 	case EForbidden:
 		return http.StatusForbidden
 	case EDuplicateUser, EDuplicateLocation:
+		return http.StatusConflict
+	case ENotInLocation, EUnknownLocation, ETooFast, EBlocked:
 		return http.StatusBadRequest
-	case ENotInLocation:
-		return http.StatusBadRequest
+	case EVersionMismatch:
+		return http.StatusPreconditionFailed
+	case EIdempotencyMismatch:
+		return http.StatusUnprocessableEntity
+	case EIdempotencyInProgress:
+		return http.StatusConflict
+	case ERateLimited:
+		return http.StatusTooManyRequests
 	case EUnknown:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+// statusCodes - the HTTP status of each error code, as documented in the
+// README's table of errors
+var statusCodes = map[string]int{
+	"INVALID_REQUEST":         http.StatusBadRequest,
+	"AUTH":                    http.StatusUnauthorized,
+	"FORBIDDEN":               http.StatusForbidden,
+	"NOT_FOUND":               http.StatusNotFound,
+	"DUPLICATE_USER":          http.StatusConflict,
+	"DUPLICATE_LOCATION":      http.StatusConflict,
+	"UNKNOWN_LOCATION":        http.StatusBadRequest,
+	"NOT_IN_LOCATION":         http.StatusBadRequest,
+	"TOO_FAST":                http.StatusBadRequest,
+	"BLOCKED":                 http.StatusBadRequest,
+	"VERSION_MISMATCH":        http.StatusPreconditionFailed,
+	"IDEMPOTENCY_MISMATCH":    http.StatusUnprocessableEntity,
+	"IDEMPOTENCY_IN_PROGRESS": http.StatusConflict,
+	"RATE_LIMITED":            http.StatusTooManyRequests,
+	"NOT_IMPLEMENTED":         http.StatusNotImplemented,
+	"RPC_CONNECTION":          http.StatusServiceUnavailable,
+	"RPC":                     http.StatusInternalServerError,
+	"DATABASE_CONNECTION":     http.StatusInternalServerError,
+	"DATABASE":                http.StatusInternalServerError,
+	"INTERNAL":                http.StatusInternalServerError,
+	"UNKNOWN":                 http.StatusInternalServerError,
+}
+
+func TestStatusCode(t *testing.T) {
+	for _, code := range Codes() {
+		t.Run(code, func(t *testing.T) {
+			want, ok := statusCodes[code]
+			if !ok {
+				t.Fatalf("no HTTP status listed for %s", code)
+			}
+
+			kind, ok := KindFromCode(code)
+			if !ok {
+				t.Fatalf("no kind has code %s", code)
+			}
+
+			if got := kind.NewError("test").StatusCode(); got != want {
+				t.Errorf("%s has HTTP status %d, want %d", code, got, want)
+			}
+		})
+	}
+}
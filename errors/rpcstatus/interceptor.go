@@ -0,0 +1,43 @@
+package rpcstatus
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor - send the errors returned by unary gRPC handlers as
+// statuses
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	res, err := handler(ctx, req)
+	return res, ToStatus(err)
+}
+
+// StreamServerInterceptor - send the errors returned by streaming gRPC
+// handlers as statuses
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return ToStatus(handler(srv, ss))
+}
+
+// UnaryClientInterceptor - recover the errors returned by unary gRPC calls
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return FromStatus(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// StreamClientInterceptor - recover the errors returned by streaming gRPC calls
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, FromStatus(err)
+	}
+
+	return &clientStream{stream}, nil
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return FromStatus(s.ClientStream.RecvMsg(m))
+}
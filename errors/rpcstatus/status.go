@@ -0,0 +1,131 @@
+package rpcstatus
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Code - derive a gRPC status code from an error kind
+func Code(kind errors.Kind) codes.Code {
+	switch kind {
+	case errors.EInternal, errors.EDatabase:
+		return codes.Internal
+	case errors.EDatabaseConnection, errors.ERPCConnection:
+		return codes.Unavailable
+	case errors.EAuth:
+		return codes.Unauthenticated
+	case errors.EInvalidRequest, errors.EUnknownLocation, errors.EIdempotencyMismatch:
+		return codes.InvalidArgument
+	case errors.ENotFound:
+		return codes.NotFound
+	case errors.ENotImplemented:
+		return codes.Unimplemented
+	case errors.EForbidden:
+		return codes.PermissionDenied
+	case errors.EDuplicateUser, errors.EDuplicateLocation:
+		return codes.AlreadyExists
+	case errors.ENotInLocation, errors.ETooFast, errors.EBlocked:
+		return codes.FailedPrecondition
+	case errors.EVersionMismatch, errors.EIdempotencyInProgress:
+		return codes.Aborted
+	case errors.ERateLimited:
+		return codes.ResourceExhausted
+	}
+
+	return codes.Unknown
+}
+
+// Problem - convert an error to its message
+func Problem(e *errors.Error) *proto.Problem {
+	problem := &proto.Problem{
+		Kind:    string(e.Kind),
+		Ctx:     e.Ctx,
+		Message: e.Error(),
+		Code:    e.Kind.Code(),
+	}
+
+	if len(e.Details) > 0 {
+		details, err := json.Marshal(e.Details)
+		if err == nil {
+			problem.Details = details
+		}
+	}
+
+	return problem
+}
+
+// FromProblem - recover the error a problem message was made from
+func FromProblem(problem *proto.Problem) *errors.Error {
+	e := &errors.Error{
+		Kind:    errors.Kind(problem.GetKind()),
+		Ctx:     problem.GetCtx(),
+		Message: problem.GetMessage(),
+	}
+
+	if details := problem.GetDetails(); len(details) > 0 {
+		json.Unmarshal(details, &e.Details)
+	}
+
+	return e
+}
+
+// Status - convert an error to a gRPC status carrying its kind and context
+func Status(e *errors.Error) *status.Status {
+	st := status.New(Code(e.Kind), e.Error())
+	detailed, err := st.WithDetails(Problem(e))
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// ToStatus - convert the error returned by a gRPC service to the status sent
+// to its caller. Errors which aren't *errors.Error are returned unchanged.
+func ToStatus(err error) error {
+	if e, ok := err.(*errors.Error); ok {
+		return Status(e).Err()
+	}
+
+	return err
+}
+
+// FromStatus - recover the error returned by a gRPC service. Errors without
+// a problem attached become ERPC errors, except for cancellations and
+// deadlines; errors which aren't gRPC statuses are returned unchanged.
+func FromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(*errors.Error); ok {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		if problem, ok := detail.(*proto.Problem); ok {
+			return FromProblem(problem)
+		}
+	}
+
+	switch st.Code() {
+	case codes.Canceled:
+		return context.Canceled
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
+	case codes.Unavailable:
+		return errors.ERPCConnection.NewError(st.Message())
+	}
+
+	return errors.ERPC.NewError(st.Message())
+}
@@ -0,0 +1,106 @@
+package rpcstatus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusCodes - the gRPC status code of each error code
+var statusCodes = map[string]codes.Code{
+	"INVALID_REQUEST":         codes.InvalidArgument,
+	"AUTH":                    codes.Unauthenticated,
+	"FORBIDDEN":               codes.PermissionDenied,
+	"NOT_FOUND":               codes.NotFound,
+	"DUPLICATE_USER":          codes.AlreadyExists,
+	"DUPLICATE_LOCATION":      codes.AlreadyExists,
+	"UNKNOWN_LOCATION":        codes.InvalidArgument,
+	"NOT_IN_LOCATION":         codes.FailedPrecondition,
+	"TOO_FAST":                codes.FailedPrecondition,
+	"BLOCKED":                 codes.FailedPrecondition,
+	"VERSION_MISMATCH":        codes.Aborted,
+	"IDEMPOTENCY_MISMATCH":    codes.InvalidArgument,
+	"IDEMPOTENCY_IN_PROGRESS": codes.Aborted,
+	"RATE_LIMITED":            codes.ResourceExhausted,
+	"NOT_IMPLEMENTED":         codes.Unimplemented,
+	"RPC_CONNECTION":          codes.Unavailable,
+	"RPC":                     codes.Unknown,
+	"DATABASE_CONNECTION":     codes.Unavailable,
+	"DATABASE":                codes.Internal,
+	"INTERNAL":                codes.Internal,
+	"UNKNOWN":                 codes.Unknown,
+}
+
+// TestRoundTrip - every kind of error is sent with its status code, and
+// comes back out of the status unchanged
+func TestRoundTrip(t *testing.T) {
+	for _, code := range errors.Codes() {
+		t.Run(code, func(t *testing.T) {
+			want, ok := statusCodes[code]
+			if !ok {
+				t.Fatalf("no gRPC status code listed for %s", code)
+			}
+
+			kind, ok := errors.KindFromCode(code)
+			if !ok {
+				t.Fatalf("no kind has code %s", code)
+			}
+
+			sent := kind.NewError("test").WithContext("ctx").WithDetail("location", "town")
+			err := ToStatus(sent)
+
+			if got := status.Code(err); got != want {
+				t.Errorf("sent with status code %s, want %s", got, want)
+			}
+
+			received, ok := FromStatus(err).(*errors.Error)
+			if !ok {
+				t.Fatalf("got %T back, want *errors.Error", FromStatus(err))
+			}
+
+			if received.Kind != sent.Kind || received.Ctx != sent.Ctx || received.Error() != sent.Error() {
+				t.Errorf("got %s %q %q back, want %s %q %q", received.Kind, received.Ctx, received.Error(), sent.Kind, sent.Ctx, sent.Error())
+			}
+
+			if !reflect.DeepEqual(received.Details, sent.Details) {
+				t.Errorf("got details %v back, want %v", received.Details, sent.Details)
+			}
+		})
+	}
+}
+
+// TestFromStatus - statuses without a problem attached
+func TestFromStatus(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want error
+		kind errors.Kind
+	}{
+		{"nil", nil, nil, ""},
+		{"canceled", status.Error(codes.Canceled, "canceled"), context.Canceled, ""},
+		{"deadline", status.Error(codes.DeadlineExceeded, "too slow"), context.DeadlineExceeded, ""},
+		{"unavailable", status.Error(codes.Unavailable, "down"), nil, errors.ERPCConnection},
+		{"other", status.Error(codes.Internal, "broken"), nil, errors.ERPC},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := FromStatus(c.err)
+			if len(c.kind) > 0 {
+				if !errors.IsKind(got, c.kind) {
+					t.Errorf("got %v, want a %s error", got, c.kind)
+				}
+				return
+			}
+
+			if got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
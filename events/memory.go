@@ -0,0 +1,67 @@
+package events
+
+import (
+	"sync"
+)
+
+type memoryBus struct {
+	mu          sync.RWMutex
+	subscribers map[*memorySubscription]struct{}
+}
+
+// NewMemoryBus - create an event bus which delivers events within this process
+func NewMemoryBus() Bus {
+	return &memoryBus{
+		subscribers: make(map[*memorySubscription]struct{}),
+	}
+}
+
+func (b *memoryBus) Publish(event *Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			log.Warn("Dropped event for slow subscriber")
+		}
+	}
+
+	return nil
+}
+
+func (b *memoryBus) Subscribe() (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &memorySubscription{
+		bus:    b,
+		events: make(chan *Event, 64),
+	}
+
+	b.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+type memorySubscription struct {
+	bus    *memoryBus
+	events chan *Event
+	once   sync.Once
+}
+
+func (s *memorySubscription) Events() <-chan *Event {
+	return s.events
+}
+
+func (s *memorySubscription) Close() error {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+
+		delete(s.bus.subscribers, s)
+		close(s.events)
+	})
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package events
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/logger"
+)
+
+var log = logger.GetLogger()
+
+const (
+	// LocationRenamed - a location's name changed
+	LocationRenamed = "location.renamed"
+
+	// LocationUpdated - a location's details changed without it being renamed
+	LocationUpdated = "location.updated"
+
+	// LocationDeleted - a location was deleted, and can be restored until it
+	// is purged
+	LocationDeleted = "location.deleted"
+
+	// LocationRestored - a deleted location was restored
+	LocationRestored = "location.restored"
+)
+
+// Event - notification of a change to the game world
+type Event struct {
+	Kind     string    `json:"kind"`
+	Location string    `json:"location"`
+	Previous string    `json:"previous,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Subscription - a stream of events which must be closed when no longer needed
+type Subscription interface {
+	Events() <-chan *Event
+	Close() error
+}
+
+// Bus - publishes events to every subscriber, in this or any other service
+type Bus interface {
+	Publish(event *Event) error
+	Subscribe() (Subscription, error)
+}
+
+var bus Bus
+
+// SetBus - override the configured event bus
+func SetBus(b Bus) {
+	bus = b
+}
+
+// GetBus - get the configured event bus
+func GetBus() Bus {
+	if bus != nil {
+		return bus
+	}
+
+	// without redis there is nothing to share events between processes, so
+	// those backends only deliver events within a single (standalone) process
+	switch configure.GetStorage().Backend {
+	case configure.BackendMemory, configure.BackendSQLite:
+		bus = NewMemoryBus()
+	default:
+		bus = NewRedisBus()
+	}
+
+	return bus
+}
+
+// Publish - publish an event on the configured bus, stamping it with the current time
+func Publish(kind, location, previous string) error {
+	return GetBus().Publish(&Event{
+		Kind:     kind,
+		Location: location,
+		Previous: previous,
+		Time:     time.Now(),
+	})
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"encoding/json"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+const channel = "events"
+
+type redisBus struct{}
+
+// NewRedisBus - create an event bus using redis pub/sub
+func NewRedisBus() Bus {
+	return &redisBus{}
+}
+
+func (b *redisBus) Publish(event *Event) error {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.EInternal.NewError(err)
+	}
+
+	if err := rdb.Publish(channel, payload).Err(); err != nil {
+		log.Error("Failed to publish event", zap.String("kind", event.Kind), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (b *redisBus) Subscribe() (Subscription, error) {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	pubsub := rdb.Subscribe(channel)
+	if _, err := pubsub.Receive(); err != nil {
+		pubsub.Close()
+		log.Error("Failed to subscribe to events", zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	sub := &redisSubscription{
+		pubsub: pubsub,
+		events: make(chan *Event),
+	}
+
+	go sub.forward()
+	return sub, nil
+}
+
+type redisSubscription struct {
+	pubsub *redis.PubSub
+	events chan *Event
+}
+
+func (s *redisSubscription) forward() {
+	defer close(s.events)
+
+	for msg := range s.pubsub.Channel() {
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			log.Error("Failed to decode event", zap.String("payload", msg.Payload), zap.Error(err))
+			continue
+		}
+
+		s.events <- &event
+	}
+}
+
+func (s *redisSubscription) Events() <-chan *Event {
+	return s.events
+}
+
+func (s *redisSubscription) Close() error {
+	return s.pubsub.Close()
+}
@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// purgeInterval - how often expired records are removed from a memory store
+const purgeInterval = time.Minute
+
+type memoryEntry struct {
+	record  *Record
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	nextPurge time.Time
+}
+
+// NewMemoryStore - create a store which keeps records within this process
+func NewMemoryStore() Store {
+	return &memoryStore{
+		entries:   make(map[string]*memoryEntry),
+		nextPurge: time.Now().Add(purgeInterval),
+	}
+}
+
+func (s *memoryStore) Begin(key, fingerprint string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextPurge) {
+		s.purge(now)
+	}
+
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expires) {
+		return entry.record, nil
+	}
+
+	s.entries[key] = &memoryEntry{
+		record:  &Record{Fingerprint: fingerprint},
+		expires: now.Add(pendingTTL),
+	}
+
+	return nil, nil
+}
+
+func (s *memoryStore) Finish(key string, record *Record, window time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &memoryEntry{
+		record:  record,
+		expires: time.Now().Add(window),
+	}
+
+	return nil
+}
+
+func (s *memoryStore) Abandon(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// purge - remove expired records. The lock must be held.
+func (s *memoryStore) purge(now time.Time) {
+	for key, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+
+	s.nextPurge = now.Add(purgeInterval)
+}
@@ -0,0 +1,64 @@
+package idempotency
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/logger"
+)
+
+var log = logger.GetLogger()
+
+// pendingTTL - how long a request holds its key before it finishes. A request
+// which never finishes (e.g. the API crashed) only blocks its key this long,
+// rather than for the whole window.
+const pendingTTL = time.Minute
+
+// Record - the response to the first request made with a key. A record which
+// isn't complete belongs to a request which is still being handled.
+type Record struct {
+	Fingerprint string      `json:"fingerprint"`
+	Complete    bool        `json:"complete"`
+	StatusCode  int         `json:"statusCode,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// Store - keeps the responses of requests by their idempotency key
+type Store interface {
+	// Begin - claim a key for a request with a fingerprint. If the key was
+	// already claimed, its record is returned instead.
+	Begin(key, fingerprint string) (*Record, error)
+
+	// Finish - store the response to a request, replacing its claim
+	Finish(key string, record *Record, window time.Duration) error
+
+	// Abandon - release a claim, so the request can be retried
+	Abandon(key string) error
+}
+
+var store Store
+
+// SetStore - override the configured store
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore - get the configured store
+func GetStore() Store {
+	if store != nil {
+		return store
+	}
+
+	// as with events, a standalone process without redis keeps its keys in
+	// memory
+	switch configure.GetStorage().Backend {
+	case configure.BackendMemory, configure.BackendSQLite:
+		store = NewMemoryStore()
+	default:
+		store = NewRedisStore()
+	}
+
+	return store
+}
@@ -0,0 +1,134 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+const prefix = "idempotency:"
+
+// retryRedis - how long keys are kept in memory once redis can't be reached,
+// before connecting to it again
+const retryRedis = 30 * time.Second
+
+type redisStore struct {
+	// fallback - keeps keys while redis can't be reached, so requests with a
+	// key aren't refused while it is down
+	fallback Store
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+// NewRedisStore - create a store shared by every API process through redis.
+// While redis can't be reached, keys are kept within this process instead.
+func NewRedisStore() Store {
+	return &redisStore{fallback: NewMemoryStore()}
+}
+
+// client - connect to redis, or nil if it can't be reached
+func (s *redisStore) client() *redis.Client {
+	s.mu.Lock()
+	down := time.Now().Before(s.downUntil)
+	s.mu.Unlock()
+
+	if down {
+		return nil
+	}
+
+	rdb, err := connect.Redis()
+	if err != nil {
+		log.Warn("Keeping idempotency keys in memory until redis can be reached", zap.Duration("retry", retryRedis), zap.Error(err))
+
+		s.mu.Lock()
+		s.downUntil = time.Now().Add(retryRedis)
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	return rdb
+}
+
+func (s *redisStore) Begin(key, fingerprint string) (*Record, error) {
+	rdb := s.client()
+	if rdb == nil {
+		return s.fallback.Begin(key, fingerprint)
+	}
+
+	payload, err := json.Marshal(&Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, errors.EInternal.NewError(err)
+	}
+
+	// the claim can expire between failing to set it and reading it back, in
+	// which case the key is free to claim again
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed, err := rdb.SetNX(prefix+key, payload, pendingTTL).Result()
+		if err != nil {
+			log.Error("Failed to claim idempotency key", zap.String("key", key), zap.Error(err))
+			return nil, errors.EDatabase.NewError(err)
+		}
+
+		if claimed {
+			return nil, nil
+		}
+
+		existing, err := rdb.Get(prefix + key).Bytes()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			log.Error("Failed to get idempotency record", zap.String("key", key), zap.Error(err))
+			return nil, errors.EDatabase.NewError(err)
+		}
+
+		var record Record
+		if err := json.Unmarshal(existing, &record); err != nil {
+			log.Error("Failed to decode idempotency record", zap.String("key", key), zap.Error(err))
+			return nil, errors.EInternal.NewError(err)
+		}
+
+		return &record, nil
+	}
+
+	return nil, errors.EDatabase.NewErrorf("failed to claim idempotency key %s", key)
+}
+
+func (s *redisStore) Finish(key string, record *Record, window time.Duration) error {
+	rdb := s.client()
+	if rdb == nil {
+		return s.fallback.Finish(key, record, window)
+	}
+
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return errors.EInternal.NewError(err)
+	}
+
+	if err := rdb.Set(prefix+key, payload, window).Err(); err != nil {
+		log.Error("Failed to store idempotency record", zap.String("key", key), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *redisStore) Abandon(key string) error {
+	rdb := s.client()
+	if rdb == nil {
+		return s.fallback.Abandon(key)
+	}
+
+	if err := rdb.Del(prefix + key).Err(); err != nil {
+		log.Error("Failed to release idempotency key", zap.String("key", key), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/carsonmyers/bublar-assignment/configure"
+)
+
+// TestRedisFallback - keys are kept in memory while redis can't be reached,
+// and in redis once it can
+func TestRedisFallback(t *testing.T) {
+	// a port nothing is listening on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("finding a free port: %v", err)
+	}
+	port := uint(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+
+	configure.Redis(&configure.RedisConfig{Host: "127.0.0.1", Port: port})
+	defer configure.Redis(nil)
+
+	store := NewRedisStore()
+
+	existing, err := store.Begin("bob/1", "a")
+	if err != nil || existing != nil {
+		t.Fatalf("claiming a key without redis: got %v, %v", existing, err)
+	}
+
+	if err := store.Finish("bob/1", &Record{Fingerprint: "a", Complete: true, StatusCode: 201}, time.Minute); err != nil {
+		t.Fatalf("finishing a key without redis: %v", err)
+	}
+
+	existing, err = store.Begin("bob/1", "a")
+	if err != nil || existing == nil || existing.StatusCode != 201 {
+		t.Fatalf("repeating a key without redis: got %v, %v", existing, err)
+	}
+
+	srv, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("starting miniredis: %v", err)
+	}
+	defer srv.Close()
+
+	configure.Redis(&configure.RedisConfig{
+		Host: srv.Host(),
+		Port: uint(srv.Server().Addr().Port),
+	})
+
+	// redis is only tried again once the retry interval has passed
+	store.(*redisStore).downUntil = time.Time{}
+
+	if existing, err := store.Begin("bob/2", "b"); err != nil || existing != nil {
+		t.Fatalf("claiming a key with redis: got %v, %v", existing, err)
+	}
+
+	if !srv.Exists(prefix + "bob/2") {
+		t.Error("key was not claimed in redis once it could be reached")
+	}
+}
@@ -0,0 +1,270 @@
+package locations
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/positions"
+	"github.com/jinzhu/gorm"
+	"go.uber.org/zap"
+)
+
+type gormStore struct{}
+
+// NewGormStore - create a location store backed by an SQL database
+func NewGormStore() Store {
+	return &gormStore{}
+}
+
+func (s *gormStore) Exists(name string) (bool, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return false, errors.EDatabaseConnection.NewError(err)
+	}
+
+	// deleted locations keep their name reserved until they are purged
+	var count uint64
+	q := db.Unscoped().Model(&Location{}).Where(&Location{Name: name}).Count(&count)
+	if err := q.Error; err != nil {
+		log.Error("Error counting existing locations", zap.Error(err))
+		return false, errors.EDatabase.NewError(err)
+	}
+
+	return count != 0, nil
+}
+
+func (s *gormStore) Create(location *Location) error {
+	db, err := connect.Database()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	if err := db.Create(location).Error; err != nil {
+		log.Error("Failed to store new location", zap.String("name", location.Name), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) Get(name string) (*Location, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var location Location
+	if err := db.Where(&Location{Name: name}).First(&location).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.ENotFound.NewError(err)
+		}
+
+		log.Error("Error fetching location data", zap.String("name", name), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return &location, nil
+}
+
+// sortColumns - columns corresponding to each sort field
+var sortColumns = map[string]string{
+	SortName:    "name",
+	SortCreated: "created_at",
+}
+
+func (s *gormStore) List(query *ListQuery) ([]*Location, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	q := db.Model(&Location{})
+	if len(query.Prefix) > 0 {
+		q = q.Where(`name LIKE ? ESCAPE '\'`, paging.EscapeLike(query.Prefix)+"%")
+	}
+
+	column := sortColumns[query.Sort]
+	op := query.Operator()
+	if after := query.After; after != nil {
+		if column == "name" {
+			q = q.Where("name "+op+" ?", after.Key)
+		} else {
+			value, err := paging.ParseTime(after.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			q = q.Where(column+" "+op+" ? OR ("+column+" = ? AND name "+op+" ?)", value, value, after.Key)
+		}
+	}
+
+	if column != "name" {
+		q = q.Order(column + " " + query.Direction())
+	}
+
+	var locations []*Location
+	q = q.Order("name " + query.Direction()).Limit(query.Limit)
+	if err := q.Find(&locations).Error; err != nil {
+		log.Error("Error fetching all locations", zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return locations, nil
+}
+
+func (s *gormStore) Update(name string, location *Location, hook UpdateHook) (*Location, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	tx := db.Begin()
+	if err := tx.Error; err != nil {
+		log.Error("Failed to begin transaction", zap.String("name", name), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	// update through the table rather than the model, otherwise gorm puts the
+	// new primary key into the WHERE clause and nothing matches
+	q := tx.Table(tx.NewScope(&Location{}).TableName()).Where(&Location{Name: name, Version: location.Version}).Where("deleted_at IS NULL").Updates(map[string]interface{}{
+		"name":       location.Name,
+		"x":          location.X,
+		"y":          location.Y,
+		"width":      location.Width,
+		"height":     location.Height,
+		"blocked":    location.Blocked,
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	})
+	if err := q.Error; err != nil {
+		tx.Rollback()
+		log.Error("Failed to patch location", zap.String("name", name), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	if q.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, s.missing(name, location.Version)
+	}
+
+	var updated Location
+	if err := tx.Where(&Location{Name: location.Name}).First(&updated).Error; err != nil {
+		tx.Rollback()
+		log.Error("Failed to fetch patched location", zap.String("name", location.Name), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	if hook != nil {
+		if err := hook(&updated, positions.Within(tx)); err != nil {
+			tx.Rollback()
+			log.Error("Rolled back location update", zap.String("name", name), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("Failed to commit location update", zap.String("name", name), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return &updated, nil
+}
+
+func (s *gormStore) Delete(name string, version uint, hook DeleteHook) error {
+	db, err := connect.Database()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	tx := db.Begin()
+	if err := tx.Error; err != nil {
+		log.Error("Failed to begin transaction", zap.String("name", name), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	q := tx.Where("name = ?", name)
+	if version != 0 {
+		q = q.Where("version = ?", version)
+	}
+
+	q = q.Delete(&Location{})
+	if err := q.Error; err != nil {
+		tx.Rollback()
+		log.Error("Error deleting location", zap.String("name", name), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	if q.RowsAffected == 0 {
+		tx.Rollback()
+		log.Error("Location does not exist at expected version", zap.String("name", name), zap.Uint("version", version))
+		return s.missing(name, version)
+	}
+
+	if hook != nil {
+		if err := hook(positions.Within(tx)); err != nil {
+			tx.Rollback()
+			log.Error("Rolled back location deletion", zap.String("name", name), zap.Error(err))
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("Failed to commit location deletion", zap.String("name", name), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) Restore(name string) (*Location, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	q := db.Unscoped().Table(db.NewScope(&Location{}).TableName()).Where("name = ? AND deleted_at IS NOT NULL", name).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	})
+	if err := q.Error; err != nil {
+		log.Error("Failed to restore location", zap.String("name", name), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	if q.RowsAffected == 0 {
+		return nil, errors.ENotFound.NewError("no deleted location to restore")
+	}
+
+	return s.Get(name)
+}
+
+func (s *gormStore) Purge(before time.Time) (int, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return 0, errors.EDatabaseConnection.NewError(err)
+	}
+
+	q := db.Unscoped().Where("deleted_at < ?", before).Delete(&Location{})
+	if err := q.Error; err != nil {
+		log.Error("Failed to purge deleted locations", zap.Time("before", before), zap.Error(err))
+		return 0, errors.EDatabase.NewError(err)
+	}
+
+	return int(q.RowsAffected), nil
+}
+
+// missing - explain why a conditional write to a location matched nothing
+func (s *gormStore) missing(name string, version uint) error {
+	if version == 0 {
+		return errors.ENotFound.NewError("location does not exist")
+	}
+
+	if _, err := s.Get(name); err != nil {
+		return err
+	}
+
+	return versionMismatch(name, version)
+}
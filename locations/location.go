@@ -1,66 +1,82 @@
 package locations
 
 import (
-	"fmt"
 	"time"
 
-	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/configure"
 	"github.com/carsonmyers/bublar-assignment/data"
 	"github.com/carsonmyers/bublar-assignment/errors"
-	"github.com/jinzhu/gorm"
+	"github.com/carsonmyers/bublar-assignment/events"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/positions"
+	"github.com/carsonmyers/bublar-assignment/validate"
 	"go.uber.org/zap"
 )
 
-var exp = 48 * time.Hour
-
 // Location - a location within the game world
 type Location struct {
-	Name      string    `json:"name" gorm:"primary_key"`
-	X         int       `json:"x"`
-	Y         int       `json:"y"`
-	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
-	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+	Name      string     `json:"name" gorm:"primary_key"`
+	X         int        `json:"x"`
+	Y         int        `json:"y"`
+	Width     int        `json:"width" gorm:"not null;default:0"`
+	Height    int        `json:"height" gorm:"not null;default:0"`
+	Blocked   string     `json:"blocked" gorm:"type:text"`
+	Version   uint       `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time  `json:"updatedAt" gorm:"type:timestamp"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty" gorm:"type:timestamp;index"`
 }
 
 // ToLocation - convert to universal data format
 func (l *Location) ToLocation() *data.Location {
+	var blocked data.Tiles
+	if err := blocked.Decode(l.Blocked); err != nil {
+		log.Error("Failed to decode blocked tiles", zap.String("name", l.Name), zap.Error(err))
+	}
+
 	return &data.Location{
-		Name: l.Name,
-		X:    l.X,
-		Y:    l.Y,
+		Name:    l.Name,
+		X:       l.X,
+		Y:       l.Y,
+		Width:   l.Width,
+		Height:  l.Height,
+		Blocked: blocked,
+		Version: l.Version,
 	}
 }
 
 // CreateLocation - create a new location in the game world
 func CreateLocation(location *data.Location) (*data.Location, error) {
-	db, err := connect.Postgres()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+	if err := validate.New().Check("name", location.Name, validate.LocationName...).Layout("", location).Err(); err != nil {
+		return nil, err
 	}
 
-	var count uint64
-	q := db.Model(&Location{}).Where(&Location{Name: location.Name}).Count(&count)
-	if err := q.Error; err != nil {
-		log.Error("Error counting existing locations", zap.Error(err))
-		return nil, errors.EDatabase.NewError(location.Name)
+	locations := GetStore()
+
+	exists, err := locations.Exists(location.Name)
+	if err != nil {
+		return nil, err
 	}
 
-	if count != 0 {
-		log.Error("Attempt to create a duplicate location", zap.String("name", location.Name), zap.Error(err))
-		return nil, errors.EDuplicateLocation.NewError(err)
+	if exists {
+		log.Error("Attempt to create a duplicate location", zap.String("name", location.Name))
+		return nil, errors.EDuplicateLocation.NewError(location.Name).WithDetail("name", location.Name)
 	}
 
 	locationModel := Location{
 		Name:      location.Name,
 		X:         location.X,
 		Y:         location.Y,
+		Width:     location.Width,
+		Height:    location.Height,
+		Blocked:   location.Blocked.Encode(),
+		Version:   1,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := db.Create(&locationModel).Error; err != nil {
-		log.Error("Failed to store new location", zap.String("name", location.Name), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+	if err := locations.Create(&locationModel); err != nil {
+		return nil, err
 	}
 
 	return locationModel.ToLocation(), nil
@@ -68,35 +84,33 @@ func CreateLocation(location *data.Location) (*data.Location, error) {
 
 // GetLocation - get a location by name
 func GetLocation(id string) (*data.Location, error) {
-	db, err := connect.Postgres()
+	location, err := GetStore().Get(id)
 	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
-	}
-
-	var location Location
-	if err := db.Where(&Location{Name: id}).First(&location).Error; err != nil {
-		if gorm.IsRecordNotFoundError(err) {
-			return nil, errors.ENotFound.NewError(err)
-		}
-
-		log.Error("Error fetching location data", zap.String("name", location.Name), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+		return nil, err
 	}
 
 	return location.ToLocation(), nil
 }
 
-// ListLocations - list all locations in the game
-func ListLocations() ([]*data.Location, error) {
-	db, err := connect.Postgres()
+// ListLocations - list one page of locations in the game, along with a cursor
+// for the next page if there is one
+func ListLocations(page *paging.Query, prefix string) ([]*data.Location, *paging.Cursor, error) {
+	// one extra location is fetched to find out whether there is another page
+	query := &ListQuery{
+		Query:  *page,
+		Prefix: prefix,
+	}
+	query.Limit = page.Limit + 1
+
+	locations, err := GetStore().List(query)
 	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+		return nil, nil, err
 	}
 
-	var locations []*Location
-	if err := db.Find(&locations).Error; err != nil {
-		log.Error("Error fetching all locations", zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+	var next *paging.Cursor
+	if len(locations) > page.Limit {
+		locations = locations[:page.Limit]
+		next = locations[page.Limit-1].Cursor(page.Sort)
 	}
 
 	response := make([]*data.Location, len(locations))
@@ -104,75 +118,67 @@ func ListLocations() ([]*data.Location, error) {
 		response[i] = location.ToLocation()
 	}
 
-	return response, nil
+	return response, next, nil
 }
 
 // UpdateLocation - update the details of a location
 func UpdateLocation(id string, location *data.Location) (*data.Location, error) {
-	pdb, err := connect.Postgres()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+	if err := validate.New().Layout("", location).Err(); err != nil {
+		return nil, err
 	}
 
-	var updated Location
-	q := pdb.Raw(`
-	UPDATE "location"
-	SET
-		"name" = ?,
-		"x" = ?,
-		"y" = ?,
-		"updated_at" = ?
-	WHERE
-		"name" = ?
-	RETURNING
-		"location".*
-	`, location.Name, location.X, location.Y, time.Now(), id).Scan(&updated)
-	if err := q.Error; err != nil {
-		log.Error("Failed to patch location", zap.String("name", id), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
-	}
-
-	if q.RowsAffected == 0 {
-		return nil, errors.EDatabase.NewError("location not found")
-	}
+	if location.Name != id {
+		if err := validate.New().Check("name", location.Name, validate.LocationName...).Err(); err != nil {
+			return nil, err
+		}
 
-	rdb, err := connect.Redis()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
-	}
+		exists, err := GetStore().Exists(location.Name)
+		if err != nil {
+			return nil, err
+		}
 
-	key := fmt.Sprintf("location:%s", id)
-	members, err := rdb.SMembers(key).Result()
-	if err != nil {
-		log.Error("Error fetching players from updated location", zap.String("location", id), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+		if exists {
+			return nil, errors.EDuplicateLocation.NewError(location.Name).WithDetail("name", location.Name)
+		}
 	}
 
-	newKey := fmt.Sprintf("location:%s", location.Name)
-	for _, member := range members {
-		p := &data.Player{}
-		if err := p.Decode(member); err != nil {
-			log.Error("Error decoding player position for location update", zap.String("location", location.Name), zap.String("data", member), zap.Error(err))
-			continue
+	// players are moved before the location update is committed, so a failure
+	// to move them rolls the update back. If instead the commit fails after
+	// they were moved, they are moved back to the original name.
+	renamed := false
+	updated, err := GetStore().Update(id, &Location{
+		Name:    location.Name,
+		X:       location.X,
+		Y:       location.Y,
+		Width:   location.Width,
+		Height:  location.Height,
+		Blocked: location.Blocked.Encode(),
+		Version: location.Version,
+	}, func(updated *Location, pos positions.Store) error {
+		if err := pos.Rename(id, updated.Name); err != nil {
+			return err
 		}
 
-		p.Position.Location = location.Name
-
-		if _, err := rdb.SAdd(newKey, p.Encode()).Result(); err != nil {
-			log.Error("Error moving player to updated location", zap.String("location", location.Name), zap.String("playerData", member), zap.String("playerData", member), zap.Error(err))
-			return nil, errors.EDatabase.NewError(err)
+		renamed = true
+		return nil
+	})
+	if err != nil {
+		if renamed && id != location.Name {
+			if err := positions.GetStore().Rename(location.Name, id); err != nil {
+				log.Error("Failed to move players back after failed rename", zap.String("from", location.Name), zap.String("to", id), zap.Error(err))
+			}
 		}
 
-		key := fmt.Sprintf("%s:position", p.Username)
-		if _, err := rdb.Set(key, p.Position.Encode(), exp).Result(); err != nil {
-			log.Error("Error updating player into updated location", zap.String("location", location.Name), zap.String("username", p.Username), zap.Error(err))
-			continue
-		}
+		return nil, err
+	}
+
+	kind, previous := events.LocationUpdated, ""
+	if id != updated.Name {
+		kind, previous = events.LocationRenamed, id
 	}
 
-	if _, err := rdb.Del(key).Result(); err != nil {
-		log.Error("Error removing updated location", zap.String("location", id), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+	if err := events.Publish(kind, updated.Name, previous); err != nil {
+		log.Error("Failed to publish location event", zap.String("name", updated.Name), zap.Error(err))
 	}
 
 	return updated.ToLocation(), nil
@@ -180,97 +186,140 @@ func UpdateLocation(id string, location *data.Location) (*data.Location, error)
 
 // ListPlayers - list all player positions within a location
 func ListPlayers(location string) ([]*data.Player, error) {
-	pdb, err := connect.Postgres()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+	if _, err := GetStore().Get(location); err != nil {
+		log.Error("Cannot list users from unknown location", zap.String("location", location), zap.Error(err))
+		return nil, err
 	}
 
-	var l Location
-	if err := pdb.Where(&Location{Name: location}).First(&l).Error; err != nil {
-		if gorm.IsRecordNotFoundError(err) {
-			log.Error("Cannot list users from unknown location", zap.String("location", location), zap.Error(err))
-			return nil, errors.ENotFound.NewError(err)
-		}
+	return positions.GetStore().Members(location)
+}
 
-		log.Error("Error finding location", zap.String("location", location), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
-	}
+// GetLocations - fetch several locations by name, leaving out the ones which
+// don't exist
+func GetLocations(names []string) ([]*data.Location, error) {
+	result := make([]*data.Location, 0, len(names))
+	for _, name := range names {
+		location, err := GetStore().Get(name)
+		if errors.IsKind(err, errors.ENotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
 
-	rdb, err := connect.Redis()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+		result = append(result, location.ToLocation())
 	}
 
-	key := fmt.Sprintf("location:%s", location)
-	members, err := rdb.SMembers(key).Result()
-	if err != nil {
-		return nil, errors.EDatabase.NewError(err)
-	}
+	return result, nil
+}
 
-	log.Debug("Queried players from location", zap.String("key", key), zap.Int("players", len(members)))
+// ListOccupants - list the players in each of several locations, keyed by
+// location name. Unknown locations have no players.
+func ListOccupants(names []string) (map[string][]*data.Player, error) {
+	result := make(map[string][]*data.Player, len(names))
+	for _, name := range names {
+		if _, ok := result[name]; ok {
+			continue
+		}
 
-	results := make([]*data.Player, len(members))
-	for i, member := range members {
-		results[i] = &data.Player{}
-		if err := results[i].Decode(member); err != nil {
-			return nil, errors.EDatabase.NewErrorf("failed to decode position `%s`", member).Wrap(err)
+		members, err := positions.GetStore().Members(name)
+		if err != nil {
+			return nil, err
 		}
+
+		result[name] = members
 	}
 
-	return results, nil
+	return result, nil
 }
 
-// DeleteLocation - delete a location
-func DeleteLocation(name string) error {
-	pdb, err := connect.Postgres()
-	if err != nil {
-		return errors.EDatabaseConnection.NewError(err)
-	}
+// DeleteLocation - delete a location, moving any players in it to the
+// configured fallback location. If version is not 0, the location is only
+// deleted if it hasn't changed since that version.
+func DeleteLocation(name string, version uint) error {
+	// players in the location are moved to the fallback's entrance, the same
+	// tile they would arrive at by travelling there
+	var entrance *data.Position
+	fallback := configure.GetLocations().Fallback
+	if len(fallback) > 0 {
+		if name == fallback {
+			return errors.EForbidden.NewErrorf("cannot delete the fallback location `%s`", fallback)
+		}
 
-	q := pdb.Delete(&Location{Name: name})
-	if err := q.Error; err != nil {
-		log.Error("Error deleting location", zap.String("name", name), zap.Error(err))
-		return errors.EDatabase.NewError(err)
-	}
+		location, err := GetStore().Get(fallback)
+		if err != nil && !errors.IsKind(err, errors.ENotFound) {
+			return err
+		}
 
-	if q.RowsAffected == 0 {
-		log.Error("Location does not exist", zap.String("name", name))
-		return errors.EDatabase.NewError("player does not exist")
-	}
+		if location == nil {
+			log.Warn("Fallback location does not exist, players will be removed from the location", zap.String("fallback", fallback))
+		} else {
+			x, y, ok := location.ToLocation().Entrance()
+			if !ok {
+				return errors.EBlocked.NewErrorf("every tile of the fallback location `%s` is blocked", fallback).WithContext("location").WithDetail("location", fallback)
+			}
 
-	rdb, err := connect.Redis()
-	if err != nil {
-		return errors.EDatabaseConnection.NewError(err)
+			entrance = &data.Position{Location: fallback, X: x, Y: y}
+		}
 	}
 
-	key := fmt.Sprintf("location:%s", name)
-	members, err := rdb.SMembers(key).Result()
-	if err != nil {
-		log.Error("Error fetching members in deleted location", zap.String("name", name), zap.Error(err))
-		return errors.EDatabase.NewError(err)
-	}
+	// players are moved before the delete is committed, so a failure to move
+	// them rolls the delete back. If instead the commit fails after they were
+	// moved (positions kept outside the database aren't part of its
+	// transaction), they are put back where they were.
+	var occupants []*data.Player
+	err := GetStore().Delete(name, version, func(pos positions.Store) error {
+		members, err := pos.Members(name)
+		if err != nil {
+			return err
+		}
 
-	for _, member := range members {
-		p := &data.Player{}
-		if err := p.Decode(member); err != nil {
-			log.Error("Error decoding member of deleted location", zap.String("name", name), zap.String("data", member), zap.Error(err))
-			continue
+		occupants = members
+		usernames, err := pos.Clear(name)
+		if err != nil {
+			return err
 		}
 
-		if _, err := rdb.Del(fmt.Sprintf("%s:position", p.Username)).Result(); err != nil {
-			log.Error("Error deleting location record for user", zap.String("name", name), zap.String("username", p.Username), zap.Error(err))
-			continue
+		if entrance == nil {
+			return nil
+		}
+
+		for _, username := range usernames {
+			if err := pos.Set(username, &data.Position{Location: fallback, X: entrance.X, Y: entrance.Y}); err != nil {
+				return err
+			}
+		}
+
+		log.Info("Moved players to fallback location", zap.String("from", name), zap.String("to", fallback), zap.Int("players", len(usernames)))
+		return nil
+	})
+	if err != nil {
+		for _, occupant := range occupants {
+			if err := positions.GetStore().Set(occupant.Username, occupant.Position); err != nil {
+				log.Error("Failed to move player back after failed delete", zap.String("username", occupant.Username), zap.String("location", name), zap.Error(err))
+			}
 		}
+
+		return err
 	}
 
-	if _, err := rdb.Del(key).Result(); err != nil {
-		log.Error("Error deleting member list for location", zap.String("name", name), zap.Error(err))
-		return errors.EDatabase.NewError(err)
+	if err := events.Publish(events.LocationDeleted, name, ""); err != nil {
+		log.Error("Failed to publish location event", zap.String("name", name), zap.Error(err))
 	}
 
 	return nil
 }
 
-func init() {
-	models = append(models, &Location{})
+// RestoreLocation - bring back a deleted location which has not been purged.
+// Players moved out when it was deleted stay where they are.
+func RestoreLocation(name string) (*data.Location, error) {
+	restored, err := GetStore().Restore(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := events.Publish(events.LocationRestored, name, ""); err != nil {
+		log.Error("Failed to publish location event", zap.String("name", name), zap.Error(err))
+	}
+
+	return restored.ToLocation(), nil
 }
@@ -0,0 +1,196 @@
+package locations
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/positions"
+)
+
+type memoryStore struct {
+	mu        sync.RWMutex
+	locations map[string]Location
+	deleted   map[string]Location
+}
+
+// NewMemoryStore - create a location store held in process memory
+func NewMemoryStore() Store {
+	return &memoryStore{
+		locations: make(map[string]Location),
+		deleted:   make(map[string]Location),
+	}
+}
+
+func (s *memoryStore) Exists(name string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.taken(name), nil
+}
+
+func (s *memoryStore) taken(name string) bool {
+	_, ok := s.locations[name]
+	_, deleted := s.deleted[name]
+	return ok || deleted
+}
+
+func (s *memoryStore) Create(location *Location) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.taken(location.Name) {
+		return errors.EDuplicateLocation.NewError(location.Name).WithDetail("name", location.Name)
+	}
+
+	s.locations[location.Name] = *location
+	return nil
+}
+
+func (s *memoryStore) Get(name string) (*Location, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	location, ok := s.locations[name]
+	if !ok {
+		return nil, errors.ENotFound.NewError("record not found")
+	}
+
+	return &location, nil
+}
+
+func (s *memoryStore) List(query *ListQuery) ([]*Location, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	locations := make([]*Location, 0, len(s.locations))
+	for _, location := range s.locations {
+		if !strings.HasPrefix(location.Name, query.Prefix) {
+			continue
+		}
+
+		cursor := location.Cursor(query.Sort)
+		if !query.Includes(cursor.Value, cursor.Key) {
+			continue
+		}
+
+		l := location
+		locations = append(locations, &l)
+	}
+
+	sort.Slice(locations, func(i, j int) bool {
+		a, b := locations[i].Cursor(query.Sort), locations[j].Cursor(query.Sort)
+		if a.Value != b.Value {
+			return query.Less(a.Value, b.Value)
+		}
+
+		return query.Less(a.Key, b.Key)
+	})
+
+	if len(locations) > query.Limit {
+		locations = locations[:query.Limit]
+	}
+
+	return locations, nil
+}
+
+func (s *memoryStore) Update(name string, location *Location, hook UpdateHook) (*Location, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.locations[name]
+	if !ok {
+		return nil, errors.ENotFound.NewError("location not found")
+	}
+
+	if location.Version != 0 && location.Version != existing.Version {
+		return nil, versionMismatch(name, location.Version)
+	}
+
+	if location.Name != name && s.taken(location.Name) {
+		return nil, errors.EDuplicateLocation.NewError(location.Name).WithDetail("name", location.Name)
+	}
+
+	updated := existing
+	updated.Name = location.Name
+	updated.X = location.X
+	updated.Y = location.Y
+	updated.Width = location.Width
+	updated.Height = location.Height
+	updated.Blocked = location.Blocked
+	updated.Version = existing.Version + 1
+	updated.UpdatedAt = time.Now()
+
+	if hook != nil {
+		if err := hook(&updated, positions.GetStore()); err != nil {
+			return nil, err
+		}
+	}
+
+	delete(s.locations, name)
+	s.locations[updated.Name] = updated
+
+	return &updated, nil
+}
+
+func (s *memoryStore) Delete(name string, version uint, hook DeleteHook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.locations[name]
+	if !ok {
+		return errors.ENotFound.NewError("location does not exist")
+	}
+
+	if version != 0 && version != existing.Version {
+		return versionMismatch(name, version)
+	}
+
+	if hook != nil {
+		if err := hook(positions.GetStore()); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	existing.DeletedAt = &now
+
+	delete(s.locations, name)
+	s.deleted[name] = existing
+	return nil
+}
+
+func (s *memoryStore) Restore(name string) (*Location, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	location, ok := s.deleted[name]
+	if !ok {
+		return nil, errors.ENotFound.NewError("no deleted location to restore")
+	}
+
+	location.DeletedAt = nil
+	location.Version++
+	location.UpdatedAt = time.Now()
+
+	delete(s.deleted, name)
+	s.locations[name] = location
+	return &location, nil
+}
+
+func (s *memoryStore) Purge(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for name, location := range s.deleted {
+		if location.DeletedAt.Before(before) {
+			delete(s.deleted, name)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,111 @@
+package locations
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/migrate"
+	"github.com/jinzhu/gorm"
+)
+
+// migrations - schema history for the tables managed by this service. Each
+// migration declares its own copy of the models it touches, so that later
+// changes to the Location struct do not change what old migrations do.
+var migrations = []*migrate.Migration{
+	{
+		Version: 1,
+		Name:    "create_location",
+		Up: func(tx *gorm.DB) error {
+			type location struct {
+				Name      string `gorm:"primary_key"`
+				X         int
+				Y         int
+				CreatedAt time.Time `gorm:"type:timestamp"`
+				UpdatedAt time.Time `gorm:"type:timestamp"`
+			}
+
+			// tables created by AutoMigrate before versioned migrations existed
+			if tx.HasTable(&location{}) {
+				return nil
+			}
+
+			return tx.CreateTable(&location{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists("location").Error
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add_location_version",
+		Up: func(tx *gorm.DB) error {
+			type location struct {
+				Name    string `gorm:"primary_key"`
+				Version uint   `gorm:"not null;default:1"`
+			}
+
+			return tx.AutoMigrate(&location{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			type location struct {
+				Name      string `gorm:"primary_key"`
+				X         int
+				Y         int
+				CreatedAt time.Time `gorm:"type:timestamp"`
+				UpdatedAt time.Time `gorm:"type:timestamp"`
+			}
+
+			return migrate.DropColumn(tx, &location{}, "version")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add_location_deleted_at",
+		Up: func(tx *gorm.DB) error {
+			type location struct {
+				Name      string     `gorm:"primary_key"`
+				DeletedAt *time.Time `gorm:"type:timestamp;index"`
+			}
+
+			return tx.AutoMigrate(&location{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			type location struct {
+				Name      string `gorm:"primary_key"`
+				X         int
+				Y         int
+				Version   uint      `gorm:"not null;default:1"`
+				CreatedAt time.Time `gorm:"type:timestamp"`
+				UpdatedAt time.Time `gorm:"type:timestamp"`
+			}
+
+			return migrate.DropColumn(tx, &location{}, "deleted_at")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "add_location_layout",
+		Up: func(tx *gorm.DB) error {
+			type location struct {
+				Name    string `gorm:"primary_key"`
+				Width   int    `gorm:"not null;default:0"`
+				Height  int    `gorm:"not null;default:0"`
+				Blocked string `gorm:"type:text"`
+			}
+
+			return tx.AutoMigrate(&location{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			type location struct {
+				Name      string `gorm:"primary_key"`
+				X         int
+				Y         int
+				Version   uint       `gorm:"not null;default:1"`
+				CreatedAt time.Time  `gorm:"type:timestamp"`
+				UpdatedAt time.Time  `gorm:"type:timestamp"`
+				DeletedAt *time.Time `gorm:"type:timestamp;index"`
+			}
+
+			return migrate.DropColumn(tx, &location{}, "width", "height", "blocked")
+		},
+	},
+}
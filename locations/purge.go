@@ -0,0 +1,23 @@
+package locations
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/purge"
+)
+
+// PurgeDeleted - permanently remove locations deleted longer ago than the
+// configured retention period
+func PurgeDeleted() (int, error) {
+	return purge.Deleted("locations", purgeStore)
+}
+
+// StartPurge - purge deleted locations in the background at the configured
+// interval. Purging is disabled if the interval is not positive.
+func StartPurge() {
+	purge.Start("locations", purgeStore)
+}
+
+func purgeStore(before time.Time) (int, error) {
+	return GetStore().Purge(before)
+}
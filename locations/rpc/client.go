@@ -7,17 +7,22 @@ import (
 	"time"
 
 	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/errors/rpcstatus"
 	"github.com/carsonmyers/bublar-assignment/logger"
 	"github.com/carsonmyers/bublar-assignment/proto"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 var log = logger.GetLogger()
 
 // Client - RPC client for locations service
 type Client struct {
+	conn   *grpc.ClientConn
 	client proto.LocationsClient
+	// md - metadata sent with every request, such as who made it
+	md metadata.MD
 }
 
 // NewClient - create a new RPC client
@@ -25,6 +30,8 @@ func NewClient() (*Client, error) {
 	var opts = []grpc.DialOption{
 		grpc.WithInsecure(),
 		grpc.WithBlock(),
+		grpc.WithUnaryInterceptor(rpcstatus.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(rpcstatus.StreamClientInterceptor),
 	}
 
 	conf := configure.GetLocations()
@@ -34,10 +41,32 @@ func NewClient() (*Client, error) {
 	}
 
 	return &Client{
+		conn:   conn,
 		client: proto.NewLocationsClient(conn),
 	}, nil
 }
 
+// With - a client which sends the outgoing metadata of a context, such as
+// the caller attached by the API, with every request. Requests still get
+// their own timeout rather than the context's deadline.
+func (c *Client) With(ctx context.Context) *Client {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return c
+	}
+
+	return &Client{
+		conn:   c.conn,
+		client: c.client,
+		md:     md,
+	}
+}
+
+// Conn - the connection to the service, for invoking methods by name
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
 // Create - send a create location request
 func (c *Client) Create(location *proto.Location) (*proto.Location, error) {
 	ctx, cancel := c.ctx()
@@ -52,13 +81,14 @@ func (c *Client) Get(location *proto.Location) (*proto.Location, error) {
 	return c.client.Get(ctx, location)
 }
 
-// List - send a list locations request
-func (c *Client) List() ([]*proto.Location, error) {
+// List - send a list locations request, returning one page of locations and
+// the cursor for the next page, which is empty on the last page
+func (c *Client) List(req *proto.LocationListRequest) ([]*proto.Location, string, error) {
 	ctx, cancel := c.ctx()
 	defer cancel()
-	src, err := c.client.List(ctx, &proto.Empty{})
+	src, err := c.client.List(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	res := make([]*proto.Location, 0)
@@ -66,11 +96,11 @@ func (c *Client) List() ([]*proto.Location, error) {
 		var msg proto.Location
 		if err := src.RecvMsg(&msg); err != nil {
 			if err == io.EOF {
-				return res, nil
+				return res, nextCursor(src.Trailer()), nil
 			}
 
 			log.Error("Error receiving locations", zap.Error(err))
-			return nil, err
+			return nil, "", err
 		}
 
 		log.Debug("Receiving location", zap.String("name", msg.GetName()))
@@ -104,6 +134,58 @@ func (c *Client) ListPlayers(location *proto.Location) ([]*proto.Player, error)
 	}
 }
 
+// GetMany - request several locations at once. Locations which don't exist
+// are left out of the result.
+func (c *Client) GetMany(names []string) ([]*proto.Location, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	src, err := c.client.GetMany(ctx, &proto.Names{Names: names})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*proto.Location, 0, len(names))
+	for {
+		var msg proto.Location
+		if err := src.RecvMsg(&msg); err != nil {
+			if err == io.EOF {
+				return res, nil
+			}
+
+			log.Error("Error receiving locations", zap.Error(err))
+			return nil, err
+		}
+
+		res = append(res, &msg)
+	}
+}
+
+// Occupants - request the players in several locations at once. Each player's
+// location is the one of the requested locations they are in.
+func (c *Client) Occupants(names []string) ([]*proto.Player, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	src, err := c.client.Occupants(ctx, &proto.Names{Names: names})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*proto.Player, 0)
+	for {
+		var msg proto.Player
+		if err := src.RecvMsg(&msg); err != nil {
+			if err == io.EOF {
+				return res, nil
+			}
+
+			log.Error("Error receiving players", zap.Error(err))
+			return nil, err
+		}
+
+		res = append(res, &msg)
+	}
+}
+
 // Update - send an update location request
 func (c *Client) Update(location *proto.LocationUpdate) (*proto.Location, error) {
 	ctx, cancel := c.ctx()
@@ -111,17 +193,92 @@ func (c *Client) Update(location *proto.LocationUpdate) (*proto.Location, error)
 	return c.client.Update(ctx, location)
 }
 
-// Delete - send a delete location request
-func (c *Client) Delete(name string) error {
+// Delete - send a delete location request. If version is not 0, the location
+// is only deleted if it hasn't changed since that version.
+func (c *Client) Delete(name string, version uint64) error {
 	ctx, cancel := c.ctx()
 	defer cancel()
 	_, err := c.client.Delete(ctx, &proto.Location{
-		Name: name,
+		Name:    name,
+		Version: version,
 	})
 
 	return err
 }
 
+// Restore - send a restore location request
+func (c *Client) Restore(name string) (*proto.Location, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	return c.client.Restore(ctx, &proto.Location{
+		Name: name,
+	})
+}
+
+// Snapshot - request every location and the positions of the players in it
+func (c *Client) Snapshot() (*proto.LocationSnapshot, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	return c.client.Snapshot(ctx, &proto.Empty{})
+}
+
+// RestoreSnapshot - send a request to bring back the locations and positions
+// in a snapshot
+func (c *Client) RestoreSnapshot(snapshot *proto.LocationSnapshot, clear bool) (*proto.RestoreReport, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	return c.client.RestoreSnapshot(ctx, &proto.LocationSnapshotRestore{
+		Snapshot: snapshot,
+		Clear:    clear,
+	})
+}
+
+// Events - subscribe to changes to locations. Events are sent on the returned
+// channel until ctx is cancelled or the stream ends, then the channel is closed.
+func (c *Client) Events(ctx context.Context) (<-chan *proto.Event, error) {
+	src, err := c.client.Events(ctx, &proto.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(chan *proto.Event)
+	go func() {
+		defer close(res)
+
+		for {
+			msg, err := src.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					log.Error("Error receiving events", zap.Error(err))
+				}
+
+				return
+			}
+
+			select {
+			case res <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return res, nil
+}
+
 func (c *Client) ctx() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 10*time.Second)
+	ctx := context.Background()
+	if c.md != nil {
+		ctx = metadata.NewOutgoingContext(ctx, c.md)
+	}
+
+	return context.WithTimeout(ctx, 10*time.Second)
+}
+
+func nextCursor(trailer metadata.MD) string {
+	if next := trailer.Get("next"); len(next) > 0 {
+		return next[0]
+	}
+
+	return ""
 }
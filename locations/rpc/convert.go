@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/proto"
+)
+
+// LocationMessage - convert a location to its message
+func LocationMessage(loc *data.Location) *proto.Location {
+	blocked := make([]*proto.Tile, len(loc.Blocked))
+	for i, tile := range loc.Blocked {
+		blocked[i] = &proto.Tile{X: int32(tile.X), Y: int32(tile.Y)}
+	}
+
+	return &proto.Location{
+		Name:    loc.Name,
+		X:       int32(loc.X),
+		Y:       int32(loc.Y),
+		Version: uint64(loc.Version),
+		Width:   int32(loc.Width),
+		Height:  int32(loc.Height),
+		Blocked: blocked,
+	}
+}
+
+// LocationData - convert a location message to the universal data format
+func LocationData(msg *proto.Location) *data.Location {
+	var blocked data.Tiles
+	for _, tile := range msg.GetBlocked() {
+		blocked = append(blocked, data.Tile{X: int(tile.GetX()), Y: int(tile.GetY())})
+	}
+
+	return &data.Location{
+		Name:    msg.GetName(),
+		X:       int(msg.GetX()),
+		Y:       int(msg.GetY()),
+		Version: uint(msg.GetVersion()),
+		Width:   int(msg.GetWidth()),
+		Height:  int(msg.GetHeight()),
+		Blocked: blocked,
+	}
+}
@@ -0,0 +1,297 @@
+package server
+
+import (
+	"context"
+
+	"github.com/carsonmyers/bublar-assignment/audit"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/events"
+	"github.com/carsonmyers/bublar-assignment/locations"
+	"github.com/carsonmyers/bublar-assignment/locations/rpc"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+var log = logger.GetLogger()
+
+// Server - RPC server for the locations service
+type Server struct {
+	proto.UnimplementedLocationsServer
+}
+
+// Create - create a new location
+func (s *Server) Create(ctx context.Context, req *proto.Location) (*proto.Location, error) {
+	newLoc, err := locations.CreateLocation(rpc.LocationData(req))
+	if err != nil {
+		return nil, err
+	}
+
+	res := rpc.LocationMessage(newLoc)
+	audit.Record(ctx, "location.create", newLoc.Name, nil, res)
+	return res, nil
+}
+
+// Get - get a location by name
+func (s *Server) Get(ctx context.Context, req *proto.Location) (*proto.Location, error) {
+	loc, err := locations.GetLocation(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return rpc.LocationMessage(loc), nil
+}
+
+// List - list one page of locations, sending the cursor for the next page in
+// the `next` trailer
+func (s *Server) List(req *proto.LocationListRequest, srv proto.Locations_ListServer) error {
+	page, err := paging.NewQuery(int(req.GetLimit()), req.GetAfter(), req.GetSort(), locations.SortFields...)
+	if err != nil {
+		return err
+	}
+
+	res, next, err := locations.ListLocations(page, req.GetPrefix())
+	if err != nil {
+		return err
+	}
+
+	srv.SetTrailer(metadata.Pairs("next", next.Encode()))
+
+	log.Debug("Sending locations", zap.Int("locations", len(res)))
+	for _, loc := range res {
+		if err := srv.Send(rpc.LocationMessage(loc)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListPlayers - list all players in a location
+func (s *Server) ListPlayers(req *proto.Location, srv proto.Locations_ListPlayersServer) error {
+	res, err := locations.ListPlayers(req.GetName())
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Sending players from location", zap.String("location", req.GetName()), zap.Int("players", len(res)))
+	for _, p := range res {
+		player := &proto.Player{
+			Username: p.Username,
+		}
+
+		if p.Position != nil {
+			player.Location = p.Position.Location
+			player.X = int32(p.Position.X)
+			player.Y = int32(p.Position.Y)
+		}
+
+		if err := srv.Send(player); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetMany - fetch several locations at once, leaving out the ones which don't
+// exist
+func (s *Server) GetMany(req *proto.Names, srv proto.Locations_GetManyServer) error {
+	res, err := locations.GetLocations(req.GetNames())
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Sending locations", zap.Int("requested", len(req.GetNames())), zap.Int("locations", len(res)))
+	for _, loc := range res {
+		if err := srv.Send(rpc.LocationMessage(loc)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Occupants - list the players in several locations at once. Each player's
+// location says which of the locations they were found in.
+func (s *Server) Occupants(req *proto.Names, srv proto.Locations_OccupantsServer) error {
+	res, err := locations.ListOccupants(req.GetNames())
+	if err != nil {
+		return err
+	}
+
+	for name, members := range res {
+		log.Debug("Sending players from location", zap.String("location", name), zap.Int("players", len(members)))
+		for _, p := range members {
+			player := &proto.Player{
+				Username: p.Username,
+				Location: name,
+			}
+
+			if p.Position != nil {
+				player.X = int32(p.Position.X)
+				player.Y = int32(p.Position.Y)
+			}
+
+			if err := srv.Send(player); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Update - update a location's information
+func (s *Server) Update(ctx context.Context, req *proto.LocationUpdate) (*proto.Location, error) {
+	before, err := locations.GetLocation(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	newLoc, err := locations.UpdateLocation(req.GetId(), rpc.LocationData(req.GetLocation()))
+	if err != nil {
+		return nil, err
+	}
+
+	res := rpc.LocationMessage(newLoc)
+	audit.Record(ctx, "location.update", req.GetId(), rpc.LocationMessage(before), res)
+	return res, nil
+}
+
+// Delete - delete a location
+func (s *Server) Delete(ctx context.Context, req *proto.Location) (*proto.Location, error) {
+	before, err := locations.GetLocation(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := locations.DeleteLocation(req.GetName(), uint(req.GetVersion())); err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, "location.delete", req.GetName(), rpc.LocationMessage(before), nil)
+	return req, nil
+}
+
+// Restore - bring back a deleted location which has not been purged
+func (s *Server) Restore(ctx context.Context, req *proto.Location) (*proto.Location, error) {
+	loc, err := locations.RestoreLocation(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	res := rpc.LocationMessage(loc)
+	audit.Record(ctx, "location.restore", loc.Name, nil, res)
+	return res, nil
+}
+
+// Snapshot - capture every location and the positions of the players in it
+func (s *Server) Snapshot(ctx context.Context, req *proto.Empty) (*proto.LocationSnapshot, error) {
+	states, err := locations.TakeSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proto.LocationSnapshot{
+		Locations: make([]*proto.LocationState, len(states)),
+	}
+
+	for i, state := range states {
+		members := make([]*proto.Player, len(state.Members))
+		for j, member := range state.Members {
+			members[j] = &proto.Player{
+				Username: member.Username,
+				Location: member.Position.Location,
+				X:        int32(member.Position.X),
+				Y:        int32(member.Position.Y),
+			}
+		}
+
+		res.Locations[i] = &proto.LocationState{
+			Location: rpc.LocationMessage(state.Location),
+			Members:  members,
+		}
+	}
+
+	return res, nil
+}
+
+// RestoreSnapshot - bring back the locations and player positions in a snapshot
+func (s *Server) RestoreSnapshot(ctx context.Context, req *proto.LocationSnapshotRestore) (*proto.RestoreReport, error) {
+	snapshot := req.GetSnapshot().GetLocations()
+	states := make([]*locations.State, len(snapshot))
+	for i, state := range snapshot {
+		loc := state.GetLocation()
+		members := make([]*data.Player, len(state.GetMembers()))
+		for j, member := range state.GetMembers() {
+			members[j] = &data.Player{
+				Username: member.GetUsername(),
+				Position: &data.Position{
+					Location: loc.GetName(),
+					X:        int(member.GetX()),
+					Y:        int(member.GetY()),
+				},
+			}
+		}
+
+		states[i] = &locations.State{
+			Location: rpc.LocationData(loc),
+			Members:  members,
+		}
+	}
+
+	report, err := locations.RestoreSnapshot(states, req.GetClear())
+	if err != nil {
+		return nil, err
+	}
+
+	res := restoreReport(report)
+	audit.Record(ctx, "location.snapshot.restore", "", nil, res)
+	return res, nil
+}
+
+// Events - stream changes to locations until the client disconnects
+func (s *Server) Events(req *proto.Empty, srv proto.Locations_EventsServer) error {
+	sub, err := events.GetBus().Subscribe()
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	log.Debug("Client subscribed to events")
+	for {
+		select {
+		case <-srv.Context().Done():
+			log.Debug("Client unsubscribed from events")
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+
+			if err := srv.Send(&proto.Event{
+				Kind:     event.Kind,
+				Location: event.Location,
+				Previous: event.Previous,
+				Time:     event.Time.Unix(),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// restoreReport - convert a snapshot restore report to its message
+func restoreReport(report *data.RestoreReport) *proto.RestoreReport {
+	return &proto.RestoreReport{
+		Created:   int32(report.Created),
+		Updated:   int32(report.Updated),
+		Restored:  int32(report.Restored),
+		Deleted:   int32(report.Deleted),
+		Positions: int32(report.Positions),
+		Missing:   report.Missing,
+	}
+}
@@ -0,0 +1,187 @@
+package locations
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/positions"
+	"go.uber.org/zap"
+)
+
+// State - a location along with the players in it
+type State struct {
+	Location *data.Location
+	Members  []*data.Player
+}
+
+// TakeSnapshot - capture every location and the positions of the players in it
+func TakeSnapshot() ([]*State, error) {
+	all, err := allLocations()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*State, len(all))
+	for i, location := range all {
+		members, err := positions.GetStore().Members(location.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		states[i] = &State{
+			Location: location.ToLocation(),
+			Members:  members,
+		}
+	}
+
+	return states, nil
+}
+
+// RestoreSnapshot - bring the locations and player positions in a snapshot
+// back. Locations which were deleted since are restored. If clear is set,
+// locations which are not in the snapshot are deleted as DeleteLocation would
+// (except the fallback location, which can't be) and every player not in the
+// snapshot is removed from their location; otherwise they are left alone.
+func RestoreSnapshot(states []*State, clear bool) (*data.RestoreReport, error) {
+	store := GetStore()
+	posStore := positions.GetStore()
+	report := &data.RestoreReport{}
+
+	if clear {
+		keep := make(map[string]bool, len(states))
+		for _, state := range states {
+			keep[state.Location.Name] = true
+		}
+
+		current, err := allLocations()
+		if err != nil {
+			return nil, err
+		}
+
+		fallback := configure.GetLocations().Fallback
+		for _, location := range current {
+			if !keep[location.Name] && location.Name != fallback {
+				if err := DeleteLocation(location.Name, 0); err != nil {
+					return nil, err
+				}
+
+				report.Deleted++
+			}
+		}
+
+		// the players left in the remaining locations, including any moved to
+		// the fallback, are put back by the snapshot if they are in it
+		for _, location := range current {
+			if keep[location.Name] || location.Name == fallback {
+				if _, err := posStore.Clear(location.Name); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	for _, state := range states {
+		location := state.Location
+		existing, err := store.Get(location.Name)
+		if err != nil && !errors.IsKind(err, errors.ENotFound) {
+			return nil, err
+		}
+
+		if err != nil {
+			exists, err := store.Exists(location.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			if !exists {
+				if err := store.Create(&Location{
+					Name:      location.Name,
+					X:         location.X,
+					Y:         location.Y,
+					Width:     location.Width,
+					Height:    location.Height,
+					Blocked:   location.Blocked.Encode(),
+					Version:   1,
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				}); err != nil {
+					return nil, err
+				}
+
+				report.Created++
+			} else {
+				if existing, err = store.Restore(location.Name); err != nil {
+					return nil, err
+				}
+
+				report.Restored++
+			}
+		}
+
+		if existing != nil && (existing.X != location.X || existing.Y != location.Y || existing.Width != location.Width ||
+			existing.Height != location.Height || existing.Blocked != location.Blocked.Encode()) {
+			if _, err := store.Update(location.Name, &Location{
+				Name:    location.Name,
+				X:       location.X,
+				Y:       location.Y,
+				Width:   location.Width,
+				Height:  location.Height,
+				Blocked: location.Blocked.Encode(),
+			}, nil); err != nil {
+				return nil, err
+			}
+
+			report.Updated++
+		}
+
+		for _, member := range state.Members {
+			if err := posStore.Set(member.Username, &data.Position{
+				Location: location.Name,
+				X:        member.Position.X,
+				Y:        member.Position.Y,
+			}); err != nil {
+				return nil, err
+			}
+
+			report.Positions++
+		}
+	}
+
+	log.Info("Restored locations snapshot",
+		zap.Bool("clear", clear),
+		zap.Int("created", report.Created),
+		zap.Int("updated", report.Updated),
+		zap.Int("restored", report.Restored),
+		zap.Int("deleted", report.Deleted),
+		zap.Int("positions", report.Positions))
+
+	return report, nil
+}
+
+// allLocations - fetch every location, a page at a time
+func allLocations() ([]*Location, error) {
+	query := &ListQuery{
+		Query: paging.Query{
+			Limit: paging.MaxLimit,
+			Sort:  SortName,
+		},
+	}
+
+	all := make([]*Location, 0)
+	for {
+		page, err := GetStore().List(query)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, page...)
+		if len(page) < query.Limit {
+			return all, nil
+		}
+
+		query.After = page[len(page)-1].Cursor(SortName)
+	}
+}
@@ -0,0 +1,108 @@
+package locations
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/positions"
+)
+
+const (
+	// SortName - sort locations by name
+	SortName = "name"
+
+	// SortCreated - sort locations by when they were created
+	SortCreated = "createdAt"
+)
+
+// SortFields - fields locations can be sorted by, the first being the default
+var SortFields = []string{SortName, SortCreated}
+
+// ListQuery - which locations to list, and in what order
+type ListQuery struct {
+	paging.Query
+
+	// Prefix - only list locations whose name starts with this
+	Prefix string
+}
+
+// Cursor - get the position of a location in a list sorted by a field
+func (l *Location) Cursor(sort string) *paging.Cursor {
+	switch sort {
+	case SortCreated:
+		return &paging.Cursor{Value: paging.TimeValue(l.CreatedAt), Key: l.Name}
+	default:
+		return &paging.Cursor{Value: l.Name, Key: l.Name}
+	}
+}
+
+// UpdateHook - called with the updated location before an update is committed,
+// along with a position store which takes part in the same transaction. If the
+// hook fails, the update is rolled back.
+type UpdateHook func(updated *Location, positions positions.Store) error
+
+// DeleteHook - called before a deletion is committed, with a position store
+// which takes part in the same transaction. If the hook fails, the deletion is
+// rolled back.
+type DeleteHook func(positions positions.Store) error
+
+// Store - storage for locations in the game world
+type Store interface {
+	// Exists - check whether a location name is taken, including by a deleted
+	// location which has not been purged yet
+	Exists(name string) (bool, error)
+
+	// Create - store a new location
+	Create(location *Location) error
+
+	// Get - get a location by name
+	Get(name string) (*Location, error)
+
+	// List - list one page of locations
+	List(query *ListQuery) ([]*Location, error)
+
+	// Update - change a location's name and coordinates. If the location's
+	// version is set, the update fails unless it matches the stored version.
+	Update(name string, location *Location, hook UpdateHook) (*Location, error)
+
+	// Delete - delete a location, keeping it around to be restored until it is
+	// purged. If version is not 0, the deletion fails unless it matches the
+	// stored version.
+	Delete(name string, version uint, hook DeleteHook) error
+
+	// Restore - bring back a deleted location which has not been purged
+	Restore(name string) (*Location, error)
+
+	// Purge - permanently remove locations deleted before a time, returning
+	// how many were removed
+	Purge(before time.Time) (int, error)
+}
+
+var store Store
+
+// SetStore - override the configured storage backend
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore - get the configured storage backend
+func GetStore() Store {
+	if store != nil {
+		return store
+	}
+
+	switch configure.GetStorage().Backend {
+	case configure.BackendMemory:
+		store = NewMemoryStore()
+	default:
+		store = NewGormStore()
+	}
+
+	return store
+}
+
+func versionMismatch(name string, expected uint) error {
+	return errors.EVersionMismatch.NewErrorf("location `%s` has changed since version %d", name, expected).WithDetail("expected", expected)
+}
@@ -0,0 +1,387 @@
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/jinzhu/gorm"
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+// Migration - a single versioned change to the schema
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration - record of a migration which has been applied
+type SchemaMigration struct {
+	Service   string    `gorm:"primary_key"`
+	Version   uint      `gorm:"primary_key;auto_increment:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"type:timestamp"`
+}
+
+// Status - state of a single migration
+type Status struct {
+	Service   string
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator - applies the migrations for one service to a database
+type Migrator struct {
+	db         *gorm.DB
+	service    string
+	migrations []*Migration
+}
+
+// New - create a migrator for a service's migrations
+func New(db *gorm.DB, service string, migrations []*Migration) *Migrator {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	return &Migrator{
+		db:         db,
+		service:    service,
+		migrations: sorted,
+	}
+}
+
+// Service - get the name of the service the migrations belong to
+func (m *Migrator) Service() string {
+	return m.service
+}
+
+// Up - apply every pending migration in order
+func (m *Migrator) Up() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		log.Info("Applying migration", zap.String("service", m.service), zap.Uint("version", migration.Version), zap.String("name", migration.Name))
+		err := m.transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&SchemaMigration{
+				Service:   m.service,
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			log.Error("Migration failed", zap.String("service", m.service), zap.Uint("version", migration.Version), zap.Error(err))
+			return errors.EDatabase.NewErrorf("migration %d (%s) failed", migration.Version, migration.Name).Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// Down - roll back the given number of most recently applied migrations
+func (m *Migrator) Down(steps int) error {
+	for ; steps > 0; steps-- {
+		migration, _, err := m.latest()
+		if err != nil {
+			return err
+		}
+
+		if migration == nil {
+			return nil
+		}
+
+		if err := m.rollback(migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// latest - the most recently applied migration and its record, or nil if
+// none have been applied
+func (m *Migrator) latest() (*Migration, *SchemaMigration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if record, ok := applied[m.migrations[i].Version]; ok {
+			return m.migrations[i], record, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// rollback - undo a single applied migration
+func (m *Migrator) rollback(migration *Migration) error {
+	log.Info("Rolling back migration", zap.String("service", m.service), zap.Uint("version", migration.Version), zap.String("name", migration.Name))
+	err := m.transaction(func(tx *gorm.DB) error {
+		if migration.Down == nil {
+			return errors.ENotImplemented.NewErrorf("migration %d cannot be rolled back", migration.Version)
+		}
+
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+
+		return tx.Delete(&SchemaMigration{
+			Service: m.service,
+			Version: migration.Version,
+		}).Error
+	})
+	if err != nil {
+		log.Error("Rollback failed", zap.String("service", m.service), zap.Uint("version", migration.Version), zap.Error(err))
+		return errors.EDatabase.NewErrorf("rollback of migration %d (%s) failed", migration.Version, migration.Name).Wrap(err)
+	}
+
+	return nil
+}
+
+// downAll - roll back the given number of most recently applied migrations
+// across every migrator, in total rather than per migrator. Migrations
+// applied at the same time are rolled back in the reverse of the order the
+// migrators are applied.
+func downAll(steps int, migrators []*Migrator) error {
+	for ; steps > 0; steps-- {
+		var (
+			newest    *Migrator
+			migration *Migration
+			appliedAt time.Time
+		)
+
+		for _, m := range migrators {
+			latest, record, err := m.latest()
+			if err != nil {
+				return err
+			}
+
+			if latest != nil && (newest == nil || !record.AppliedAt.Before(appliedAt)) {
+				newest, migration, appliedAt = m, latest, record.AppliedAt
+			}
+		}
+
+		if newest == nil {
+			return nil
+		}
+
+		if err := newest.rollback(migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Status - list every migration along with when it was applied
+func (m *Migrator) Status() ([]*Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		results[i] = &Status{
+			Service: m.service,
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			results[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return results, nil
+}
+
+func (m *Migrator) applied() (map[uint]*SchemaMigration, error) {
+	if !m.db.HasTable(&SchemaMigration{}) {
+		if err := m.db.CreateTable(&SchemaMigration{}).Error; err != nil {
+			// another service sharing the database may have won the race
+			if !m.db.HasTable(&SchemaMigration{}) {
+				log.Error("Failed to create schema table", zap.Error(err))
+				return nil, errors.EDatabase.NewError(err)
+			}
+		}
+	}
+
+	var records []*SchemaMigration
+	if err := m.db.Where(&SchemaMigration{Service: m.service}).Find(&records).Error; err != nil {
+		log.Error("Failed to read applied migrations", zap.String("service", m.service), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	applied := make(map[uint]*SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) transaction(fn func(tx *gorm.DB) error) error {
+	tx := m.db.Begin()
+	if err := tx.Error; err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// DropColumn - drop columns from the table of model, which should describe
+// the table as it is without them. SQLite cannot drop columns, so there the
+// table is rebuilt from model and the remaining columns are copied over.
+func DropColumn(tx *gorm.DB, model interface{}, columns ...string) error {
+	if tx.Dialect().GetName() != "sqlite3" {
+		for _, column := range columns {
+			if err := tx.Model(model).DropColumn(column).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	scope := tx.NewScope(model)
+	table := scope.TableName()
+	old := table + "_old"
+
+	kept := make([]string, 0)
+	for _, field := range scope.GetModelStruct().StructFields {
+		if field.IsNormal && !field.IsIgnored {
+			kept = append(kept, scope.Quote(field.DBName))
+		}
+	}
+
+	list := strings.Join(kept, ", ")
+	steps := []func() error{
+		func() error {
+			return tx.Exec(fmt.Sprintf("ALTER TABLE %s RENAME TO %s", scope.Quote(table), scope.Quote(old))).Error
+		},
+		// indexes keep their names when their table is renamed, and the new
+		// table's indexes are created with the same names
+		func() error {
+			var indexes []string
+			if err := tx.Table("sqlite_master").Where("type = 'index' AND tbl_name = ? AND sql IS NOT NULL", old).Pluck("name", &indexes).Error; err != nil {
+				return err
+			}
+
+			for _, index := range indexes {
+				if err := tx.Exec(fmt.Sprintf("DROP INDEX %s", scope.Quote(index))).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		func() error { return tx.CreateTable(model).Error },
+		func() error {
+			return tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", scope.Quote(table), list, list, scope.Quote(old))).Error
+		},
+		func() error { return tx.DropTable(old).Error },
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run - execute a migrate subcommand (up, down [steps], or status) against a
+// set of migrators, writing any output to w. `down` rolls back the given
+// number of migrations in total, newest first, whichever migrators they
+// belong to.
+func Run(w io.Writer, args []string, migrators ...*Migrator) error {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		for _, m := range migrators {
+			if err := m.Up(); err != nil {
+				return err
+			}
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return errors.EInvalidRequest.NewErrorf("invalid number of steps \"%s\"", args[1])
+			}
+
+			steps = n
+		}
+
+		if err := downAll(steps, migrators); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return errors.EInvalidRequest.NewErrorf("unknown migrate action \"%s\" (expected up, down or status)", action)
+	}
+
+	return printStatus(w, migrators)
+}
+
+func printStatus(w io.Writer, migrators []*Migrator) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVICE\tVERSION\tNAME\tAPPLIED")
+
+	for _, m := range migrators {
+		statuses, err := m.Status()
+		if err != nil {
+			return err
+		}
+
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", s.Service, s.Version, s.Name, applied)
+		}
+	}
+
+	return tw.Flush()
+}
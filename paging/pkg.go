@@ -0,0 +1,173 @@
+package paging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+)
+
+const (
+	// DefaultLimit - page size used when none is requested
+	DefaultLimit = 50
+
+	// MaxLimit - largest page size that may be requested
+	MaxLimit = 1000
+)
+
+// Cursor - position in a sorted list after which the next page starts
+type Cursor struct {
+	// Value - sort value of the last item on the previous page
+	Value string `json:"v"`
+
+	// Key - unique key of the last item on the previous page, which breaks
+	// ties between items with the same sort value
+	Key string `json:"k"`
+}
+
+// Encode - encode a cursor as an opaque string for clients to pass back
+func (c *Cursor) Encode() string {
+	if c == nil {
+		return ""
+	}
+
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor - decode a cursor created with Encode. An empty string decodes
+// to a nil cursor, which starts from the beginning of the list.
+func DecodeCursor(encoded string) (*Cursor, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.EInvalidRequest.NewError("invalid cursor").WithContext("after")
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, errors.EInvalidRequest.NewError("invalid cursor").WithContext("after")
+	}
+
+	return &c, nil
+}
+
+// Query - parameters for fetching one page of a sorted list
+type Query struct {
+	Limit int
+	After *Cursor
+	Sort  string
+	Desc  bool
+}
+
+// NewQuery - build a query from request parameters. The sort field may be
+// prefixed with `-` for descending order, and must be one of fields; the first
+// of which is the default.
+func NewQuery(limit int, after, sort string, fields ...string) (*Query, error) {
+	if limit < 0 {
+		return nil, errors.EInvalidRequest.NewErrorf("invalid limit %d", limit).WithContext("limit")
+	}
+
+	if limit == 0 {
+		limit = DefaultLimit
+	} else if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	cursor, err := DecodeCursor(after)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{
+		Limit: limit,
+		After: cursor,
+		Sort:  fields[0],
+	}
+
+	if strings.HasPrefix(sort, "-") {
+		q.Desc = true
+		sort = sort[1:]
+	}
+
+	if len(sort) == 0 {
+		return q, nil
+	}
+
+	for _, field := range fields {
+		if sort == field {
+			q.Sort = field
+			return q, nil
+		}
+	}
+
+	return nil, errors.EInvalidRequest.NewErrorf("cannot sort by `%s` (expected one of %s)", sort, strings.Join(fields, ", ")).WithContext("sort").WithDetail("allowed", fields)
+}
+
+// Less - compare two sort values in the query's direction
+func (q *Query) Less(a, b string) bool {
+	if q.Desc {
+		return a > b
+	}
+
+	return a < b
+}
+
+// Includes - check whether an item comes after the query's cursor
+func (q *Query) Includes(value, key string) bool {
+	if q.After == nil {
+		return true
+	}
+
+	if value != q.After.Value {
+		return q.Less(q.After.Value, value)
+	}
+
+	return q.Less(q.After.Key, key)
+}
+
+// Operator - SQL comparison operator selecting items after the cursor
+func (q *Query) Operator() string {
+	if q.Desc {
+		return "<"
+	}
+
+	return ">"
+}
+
+// Direction - SQL ordering direction of the query
+func (q *Query) Direction() string {
+	if q.Desc {
+		return "desc"
+	}
+
+	return "asc"
+}
+
+// EscapeLike - escape the wildcards in a string for use in a LIKE pattern
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// timeFormat - fixed-width so that encoded times sort in chronological order
+const timeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// TimeValue - encode a time as a cursor value
+func TimeValue(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// ParseTime - decode a cursor value created with TimeValue
+func ParseTime(value string) (time.Time, error) {
+	t, err := time.Parse(timeFormat, value)
+	if err != nil {
+		return t, errors.EInvalidRequest.NewError("invalid cursor").WithContext("after")
+	}
+
+	return t, nil
+}
@@ -0,0 +1,169 @@
+package paging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/errors"
+)
+
+func TestCursor(t *testing.T) {
+	cases := []struct {
+		name   string
+		cursor *Cursor
+	}{
+		{"plain", &Cursor{Value: "alice", Key: "alice"}},
+		{"empty value", &Cursor{Value: "", Key: "bob"}},
+		{"time", &Cursor{Value: TimeValue(time.Date(2020, 5, 1, 12, 0, 0, 5, time.UTC)), Key: "carol"}},
+		{"special characters", &Cursor{Value: "a/b+c=d\"e", Key: "ünïcode"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			decoded, err := DecodeCursor(c.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+
+			if decoded == nil || *decoded != *c.cursor {
+				t.Errorf("got %v back, want %v", decoded, c.cursor)
+			}
+		})
+	}
+
+	var none *Cursor
+	if encoded := none.Encode(); encoded != "" {
+		t.Errorf("nil cursor encoded as %q, want empty", encoded)
+	}
+
+	if decoded, err := DecodeCursor(""); err != nil || decoded != nil {
+		t.Errorf("empty cursor decoded as %v (%v), want nil", decoded, err)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	cases := []struct {
+		name    string
+		encoded string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", "eyJ2IjoiYSJ9=="},
+		{"not JSON", "bm90IGpzb24"},
+		{"wrong shape", "WzEsMl0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := DecodeCursor(c.encoded)
+			if !errors.IsKind(err, errors.EInvalidRequest) {
+				t.Errorf("got %v, want an invalid request", err)
+			}
+		})
+	}
+}
+
+func TestNewQuery(t *testing.T) {
+	fields := []string{"username", "createdAt"}
+
+	cases := []struct {
+		name  string
+		limit int
+		sort  string
+		want  *Query
+	}{
+		{"defaults", 0, "", &Query{Limit: DefaultLimit, Sort: "username"}},
+		{"limit", 10, "", &Query{Limit: 10, Sort: "username"}},
+		{"limit too high", MaxLimit + 1, "", &Query{Limit: MaxLimit, Sort: "username"}},
+		{"sort", 0, "createdAt", &Query{Limit: DefaultLimit, Sort: "createdAt"}},
+		{"descending", 0, "-createdAt", &Query{Limit: DefaultLimit, Sort: "createdAt", Desc: true}},
+		{"descending default", 0, "-", &Query{Limit: DefaultLimit, Sort: "username", Desc: true}},
+		{"negative limit", -1, "", nil},
+		{"unknown sort", 0, "password", nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			q, err := NewQuery(c.limit, "", c.sort, fields...)
+			if c.want == nil {
+				if !errors.IsKind(err, errors.EInvalidRequest) {
+					t.Errorf("got %v (%v), want an invalid request", q, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewQuery: %v", err)
+			}
+
+			if *q != *c.want {
+				t.Errorf("got %+v, want %+v", q, c.want)
+			}
+		})
+	}
+}
+
+// TestIncludes - items come after the cursor by their sort value, and by key
+// between items with the same value
+func TestIncludes(t *testing.T) {
+	after := &Cursor{Value: "2020", Key: "bob"}
+
+	cases := []struct {
+		name  string
+		desc  bool
+		value string
+		key   string
+		want  bool
+	}{
+		{"later value", false, "2021", "alice", true},
+		{"earlier value", false, "2019", "carol", false},
+		{"same value, later key", false, "2020", "carol", true},
+		{"same value, earlier key", false, "2020", "alice", false},
+		{"the cursor itself", false, "2020", "bob", false},
+		{"descending, earlier value", true, "2019", "carol", true},
+		{"descending, later value", true, "2021", "alice", false},
+		{"descending, same value, earlier key", true, "2020", "alice", true},
+		{"descending, same value, later key", true, "2020", "carol", false},
+		{"descending, the cursor itself", true, "2020", "bob", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			q := &Query{After: after, Desc: c.desc}
+			if got := q.Includes(c.value, c.key); got != c.want {
+				t.Errorf("Includes(%q, %q) = %v, want %v", c.value, c.key, got, c.want)
+			}
+		})
+	}
+
+	if !(&Query{}).Includes("anything", "at all") {
+		t.Errorf("a query without a cursor excludes items")
+	}
+}
+
+// TestTimeValue - encoded times sort in chronological order, and parse back
+func TestTimeValue(t *testing.T) {
+	base := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	times := []time.Time{
+		base,
+		base.Add(time.Nanosecond),
+		base.Add(time.Second),
+		base.Add(10 * time.Hour).In(time.FixedZone("east", 3*60*60)),
+		base.AddDate(1, 0, 0),
+	}
+
+	for i, tm := range times {
+		value := TimeValue(tm)
+		parsed, err := ParseTime(value)
+		if err != nil || !parsed.Equal(tm) {
+			t.Errorf("%s parsed as %v (%v), want %v", value, parsed, err, tm)
+		}
+
+		if i > 0 && TimeValue(times[i-1]) >= value {
+			t.Errorf("%s sorts before %s", value, TimeValue(times[i-1]))
+		}
+	}
+
+	if _, err := ParseTime("yesterday"); !errors.IsKind(err, errors.EInvalidRequest) {
+		t.Errorf("got %v for an invalid time, want an invalid request", err)
+	}
+}
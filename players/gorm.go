@@ -0,0 +1,322 @@
+package players
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/positions"
+	"github.com/jinzhu/gorm"
+	"go.uber.org/zap"
+)
+
+type gormStore struct{}
+
+// NewGormStore - create a player store backed by an SQL database
+func NewGormStore() Store {
+	return &gormStore{}
+}
+
+func (s *gormStore) Exists(username string) (bool, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return false, errors.EDatabaseConnection.NewError(err)
+	}
+
+	// deleted players keep their username reserved until they are purged
+	var count uint64
+	q := db.Unscoped().Model(&Player{}).Where(&Player{Username: username}).Count(&count)
+	if err := q.Error; err != nil {
+		log.Error("Error counting existing users", zap.Error(err))
+		return false, errors.EDatabase.NewError(err)
+	}
+
+	return count != 0, nil
+}
+
+func (s *gormStore) Create(player *Player) error {
+	db, err := connect.Database()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	if err := db.Create(player).Error; err != nil {
+		log.Error("Failed to store new user", zap.String("username", player.Username), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) Get(username string) (*Player, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var player Player
+	if err := db.Where(&Player{Username: username}).First(&player).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.ENotFound.NewError(err)
+		}
+
+		log.Error("Error fetching user data", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return &player, nil
+}
+
+// sortColumns - columns corresponding to each sort field
+var sortColumns = map[string]string{
+	SortUsername: "username",
+	SortCreated:  "created_at",
+}
+
+func (s *gormStore) List(query *ListQuery) ([]*Player, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	if query.Usernames != nil && len(query.Usernames) == 0 {
+		return []*Player{}, nil
+	}
+
+	q := db.Model(&Player{})
+	if len(query.Prefix) > 0 {
+		q = q.Where(`username LIKE ? ESCAPE '\'`, paging.EscapeLike(query.Prefix)+"%")
+	}
+
+	if query.Usernames != nil {
+		q = q.Where("username IN (?)", query.Usernames)
+	}
+
+	column := sortColumns[query.Sort]
+	op := query.Operator()
+	if after := query.After; after != nil {
+		if column == "username" {
+			q = q.Where("username "+op+" ?", after.Key)
+		} else {
+			value, err := paging.ParseTime(after.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			q = q.Where(column+" "+op+" ? OR ("+column+" = ? AND username "+op+" ?)", value, value, after.Key)
+		}
+	}
+
+	if column != "username" {
+		q = q.Order(column + " " + query.Direction())
+	}
+
+	var players []*Player
+	q = q.Order("username " + query.Direction()).Limit(query.Limit)
+	if err := q.Find(&players).Error; err != nil {
+		log.Error("Error fetching all users", zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return players, nil
+}
+
+func (s *gormStore) Update(username string, player *Player, hook UpdateHook) (*Player, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	updates := map[string]interface{}{
+		"username":   player.Username,
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}
+
+	if len(player.Password) != 0 {
+		updates["password"] = player.Password
+	}
+
+	tx := db.Begin()
+	if err := tx.Error; err != nil {
+		log.Error("Failed to begin transaction", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	// update through the table rather than the model, otherwise gorm puts the
+	// new primary key into the WHERE clause and nothing matches
+	q := tx.Table(tx.NewScope(&Player{}).TableName()).Where(&Player{Username: username, Version: player.Version}).Where("deleted_at IS NULL").Updates(updates)
+	if err := q.Error; err != nil {
+		tx.Rollback()
+		log.Error("Failed to patch player", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	if q.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, s.missing(username, player.Version)
+	}
+
+	if player.Username != username {
+		if err := tx.Model(&Violation{}).Where(&Violation{Username: username}).UpdateColumn("username", player.Username).Error; err != nil {
+			tx.Rollback()
+			log.Error("Failed to move violations to renamed player", zap.String("username", username), zap.Error(err))
+			return nil, errors.EDatabase.NewError(err)
+		}
+	}
+
+	var updated Player
+	if err := tx.Where(&Player{Username: player.Username}).First(&updated).Error; err != nil {
+		tx.Rollback()
+		log.Error("Failed to fetch patched player", zap.String("username", player.Username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	if hook != nil {
+		if err := hook(positions.Within(tx)); err != nil {
+			tx.Rollback()
+			log.Error("Rolled back player update", zap.String("username", username), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("Failed to commit player update", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return &updated, nil
+}
+
+func (s *gormStore) Delete(username string, version uint, position *data.Position) error {
+	db, err := connect.Database()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	updates := map[string]interface{}{
+		"deleted_at":       time.Now(),
+		"deleted_position": "",
+	}
+
+	if position != nil {
+		updates["deleted_position"] = position.Encode()
+	}
+
+	q := db.Table(db.NewScope(&Player{}).TableName()).Where("username = ? AND deleted_at IS NULL", username)
+	if version != 0 {
+		q = q.Where("version = ?", version)
+	}
+
+	q = q.Updates(updates)
+	if err := q.Error; err != nil {
+		log.Error("Error deleting player", zap.String("username", username), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	if q.RowsAffected == 0 {
+		log.Error("Cannot delete nonexistent player", zap.String("username", username), zap.Uint("version", version))
+		return s.missing(username, version)
+	}
+
+	return nil
+}
+
+func (s *gormStore) Restore(username string) (*Player, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var deleted Player
+	if err := db.Unscoped().Where("username = ? AND deleted_at IS NOT NULL", username).First(&deleted).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errors.ENotFound.NewError("no deleted player to restore")
+		}
+
+		log.Error("Failed to find deleted player", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	q := db.Unscoped().Table(db.NewScope(&Player{}).TableName()).Where("username = ? AND deleted_at IS NOT NULL", username).Updates(map[string]interface{}{
+		"deleted_at":       nil,
+		"deleted_position": "",
+		"version":          gorm.Expr("version + 1"),
+		"updated_at":       time.Now(),
+	})
+	if err := q.Error; err != nil {
+		log.Error("Failed to restore player", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	if q.RowsAffected == 0 {
+		return nil, errors.ENotFound.NewError("no deleted player to restore")
+	}
+
+	restored, err := s.Get(username)
+	if err != nil {
+		return nil, err
+	}
+
+	restored.DeletedPosition = deleted.DeletedPosition
+	return restored, nil
+}
+
+func (s *gormStore) Purge(before time.Time) (int, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return 0, errors.EDatabaseConnection.NewError(err)
+	}
+
+	q := db.Unscoped().Where("deleted_at < ?", before).Delete(&Player{})
+	if err := q.Error; err != nil {
+		log.Error("Failed to purge deleted players", zap.Time("before", before), zap.Error(err))
+		return 0, errors.EDatabase.NewError(err)
+	}
+
+	return int(q.RowsAffected), nil
+}
+
+func (s *gormStore) AddViolation(violation *Violation) error {
+	db, err := connect.Database()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	if err := db.Create(violation).Error; err != nil {
+		log.Error("Failed to record violation", zap.String("username", violation.Username), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) Violations(username string) ([]*Violation, error) {
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var violations []*Violation
+	if err := db.Where(&Violation{Username: username}).Order("id DESC").Find(&violations).Error; err != nil {
+		log.Error("Error fetching violations", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return violations, nil
+}
+
+// missing - explain why a conditional write to a player matched nothing
+func (s *gormStore) missing(username string, version uint) error {
+	if version == 0 {
+		return errors.ENotFound.NewError("player does not exist")
+	}
+
+	if _, err := s.Get(username); err != nil {
+		return err
+	}
+
+	return versionMismatch(username, version)
+}
@@ -0,0 +1,240 @@
+package players
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/positions"
+)
+
+type memoryStore struct {
+	mu         sync.RWMutex
+	players    map[string]Player
+	deleted    map[string]Player
+	violations []Violation
+}
+
+// NewMemoryStore - create a player store held in process memory
+func NewMemoryStore() Store {
+	return &memoryStore{
+		players: make(map[string]Player),
+		deleted: make(map[string]Player),
+	}
+}
+
+func (s *memoryStore) Exists(username string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.taken(username), nil
+}
+
+func (s *memoryStore) taken(username string) bool {
+	_, ok := s.players[username]
+	_, deleted := s.deleted[username]
+	return ok || deleted
+}
+
+func (s *memoryStore) Create(player *Player) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.taken(player.Username) {
+		return errors.EDuplicateUser.NewError(player.Username).WithDetail("username", player.Username)
+	}
+
+	s.players[player.Username] = *player
+	return nil
+}
+
+func (s *memoryStore) Get(username string) (*Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	player, ok := s.players[username]
+	if !ok {
+		return nil, errors.ENotFound.NewError("record not found")
+	}
+
+	return &player, nil
+}
+
+func (s *memoryStore) List(query *ListQuery) ([]*Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var only map[string]bool
+	if query.Usernames != nil {
+		only = make(map[string]bool, len(query.Usernames))
+		for _, username := range query.Usernames {
+			only[username] = true
+		}
+	}
+
+	players := make([]*Player, 0, len(s.players))
+	for _, player := range s.players {
+		if !strings.HasPrefix(player.Username, query.Prefix) {
+			continue
+		}
+
+		if only != nil && !only[player.Username] {
+			continue
+		}
+
+		cursor := player.Cursor(query.Sort)
+		if !query.Includes(cursor.Value, cursor.Key) {
+			continue
+		}
+
+		p := player
+		players = append(players, &p)
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		a, b := players[i].Cursor(query.Sort), players[j].Cursor(query.Sort)
+		if a.Value != b.Value {
+			return query.Less(a.Value, b.Value)
+		}
+
+		return query.Less(a.Key, b.Key)
+	})
+
+	if len(players) > query.Limit {
+		players = players[:query.Limit]
+	}
+
+	return players, nil
+}
+
+func (s *memoryStore) Update(username string, player *Player, hook UpdateHook) (*Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.players[username]
+	if !ok {
+		return nil, errors.ENotFound.NewError("player does not exist")
+	}
+
+	if player.Version != 0 && player.Version != existing.Version {
+		return nil, versionMismatch(username, player.Version)
+	}
+
+	if player.Username != username && s.taken(player.Username) {
+		return nil, errors.EDuplicateUser.NewError(player.Username).WithDetail("username", player.Username)
+	}
+
+	if hook != nil {
+		if err := hook(positions.GetStore()); err != nil {
+			return nil, err
+		}
+	}
+
+	existing.Username = player.Username
+	existing.Version++
+	existing.UpdatedAt = time.Now()
+	if len(player.Password) != 0 {
+		existing.Password = player.Password
+	}
+
+	delete(s.players, username)
+	s.players[existing.Username] = existing
+
+	for i := range s.violations {
+		if s.violations[i].Username == username {
+			s.violations[i].Username = existing.Username
+		}
+	}
+
+	return &existing, nil
+}
+
+func (s *memoryStore) Delete(username string, version uint, position *data.Position) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.players[username]
+	if !ok {
+		return errors.ENotFound.NewError("player does not exist")
+	}
+
+	if version != 0 && version != existing.Version {
+		return versionMismatch(username, version)
+	}
+
+	now := time.Now()
+	existing.DeletedAt = &now
+	if position != nil {
+		existing.DeletedPosition = position.Encode()
+	}
+
+	delete(s.players, username)
+	s.deleted[username] = existing
+	return nil
+}
+
+func (s *memoryStore) Restore(username string) (*Player, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	player, ok := s.deleted[username]
+	if !ok {
+		return nil, errors.ENotFound.NewError("no deleted player to restore")
+	}
+
+	restored := player
+	player.DeletedAt = nil
+	player.DeletedPosition = ""
+	player.Version++
+	player.UpdatedAt = time.Now()
+
+	delete(s.deleted, username)
+	s.players[username] = player
+
+	restored.DeletedAt = nil
+	restored.Version = player.Version
+	restored.UpdatedAt = player.UpdatedAt
+	return &restored, nil
+}
+
+func (s *memoryStore) Purge(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for username, player := range s.deleted {
+		if player.DeletedAt.Before(before) {
+			delete(s.deleted, username)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (s *memoryStore) AddViolation(violation *Violation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	violation.ID = uint(len(s.violations) + 1)
+	s.violations = append(s.violations, *violation)
+	return nil
+}
+
+func (s *memoryStore) Violations(username string) ([]*Violation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	violations := make([]*Violation, 0)
+	for i := len(s.violations) - 1; i >= 0; i-- {
+		if s.violations[i].Username == username {
+			v := s.violations[i]
+			violations = append(violations, &v)
+		}
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,129 @@
+package players
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/migrate"
+	"github.com/jinzhu/gorm"
+)
+
+// migrations - schema history for the tables managed by this service. Each
+// migration declares its own copy of the models it touches, so that later
+// changes to the Player struct do not change what old migrations do.
+var migrations = []*migrate.Migration{
+	{
+		Version: 1,
+		Name:    "create_player",
+		Up: func(tx *gorm.DB) error {
+			type player struct {
+				Username  string `gorm:"primary_key"`
+				Password  string
+				CreatedAt time.Time `gorm:"type:timestamp"`
+				UpdatedAt time.Time `gorm:"type:timestamp"`
+			}
+
+			// tables created by AutoMigrate before versioned migrations existed
+			if tx.HasTable(&player{}) {
+				return nil
+			}
+
+			return tx.CreateTable(&player{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists("player").Error
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add_player_version",
+		Up: func(tx *gorm.DB) error {
+			type player struct {
+				Username string `gorm:"primary_key"`
+				Version  uint   `gorm:"not null;default:1"`
+			}
+
+			return tx.AutoMigrate(&player{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			type player struct {
+				Username  string `gorm:"primary_key"`
+				Password  string
+				CreatedAt time.Time `gorm:"type:timestamp"`
+				UpdatedAt time.Time `gorm:"type:timestamp"`
+			}
+
+			return migrate.DropColumn(tx, &player{}, "version")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add_player_deleted_at",
+		Up: func(tx *gorm.DB) error {
+			type player struct {
+				Username  string     `gorm:"primary_key"`
+				DeletedAt *time.Time `gorm:"type:timestamp;index"`
+			}
+
+			return tx.AutoMigrate(&player{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			type player struct {
+				Username  string `gorm:"primary_key"`
+				Password  string
+				Version   uint      `gorm:"not null;default:1"`
+				CreatedAt time.Time `gorm:"type:timestamp"`
+				UpdatedAt time.Time `gorm:"type:timestamp"`
+			}
+
+			return migrate.DropColumn(tx, &player{}, "deleted_at")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create_violation",
+		Up: func(tx *gorm.DB) error {
+			type violation struct {
+				ID        uint   `gorm:"primary_key"`
+				Username  string `gorm:"index"`
+				Location  string
+				FromX     int
+				FromY     int
+				ToX       int
+				ToY       int
+				Distance  float64
+				Allowed   float64
+				Clamped   bool
+				CreatedAt time.Time `gorm:"type:timestamp"`
+			}
+
+			return tx.CreateTable(&violation{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists("violation").Error
+		},
+	},
+	{
+		Version: 5,
+		Name:    "add_player_deleted_position",
+		Up: func(tx *gorm.DB) error {
+			type player struct {
+				Username        string `gorm:"primary_key"`
+				DeletedPosition string
+			}
+
+			return tx.AutoMigrate(&player{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			type player struct {
+				Username  string `gorm:"primary_key"`
+				Password  string
+				Version   uint       `gorm:"not null;default:1"`
+				CreatedAt time.Time  `gorm:"type:timestamp"`
+				UpdatedAt time.Time  `gorm:"type:timestamp"`
+				DeletedAt *time.Time `gorm:"type:timestamp;index"`
+			}
+
+			return migrate.DropColumn(tx, &player{}, "deleted_position")
+		},
+	},
+}
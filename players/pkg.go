@@ -4,20 +4,32 @@ import (
 	"github.com/carsonmyers/bublar-assignment/connect"
 	"github.com/carsonmyers/bublar-assignment/errors"
 	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/carsonmyers/bublar-assignment/migrate"
 )
 
-var (
-	log    = logger.GetLogger()
-	models = make([]interface{}, 0, 5)
-)
+var log = logger.GetLogger()
 
-// Migrate - migrate all tables managed by this service
-func Migrate() error {
-	db, err := connect.Postgres()
+// Migrator - get the schema migrator for this service, or nil if the
+// configured storage backend has no schema
+func Migrator() (*migrate.Migrator, error) {
+	if _, ok := GetStore().(*gormStore); !ok {
+		return nil, nil
+	}
+
+	db, err := connect.Database()
 	if err != nil {
-		return errors.EDatabaseConnection.NewError(err)
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	return migrate.New(db, "players", migrations), nil
+}
+
+// Migrate - apply all pending migrations for tables managed by this service
+func Migrate() error {
+	m, err := Migrator()
+	if err != nil || m == nil {
+		return err
 	}
 
-	db.AutoMigrate(models...)
-	return nil
+	return m.Up()
 }
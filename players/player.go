@@ -4,25 +4,31 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
 	"time"
 
 	"github.com/gbrlsnchs/jwt/v2"
-	"github.com/go-redis/redis"
-	"github.com/jinzhu/gorm"
 
-	"github.com/carsonmyers/bublar-assignment/connect"
 	"github.com/carsonmyers/bublar-assignment/data"
 	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/positions"
+	"github.com/carsonmyers/bublar-assignment/ratelimit"
+	"github.com/carsonmyers/bublar-assignment/validate"
 	"go.uber.org/zap"
 )
 
 // Player - persisted user data
 type Player struct {
-	Username  string    `json:"username" gorm:"primary_key"`
-	Password  string    `json:"-"`
-	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
-	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+	Username  string     `json:"username" gorm:"primary_key"`
+	Password  string     `json:"-"`
+	Version   uint       `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time  `json:"updatedAt" gorm:"type:timestamp"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty" gorm:"type:timestamp;index"`
+
+	// DeletedPosition - the encoded position of a deleted player, kept out of
+	// their location until they are restored or purged
+	DeletedPosition string `json:"-"`
 }
 
 // ToPlayer - convert to universal data format
@@ -32,31 +38,34 @@ func (p *Player) ToPlayer() *data.Player {
 	return &data.Player{
 		Username: p.Username,
 		Password: &pw,
+		Version:  p.Version,
 	}
 }
 
 // CreatePlayer - add a new player to the system
 func CreatePlayer(player *data.Player) (*data.Player, error) {
-	db, err := connect.Postgres()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+	var pw string
+	if player.Password != nil {
+		pw = *player.Password
 	}
 
-	var count uint64
-	q := db.Model(&Player{}).Where(&Player{Username: player.Username}).Count(&count)
-	if err := q.Error; err != nil {
-		log.Error("Error counting existing users", zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+	if err := validate.New().
+		Check("username", player.Username, validate.Username...).
+		Check("password", pw, validate.Password...).
+		Err(); err != nil {
+		return nil, err
 	}
 
-	if count != 0 {
-		log.Error("Attempt to create a duplicate user", zap.String("username", player.Username))
-		return nil, errors.EDuplicateUser.NewError(player.Username)
+	accounts := GetStore()
+
+	exists, err := accounts.Exists(player.Username)
+	if err != nil {
+		return nil, err
 	}
 
-	var pw string
-	if player.Password != nil {
-		pw = *player.Password
+	if exists {
+		log.Error("Attempt to create a duplicate user", zap.String("username", player.Username))
+		return nil, errors.EDuplicateUser.NewError(player.Username).WithDetail("username", player.Username)
 	}
 
 	hashed, err := hashPassword(pw)
@@ -67,13 +76,13 @@ func CreatePlayer(player *data.Player) (*data.Player, error) {
 	playerModel := &Player{
 		Username:  player.Username,
 		Password:  hashed,
+		Version:   1,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := db.Create(playerModel).Error; err != nil {
-		log.Error("Failed to store new user", zap.String("username", player.Username), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+	if err := accounts.Create(playerModel); err != nil {
+		return nil, err
 	}
 
 	return playerModel.ToPlayer(), nil
@@ -81,19 +90,12 @@ func CreatePlayer(player *data.Player) (*data.Player, error) {
 
 // AuthPlayer - athenticate an existing player, generating an auth token
 func AuthPlayer(username, password string) (*jwt.JWT, error) {
-	db, err := connect.Postgres()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
-	}
-
-	var player Player
-	if err := db.Where(&Player{Username: username}).First(&player).Error; err != nil {
-		if gorm.IsRecordNotFoundError(err) {
-			return nil, errors.ENotFound.NewError(err)
-		}
-
-		log.Error("Error fetching user for auth", zap.String("username", username), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+	player, err := GetStore().Get(username)
+	if errors.IsKind(err, errors.ENotFound) {
+		log.Error("Login for unknown player", zap.String("username", username))
+		return nil, errors.EAuth.NewErrorf("login failed")
+	} else if err != nil {
+		return nil, err
 	}
 
 	if !testPassword(password, player.Password) {
@@ -112,106 +114,176 @@ func AuthPlayer(username, password string) (*jwt.JWT, error) {
 
 // GetPlayer - fetch a single player by username
 func GetPlayer(id string) (*data.Player, error) {
-	pdb, err := connect.Postgres()
+	player, err := GetStore().Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := player.ToPlayer()
+	pos, err := positions.GetStore().Get(player.Username)
 	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+		return nil, err
 	}
 
-	var player Player
-	if err := pdb.Where(&Player{Username: id}).First(&player).Error; err != nil {
-		if gorm.IsRecordNotFoundError(err) {
-			return nil, errors.ENotFound.NewError(err)
+	result.Position = pos
+	return result, nil
+}
+
+// GetPlayers - fetch several players by username, looking up their positions
+// together. Players which don't exist are left out.
+func GetPlayers(usernames []string) ([]*data.Player, error) {
+	result := make([]*data.Player, 0, len(usernames))
+	found := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		player, err := GetStore().Get(username)
+		if errors.IsKind(err, errors.ENotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
 		}
 
-		log.Error("Error fetching user data", zap.String("username", id), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+		result = append(result, player.ToPlayer())
+		found = append(found, player.Username)
 	}
 
-	result := player.ToPlayer()
-	rdb, err := connect.Redis()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+	if len(found) == 0 {
+		return result, nil
 	}
 
-	key := fmt.Sprintf("%s:position", player.Username)
-	encoded, err := rdb.Get(key).Result()
+	pos, err := positions.GetStore().GetMany(found)
 	if err != nil {
-		if err != redis.Nil {
-			log.Error("Failed to get position for player", zap.String("username", player.Username), zap.Error(err))
-			return nil, errors.EDatabase.NewError(err)
-		} else {
-			log.Debug("No position for user", zap.String("username", player.Username))
-		}
-	} else {
-		pos := &data.Position{}
-		if err := pos.Decode(encoded); err != nil {
-			log.Error("Failed to decode player position", zap.String("username", player.Username), zap.Error(err))
-			return nil, errors.EDatabase.NewError(err)
-		}
+		return nil, err
+	}
 
-		log.Debug("Adding position to user", zap.String("username", player.Username), zap.String("position", encoded), zap.String("location", pos.Location))
-		result.Position = pos
+	for _, player := range result {
+		player.Position = pos[player.Username]
 	}
 
 	return result, nil
 }
 
-// ListPlayers - retrieve all users
-func ListPlayers() ([]*data.Player, error) {
-	pdb, err := connect.Postgres()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
-	}
+// ListFilter - restrict the players which are listed
+type ListFilter struct {
+	// Prefix - only list players whose username starts with this
+	Prefix string
 
-	rdb, err := connect.Redis()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+	// Location - only list players in this location
+	Location string
+
+	// Online - only list players who are in a location
+	Online bool
+}
+
+// listBatchSize - number of players whose positions are looked up together
+const listBatchSize = 100
+
+// ListPlayers - retrieve one page of users, passing each to send as soon as
+// its position is known. Returns a cursor for the next page if there is one.
+func ListPlayers(page *paging.Query, filter *ListFilter, send func(*data.Player) error) (*paging.Cursor, error) {
+	posStore := positions.GetStore()
+
+	query := &ListQuery{
+		Query:  *page,
+		Prefix: filter.Prefix,
 	}
 
-	var players []*Player
-	if err := pdb.Find(&players).Error; err != nil {
-		log.Error("Error fetching all users", zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+	if len(filter.Location) > 0 {
+		members, err := posStore.Members(filter.Location)
+		if err != nil {
+			return nil, err
+		}
+
+		query.Usernames = make([]string, len(members))
+		for i, member := range members {
+			query.Usernames[i] = member.Username
+		}
 	}
 
-	response := make([]*data.Player, len(players))
-	for i, player := range players {
-		response[i] = player.ToPlayer()
+	// one extra player is fetched to find out whether there is another page.
+	// Players without a position are skipped when only listing online players,
+	// so pages are fetched until enough are found or the list runs out.
+	query.Limit = page.Limit + 1
 
-		key := fmt.Sprintf("%s:position", player.Username)
-		encoded, err := rdb.Get(key).Result()
+	sent := 0
+	var last *paging.Cursor
+	for {
+		players, err := GetStore().List(query)
 		if err != nil {
-			if err != redis.Nil {
-				log.Error("Failed to get position for player", zap.String("username", player.Username), zap.Error(err))
-				return nil, errors.EDatabase.NewError(err)
-			} else {
-				log.Debug("No position for user", zap.String("username", player.Username))
+			return nil, err
+		}
+
+		for start := 0; start < len(players); start += listBatchSize {
+			end := start + listBatchSize
+			if end > len(players) {
+				end = len(players)
+			}
+
+			batch := players[start:end]
+			usernames := make([]string, len(batch))
+			for i, player := range batch {
+				usernames[i] = player.Username
 			}
-		} else {
-			pos := &data.Position{}
-			if err := pos.Decode(encoded); err != nil {
-				log.Error("Failed to decode player position", zap.String("username", player.Username), zap.Error(err))
-				return nil, errors.EDatabase.NewError(err)
+
+			found, err := posStore.GetMany(usernames)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, player := range batch {
+				pos := found[player.Username]
+				if pos == nil && filter.Online {
+					continue
+				}
+
+				if sent == page.Limit {
+					return last, nil
+				}
+
+				result := player.ToPlayer()
+				result.Position = pos
+				if err := send(result); err != nil {
+					return nil, err
+				}
+
+				sent++
+				last = player.Cursor(page.Sort)
 			}
+		}
 
-			log.Debug("Adding position to user", zap.String("username", player.Username), zap.String("position", encoded), zap.String("location", pos.Location))
-			response[i].Position = pos
+		if len(players) < query.Limit {
+			return nil, nil
 		}
-	}
 
-	return response, nil
+		query.After = players[len(players)-1].Cursor(page.Sort)
+	}
 }
 
-// UpdatePlayer - update a player's username and/or password
+// UpdatePlayer - update a player's username and/or password. An empty
+// username or password is left as it is. If the version is not 0, the player
+// is only updated if they haven't changed since that version.
 func UpdatePlayer(id string, player *data.Player) (*data.Player, error) {
-	db, err := connect.Postgres()
-	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+	if len(player.Username) == 0 {
+		player.Username = id
+	}
+
+	if player.Username != id {
+		if err := validate.New().Check("username", player.Username, validate.Username...).Err(); err != nil {
+			return nil, err
+		}
+
+		exists, err := GetStore().Exists(player.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			return nil, errors.EDuplicateUser.NewError(player.Username).WithDetail("username", player.Username)
+		}
 	}
 
-	updates := map[string]interface{}{
-		"username":   player.Username,
-		"updated_at": time.Now(),
+	changes := &Player{
+		Username: player.Username,
+		Version:  player.Version,
 	}
 
 	var pw string
@@ -220,73 +292,112 @@ func UpdatePlayer(id string, player *data.Player) (*data.Player, error) {
 	}
 
 	if len(pw) != 0 {
+		if err := validate.New().Check("password", pw, validate.Password...).Err(); err != nil {
+			return nil, err
+		}
+
 		hashed, err := hashPassword(pw)
 		if err != nil {
 			return nil, errors.EInternal.NewError(err)
 		}
 
-		updates["password"] = hashed
+		changes.Password = hashed
+	}
+
+	// positions are kept by username, so a renamed player's position is moved
+	// over before the rename is committed, and the rename is rolled back if it
+	// can't be. If instead the commit fails after it was moved (positions kept
+	// outside the database aren't part of its transaction), it is moved back.
+	var renamed bool
+	updated, err := GetStore().Update(id, changes, func(pos positions.Store) error {
+		if changes.Username == id {
+			return nil
+		}
+
+		if err := pos.RenamePlayer(id, changes.Username); err != nil {
+			return err
+		}
+
+		renamed = true
+		return nil
+	})
+	if err != nil {
+		if renamed {
+			if err := positions.GetStore().RenamePlayer(changes.Username, id); err != nil {
+				log.Error("Failed to move position back after failed rename", zap.String("username", id), zap.Error(err))
+			}
+		}
+
+		return nil, err
+	}
+
+	// failed logins and gameplay limits follow the player too, so renaming
+	// doesn't reset them
+	if updated.Username != id {
+		if err := ratelimit.RenamePlayer(id, updated.Username); err != nil {
+			log.Error("Failed to move rate limits to renamed player", zap.String("username", id), zap.String("to", updated.Username), zap.Error(err))
+		}
 	}
 
-	var updated Player
-	q := db.Where(&Player{Username: id}).Model(&updated).Updates(updates)
-	if err := q.Error; err != nil {
-		log.Error("Failed to patch player", zap.String("username", id), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+	result := updated.ToPlayer()
+	pos, err := positions.GetStore().Get(updated.Username)
+	if err != nil {
+		return nil, err
 	}
 
-	return updated.ToPlayer(), nil
+	result.Position = pos
+	return result, nil
 }
 
-// DeletePlayer - delete an existing user
-func DeletePlayer(id string) error {
-	pdb, err := connect.Postgres()
+// DeletePlayer - delete an existing user, who can be restored until they are
+// purged. If version is not 0, the user is only deleted if they haven't
+// changed since that version.
+func DeletePlayer(id string, version uint) error {
+	pos, err := positions.GetStore().Get(id)
 	if err != nil {
-		return errors.EDatabaseConnection.NewError(err)
+		return err
 	}
 
-	q := pdb.Delete(&Player{Username: id})
-	if err := q.Error; err != nil {
-		log.Error("Error deleting player", zap.String("username", id), zap.Error(err))
-		return errors.EDatabase.NewError(err)
+	if err := GetStore().Delete(id, version, pos); err != nil {
+		return err
 	}
 
-	if q.RowsAffected == 0 {
-		log.Error("Cannot delete nonexistent player", zap.String("username", id), zap.Error(err))
-		return errors.EDatabase.NewError("player does not exist")
-	}
+	return positions.GetStore().Remove(id)
+}
 
-	rdb, err := connect.Redis()
+// RestorePlayer - bring back a deleted user who has not been purged, along
+// with the position they had when they were deleted. If their location no
+// longer exists, they are not in any location until they travel again.
+func RestorePlayer(id string) (*data.Player, error) {
+	restored, err := GetStore().Restore(id)
 	if err != nil {
-		return errors.EDatabaseConnection.NewError(err)
+		return nil, err
+	}
+
+	result := restored.ToPlayer()
+	if len(restored.DeletedPosition) == 0 {
+		return result, nil
 	}
 
 	pos := &data.Position{}
-	key := fmt.Sprintf("%s:position", id)
-	loc, err := rdb.Get(key).Result()
-	if err != nil {
-		if err != redis.Nil {
-			log.Error("Error fetching location for user", zap.String("username", id), zap.Error(err))
-			return errors.EDatabaseConnection.NewError(err)
-		}
-	} else {
-		if err := pos.Decode(loc); err != nil {
-			log.Error("Error decoding location record for user", zap.String("username", id), zap.String("data", loc), zap.Error(err))
-			return err
-		}
+	if err := pos.Decode(restored.DeletedPosition); err != nil {
+		log.Error("Failed to decode position of deleted player", zap.String("username", id), zap.String("position", restored.DeletedPosition), zap.Error(err))
+		return result, nil
+	}
 
-		if _, err := rdb.Del(key).Result(); err != nil {
-			log.Error("Error deleting location record for user", zap.String("username", id), zap.Error(err))
-		}
+	if _, err := checkLocation(pos.Location); errors.IsKind(err, errors.EUnknownLocation) {
+		log.Info("Location of restored player no longer exists", zap.String("username", id), zap.String("location", pos.Location))
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
 
-		key = fmt.Sprintf("location:%s", pos.Location)
-		if _, err := rdb.SRem(key, id).Result(); err != nil {
-			log.Error("Error removing deleted user from location", zap.String("username", id), zap.String("location", pos.Location), zap.Error(err))
-			return errors.EDatabase.NewError(err)
-		}
+	if err := positions.GetStore().Set(id, pos); err != nil {
+		return nil, err
 	}
 
-	return nil
+	result.Position = pos
+	return result, nil
 }
 
 const saltLength int = 64
@@ -310,7 +421,3 @@ func testPassword(cleartext string, hashed string) bool {
 	digest := sha256.Sum256([]byte(salted))
 	return salt+hex.EncodeToString(digest[:]) == hashed
 }
-
-func init() {
-	models = append(models, &Player{})
-}
@@ -0,0 +1,176 @@
+package players
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/positions"
+)
+
+// useMemoryStores - keep players and positions in memory for the length of a
+// test
+func useMemoryStores(t *testing.T) {
+	SetStore(NewMemoryStore())
+	positions.SetStore(positions.NewMemoryStore())
+	t.Cleanup(func() {
+		SetStore(nil)
+		positions.SetStore(nil)
+	})
+}
+
+func TestListPlayers(t *testing.T) {
+	useMemoryStores(t)
+
+	placed := map[string]*data.Position{
+		"alice": {Location: "town", X: 1, Y: 2},
+		"carol": {Location: "field", X: -3, Y: 4},
+		"erin":  {Location: "town", X: 5, Y: 6},
+	}
+
+	for _, username := range []string{"alice", "bob", "carol", "dave", "erin", "frank"} {
+		if err := GetStore().Create(&Player{Username: username, Version: 1, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("creating %s: %v", username, err)
+		}
+
+		if pos, ok := placed[username]; ok {
+			if err := positions.GetStore().Set(username, pos); err != nil {
+				t.Fatalf("placing %s: %v", username, err)
+			}
+		}
+	}
+
+	cases := []struct {
+		name   string
+		limit  int
+		filter ListFilter
+		want   [][]string
+	}{
+		{"all", 0, ListFilter{}, [][]string{{"alice", "bob", "carol", "dave", "erin", "frank"}}},
+		{"pages", 4, ListFilter{}, [][]string{{"alice", "bob", "carol", "dave"}, {"erin", "frank"}}},
+		{"online", 0, ListFilter{Online: true}, [][]string{{"alice", "carol", "erin"}}},
+		{"online pages", 2, ListFilter{Online: true}, [][]string{{"alice", "carol"}, {"erin"}}},
+		{"online pages of one", 1, ListFilter{Online: true}, [][]string{{"alice"}, {"carol"}, {"erin"}}},
+		{"location", 0, ListFilter{Location: "town"}, [][]string{{"alice", "erin"}}},
+		{"empty location", 0, ListFilter{Location: "cave"}, [][]string{{}}},
+		{"prefix", 0, ListFilter{Prefix: "d"}, [][]string{{"dave"}}},
+		{"prefix online", 0, ListFilter{Prefix: "d", Online: true}, [][]string{{}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			after := ""
+			for i, want := range c.want {
+				page, err := paging.NewQuery(c.limit, after, "", SortFields...)
+				if err != nil {
+					t.Fatalf("NewQuery: %v", err)
+				}
+
+				got := make([]string, 0)
+				filter := c.filter
+				next, err := ListPlayers(page, &filter, func(player *data.Player) error {
+					got = append(got, player.Username)
+
+					// every player comes with their position, or none if they
+					// aren't in a location
+					pos := placed[player.Username]
+					if (player.Position == nil) != (pos == nil) || (pos != nil && *player.Position != *pos) {
+						t.Errorf("%s is at %v, want %v", player.Username, player.Position, pos)
+					}
+
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("ListPlayers: %v", err)
+				}
+
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Errorf("page %d has %v, want %v", i, got, want)
+				}
+
+				last := i == len(c.want)-1
+				if last != (next == nil) {
+					t.Fatalf("page %d has next cursor %v, want one only before the last page", i, next)
+				}
+
+				after = next.Encode()
+			}
+		})
+	}
+}
+
+// BenchmarkListPlayers - list a page of players from redis positions, looking
+// each position up on its own against fetching them with MGET in batches
+func BenchmarkListPlayers(b *testing.B) {
+	srv, err := miniredis.Run()
+	if err != nil {
+		b.Fatalf("starting miniredis: %v", err)
+	}
+	defer srv.Close()
+
+	configure.Redis(&configure.RedisConfig{
+		Host: srv.Host(),
+		Port: uint(srv.Server().Addr().Port),
+	})
+
+	SetStore(NewMemoryStore())
+	positions.SetStore(positions.NewRedisStore())
+	defer SetStore(nil)
+	defer positions.SetStore(nil)
+
+	const players = 500
+	for i := 0; i < players; i++ {
+		username := fmt.Sprintf("player%04d", i)
+		if err := GetStore().Create(&Player{Username: username, Version: 1, CreatedAt: time.Now()}); err != nil {
+			b.Fatalf("creating %s: %v", username, err)
+		}
+
+		// every other player is in a location
+		if i%2 == 0 {
+			if err := positions.GetStore().Set(username, &data.Position{Location: "town", X: i, Y: i}); err != nil {
+				b.Fatalf("placing %s: %v", username, err)
+			}
+		}
+	}
+
+	page, err := paging.NewQuery(players, "", "", SortFields...)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	send := func(*data.Player) error { return nil }
+
+	b.Run("get", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			list, err := GetStore().List(&ListQuery{Query: *page})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			for _, player := range list {
+				pos, err := positions.GetStore().Get(player.Username)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				result := player.ToPlayer()
+				result.Position = pos
+				if err := send(result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("mget", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if _, err := ListPlayers(page, &ListFilter{}, send); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -1,118 +1,208 @@
 package players
 
 import (
-	"fmt"
+	"math"
 	"time"
 
+	"github.com/carsonmyers/bublar-assignment/configure"
 	"github.com/carsonmyers/bublar-assignment/connect"
 	"github.com/carsonmyers/bublar-assignment/data"
 	"github.com/carsonmyers/bublar-assignment/errors"
-	"github.com/go-redis/redis"
+	"github.com/carsonmyers/bublar-assignment/locations/rpc"
+	"github.com/carsonmyers/bublar-assignment/positions"
+	"github.com/carsonmyers/bublar-assignment/proto"
 	"go.uber.org/zap"
 )
 
-const exp = 48 * time.Hour
-
-// Travel - move a player to a new location
+// Travel - move a player to a new location, where they start on the first
+// tile which isn't blocked
 func Travel(player *data.Player, location string) (*data.Position, error) {
 	log.Debug("Travel player to new location", zap.String("username", player.Username), zap.String("location", location))
-	db, err := connect.Redis()
+
+	loc, err := checkLocation(location)
 	if err != nil {
-		return nil, errors.EDatabaseConnection.NewError(err)
+		return nil, err
 	}
 
-	pos := &data.Position{}
-
-	key := fmt.Sprintf("%s:position", player.Username)
-	encoded, err := db.Get(key).Result()
-	if err != nil {
-		if err != redis.Nil {
-			log.Error("Failed to get position for player", zap.String("username", player.Username), zap.Error(err))
-			return nil, errors.EDatabase.NewError(err)
-		}
-	} else {
-		if err := pos.Decode(encoded); err != nil {
-			log.Error("Failed to decode player position", zap.String("username", player.Username), zap.String("position", encoded), zap.Error(err))
-			return nil, errors.EDatabase.NewError(err)
-		}
+	x, y, ok := loc.Entrance()
+	if !ok {
+		return nil, errors.EBlocked.NewErrorf("every tile of location `%s` is blocked", location).WithContext("location").WithDetail("location", location)
+	}
 
-		key = fmt.Sprintf("location:%s", pos.Location)
-		_, err = db.SRem(key, player.Username).Result()
-		if err != nil {
-			log.Error("Failed to remove player from origin", zap.String("username", player.Username), zap.String("location", pos.Location), zap.Error(err))
-			return nil, errors.EDatabase.NewError(err)
-		}
+	pos := &data.Position{
+		Location: location,
+		X:        x,
+		Y:        y,
 	}
 
-	pos.Location = location
-	pos.X = 0
-	pos.Y = 0
+	if err := positions.GetStore().Set(player.Username, pos); err != nil {
+		return nil, err
+	}
 
-	key = fmt.Sprintf("%s:position", player.Username)
-	log.Debug("Storing new position", zap.String("key", key), zap.String("data", pos.Encode()))
+	player.Position = pos
+	return pos, nil
+}
 
-	_, err = db.Set(key, pos.Encode(), exp).Result()
+// checkLocation - make sure a location exists before a player travels to it
+func checkLocation(name string) (*data.Location, error) {
+	locationSvc, err := connect.Locations()
 	if err != nil {
-		log.Error("Failed to set position for player", zap.String("username", player.Username), zap.String("location", location), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+		return nil, err
 	}
 
-	player.Position = pos
-
-	key = fmt.Sprintf("location:%s", location)
-	_, err = db.SAdd(key, player.Encode()).Result()
+	loc, err := locationSvc.Get(&proto.Location{Name: name})
 	if err != nil {
-		log.Error("Failed to add player to location", zap.String("username", player.Username), zap.String("location", location), zap.Error(err))
-		return nil, errors.EDatabase.NewError(err)
+		if errors.IsKind(err, errors.ENotFound) {
+			return nil, errors.EUnknownLocation.NewErrorf("cannot travel to unknown location `%s`", name).WithContext("location").WithDetail("location", name)
+		}
+
+		log.Error("Failed to check location", zap.String("location", name), zap.Error(err))
+		return nil, err
 	}
 
-	return pos, nil
+	return rpc.LocationData(loc), nil
 }
 
-// Move - set the position of a playwer within their location
-func Move(player *data.Player, x int, y int) error {
-	db, err := connect.Redis()
-	if err != nil {
-		return errors.EDatabaseConnection.NewError(err)
+// checkBlocked - make sure a player can stand at a point in their location
+func checkBlocked(loc *data.Location, x, y int) error {
+	if !loc.Blocks(x, y) {
+		return nil
 	}
 
-	key := fmt.Sprintf("%s:position", player.Username)
-	encoded, err := db.Get(key).Result()
-	if err != nil {
-		if err == redis.Nil {
+	return errors.EBlocked.NewErrorf("cannot move to %d,%d in location `%s`", x, y, loc.Name).
+		WithDetail("x", x).
+		WithDetail("y", y)
+}
+
+// maxMoveAttempts - how many times a speed limited move is checked again
+// when the player moves while it is being checked
+const maxMoveAttempts = 3
+
+// Move - set the position of a playwer within their location. Players can't
+// move outside of the location's bounds or onto its blocked tiles. A move
+// which is speed limited can't go further than the player could have moved
+// since their last move; depending on the configuration, it is rejected or cut
+// short, and recorded as a violation either way. The limited move is only
+// stored if the player hasn't moved since it was checked, so concurrent moves
+// can't share the same allowance.
+func Move(player *data.Player, x int, y int, limitSpeed bool) error {
+	posStore := positions.GetStore()
+
+	for attempt := 0; attempt < maxMoveAttempts; attempt++ {
+		pos, err := posStore.Get(player.Username)
+		if err != nil {
+			return err
+		}
+
+		if pos == nil {
 			return errors.ENotInLocation.NewErrorf("User is not in a location")
 		}
 
-		log.Error("Failed to get position for player", zap.String("username", player.Username), zap.Error(err))
-		return errors.EDatabase.NewError(err)
-	}
+		player.Position = pos
+
+		loc, err := checkLocation(pos.Location)
+		if err != nil {
+			return err
+		}
 
-	pos := &data.Position{}
-	if err := pos.Decode(encoded); err != nil {
-		log.Error("Failed to decode player position", zap.String("username", player.Username), zap.String("position", encoded), zap.Error(err))
-		return errors.EDatabase.NewError(err)
+		if err := checkBlocked(loc, x, y); err != nil {
+			return err
+		}
+
+		if !limitSpeed || configure.GetPlayers().MaxSpeed <= 0 {
+			pos.X = x
+			pos.Y = y
+
+			return posStore.Set(player.Username, pos)
+		}
+
+		moved, err := posStore.Moved(player.Username)
+		if err != nil {
+			return err
+		}
+
+		to, violation, err := checkSpeed(player.Username, pos, moved, x, y)
+		if err != nil {
+			return err
+		}
+
+		// a move cut short can end on a blocked tile
+		if err := checkBlocked(loc, to.X, to.Y); err != nil {
+			return err
+		}
+
+		set, err := posStore.CompareAndSet(player.Username, pos, moved, to)
+		if err != nil {
+			return err
+		}
+
+		if !set {
+			log.Debug("Player moved while their move was checked", zap.String("username", player.Username), zap.Int("attempt", attempt))
+			continue
+		}
+
+		player.Position = to
+
+		if violation != nil {
+			if err := GetStore().AddViolation(violation); err != nil {
+				log.Error("Failed to record speed violation", zap.String("username", player.Username), zap.Error(err))
+			}
+		}
+
+		return nil
 	}
 
-	player.Position = pos
+	return errors.EVersionMismatch.NewErrorf("player `%s` kept moving while the move was checked", player.Username)
+}
 
-	posKey := fmt.Sprintf("location:%s", pos.Location)
-	if _, err := db.SRem(posKey, player.Encode()).Result(); err != nil {
-		log.Error("Failed to remove outdated record from location", zap.String("username", player.Username), zap.String("location", pos.Location), zap.Error(err))
-		return errors.EDatabase.NewError(err)
+// checkSpeed - make sure a player could have moved from their position to a
+// new one since they last moved at `moved`, returning where they may move to.
+// A move which is too fast is rejected after recording its violation; a move
+// which is cut short returns its violation, to be recorded once it is stored.
+func checkSpeed(username string, from *data.Position, moved time.Time, x, y int) (*data.Position, *Violation, error) {
+	to := &data.Position{Location: from.Location, X: x, Y: y}
+	if moved.IsZero() {
+		return to, nil, nil
 	}
 
-	pos.X = x
-	pos.Y = y
+	conf := configure.GetPlayers()
 
-	if _, err := db.Set(key, pos.Encode(), exp).Result(); err != nil {
-		log.Error("Failed to set position for player", zap.String("username", player.Username), zap.Error(err))
-		return errors.EDatabase.NewError(err)
+	dx, dy := float64(x-from.X), float64(y-from.Y)
+	distance := math.Hypot(dx, dy)
+	allowed := conf.MaxSpeed * time.Since(moved).Seconds()
+	if distance <= allowed {
+		return to, nil, nil
 	}
 
-	if _, err := db.SAdd(posKey, player.Encode()).Result(); err != nil {
-		log.Error("Failed to add location record for moved player", zap.String("username", player.Username), zap.String("location", pos.Location), zap.Error(err))
-		return errors.EDatabase.NewError(err)
+	violation := &Violation{
+		Username:  username,
+		Location:  from.Location,
+		FromX:     from.X,
+		FromY:     from.Y,
+		ToX:       x,
+		ToY:       y,
+		Distance:  distance,
+		Allowed:   allowed,
+		Clamped:   conf.SpeedViolation == configure.SpeedClamp,
+		CreatedAt: time.Now(),
 	}
 
-	return nil
+	log.Warn("Player moved too fast", zap.String("username", username), zap.Float64("distance", distance), zap.Float64("allowed", allowed), zap.Bool("clamped", violation.Clamped))
+
+	if !violation.Clamped {
+		if err := GetStore().AddViolation(violation); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, errors.ETooFast.NewErrorf("cannot move %.1f in %s", distance, time.Since(moved).Round(time.Millisecond)).
+			WithDetail("distance", distance).
+			WithDetail("allowed", allowed)
+	}
+
+	// truncating towards the start keeps the move within the allowed distance
+	scale := allowed / distance
+	to.X = from.X + int(dx*scale)
+	to.Y = from.Y + int(dy*scale)
+
+	return to, violation, nil
 }
@@ -0,0 +1,23 @@
+package players
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/purge"
+)
+
+// PurgeDeleted - permanently remove players deleted longer ago than the
+// configured retention period
+func PurgeDeleted() (int, error) {
+	return purge.Deleted("players", purgeStore)
+}
+
+// StartPurge - purge deleted players in the background at the configured
+// interval. Purging is disabled if the interval is not positive.
+func StartPurge() {
+	purge.Start("players", purgeStore)
+}
+
+func purgeStore(before time.Time) (int, error) {
+	return GetStore().Purge(before)
+}
@@ -7,17 +7,22 @@ import (
 	"time"
 
 	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/errors/rpcstatus"
 	"github.com/carsonmyers/bublar-assignment/logger"
 	"github.com/carsonmyers/bublar-assignment/proto"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 var log = logger.GetLogger()
 
 // Client - RPC client for players service
 type Client struct {
+	conn   *grpc.ClientConn
 	client proto.PlayersClient
+	// md - metadata sent with every request, such as who made it
+	md metadata.MD
 }
 
 // NewClient - create a new RPC client
@@ -25,6 +30,8 @@ func NewClient() (*Client, error) {
 	var opts = []grpc.DialOption{
 		grpc.WithInsecure(),
 		grpc.WithBlock(),
+		grpc.WithUnaryInterceptor(rpcstatus.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(rpcstatus.StreamClientInterceptor),
 	}
 
 	conf := configure.GetPlayers()
@@ -34,10 +41,32 @@ func NewClient() (*Client, error) {
 	}
 
 	return &Client{
+		conn:   conn,
 		client: proto.NewPlayersClient(conn),
 	}, nil
 }
 
+// With - a client which sends the outgoing metadata of a context, such as
+// the caller attached by the API, with every request. Requests still get
+// their own timeout rather than the context's deadline.
+func (c *Client) With(ctx context.Context) *Client {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return c
+	}
+
+	return &Client{
+		conn:   c.conn,
+		client: c.client,
+		md:     md,
+	}
+}
+
+// Conn - the connection to the service, for invoking methods by name
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
 // Create - send a player create request
 func (c *Client) Create(player *proto.Player) (*proto.Player, error) {
 	ctx, cancel := c.ctx()
@@ -59,17 +88,44 @@ func (c *Client) Auth(player *proto.Player) (*proto.AuthResponse, error) {
 	return c.client.Auth(ctx, player)
 }
 
-// List - send a list players request
-func (c *Client) List() ([]*proto.Player, error) {
+// List - send a list players request, returning one page of players and the
+// cursor for the next page, which is empty on the last page
+func (c *Client) List(req *proto.PlayerListRequest) ([]*proto.Player, string, error) {
 	ctx, cancel := c.ctx()
 	defer cancel()
-	src, err := c.client.List(ctx, &proto.Empty{})
+	src, err := c.client.List(ctx, req)
 	if err != nil {
 		log.Error("Error listing players", zap.Error(err))
-		return nil, err
+		return nil, "", err
 	}
 
 	res := make([]*proto.Player, 0)
+	for {
+		var msg proto.Player
+		if err := src.RecvMsg(&msg); err != nil {
+			if err == io.EOF {
+				return res, nextCursor(src.Trailer()), nil
+			}
+
+			log.Error("Error receiving player", zap.Error(err))
+			return nil, "", err
+		}
+
+		res = append(res, &msg)
+	}
+}
+
+// GetMany - request several players at once. Players which don't exist are
+// left out of the result.
+func (c *Client) GetMany(usernames []string) ([]*proto.Player, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	src, err := c.client.GetMany(ctx, &proto.Names{Names: usernames})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*proto.Player, 0, len(usernames))
 	for {
 		var msg proto.Player
 		if err := src.RecvMsg(&msg); err != nil {
@@ -103,27 +159,121 @@ func (c *Client) Travel(username, location string) (*proto.TravelResponse, error
 }
 
 // Move - send a move player request
-func (c *Client) Move(username string, x, y int32) (*proto.Position, error) {
+func (c *Client) Move(username string, x, y int32, limitSpeed bool) (*proto.Position, error) {
 	ctx, cancel := c.ctx()
 	defer cancel()
 	return c.client.Move(ctx, &proto.MoveRequest{
-		Username: username,
-		X:        x,
-		Y:        y,
+		Username:   username,
+		X:          x,
+		Y:          y,
+		LimitSpeed: limitSpeed,
 	})
 }
 
-// Delete - send a delete player request
-func (c *Client) Delete(username string) error {
+// Violations - list the moves of a player which broke the speed limit
+func (c *Client) Violations(username string) ([]*proto.Violation, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	src, err := c.client.Violations(ctx, &proto.Player{Username: username})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*proto.Violation, 0)
+	for {
+		var msg proto.Violation
+		if err := src.RecvMsg(&msg); err != nil {
+			if err == io.EOF {
+				return res, nil
+			}
+
+			log.Error("Error receiving violation", zap.Error(err))
+			return nil, err
+		}
+
+		res = append(res, &msg)
+	}
+}
+
+// Audit - send an audit log request, returning one page of entries and the
+// cursor for the next page, which is empty on the last page
+func (c *Client) Audit(query *proto.AuditQuery) ([]*proto.AuditEntry, string, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	src, err := c.client.Audit(ctx, query)
+	if err != nil {
+		log.Error("Error listing audit entries", zap.Error(err))
+		return nil, "", err
+	}
+
+	res := make([]*proto.AuditEntry, 0)
+	for {
+		var msg proto.AuditEntry
+		if err := src.RecvMsg(&msg); err != nil {
+			if err == io.EOF {
+				return res, nextCursor(src.Trailer()), nil
+			}
+
+			log.Error("Error receiving audit entry", zap.Error(err))
+			return nil, "", err
+		}
+
+		res = append(res, &msg)
+	}
+}
+
+// Delete - send a delete player request. If version is not 0, the player is
+// only deleted if they haven't changed since that version.
+func (c *Client) Delete(username string, version uint64) error {
 	ctx, cancel := c.ctx()
 	defer cancel()
 	_, err := c.client.Delete(ctx, &proto.Player{
 		Username: username,
+		Version:  version,
 	})
 
 	return err
 }
 
+// Restore - send a restore player request
+func (c *Client) Restore(username string) (*proto.Player, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	return c.client.Restore(ctx, &proto.Player{
+		Username: username,
+	})
+}
+
+// Snapshot - request every player account, without passwords
+func (c *Client) Snapshot() (*proto.PlayerSnapshot, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	return c.client.Snapshot(ctx, &proto.Empty{})
+}
+
+// RestoreSnapshot - send a request to bring back the accounts in a snapshot
+func (c *Client) RestoreSnapshot(snapshot *proto.PlayerSnapshot, clear bool) (*proto.RestoreReport, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	return c.client.RestoreSnapshot(ctx, &proto.PlayerSnapshotRestore{
+		Snapshot: snapshot,
+		Clear:    clear,
+	})
+}
+
 func (c *Client) ctx() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 10*time.Second)
+	ctx := context.Background()
+	if c.md != nil {
+		ctx = metadata.NewOutgoingContext(ctx, c.md)
+	}
+
+	return context.WithTimeout(ctx, 10*time.Second)
+}
+
+func nextCursor(trailer metadata.MD) string {
+	if next := trailer.Get("next"); len(next) > 0 {
+		return next[0]
+	}
+
+	return ""
 }
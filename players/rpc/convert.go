@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/proto"
+)
+
+// PlayerMessage - convert a player to its message, leaving out the password
+func PlayerMessage(player *data.Player) *proto.Player {
+	msg := &proto.Player{
+		Username: player.Username,
+		Version:  uint64(player.Version),
+	}
+
+	if player.Position != nil {
+		msg.Location = player.Position.Location
+		msg.X = int32(player.Position.X)
+		msg.Y = int32(player.Position.Y)
+	}
+
+	return msg
+}
+
+// PlayerData - convert a player message to the universal data format
+func PlayerData(msg *proto.Player) *data.Player {
+	player := &data.Player{
+		Username: msg.GetUsername(),
+		Version:  uint(msg.GetVersion()),
+	}
+
+	if len(msg.GetLocation()) > 0 {
+		player.Position = &data.Position{
+			Location: msg.GetLocation(),
+			X:        int(msg.GetX()),
+			Y:        int(msg.GetY()),
+		}
+	}
+
+	return player
+}
@@ -0,0 +1,368 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/audit"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/players"
+	"github.com/carsonmyers/bublar-assignment/players/rpc"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+var log = logger.GetLogger()
+
+// Server - RPC server for the player service
+type Server struct {
+	proto.UnimplementedPlayersServer
+}
+
+// Create - create a new player
+func (s *Server) Create(ctx context.Context, req *proto.Player) (*proto.Player, error) {
+	pw := req.GetPassword()
+	player := &data.Player{
+		Username: req.GetUsername(),
+		Password: &pw,
+	}
+
+	newPlayer, err := players.CreatePlayer(player)
+	if err != nil {
+		return nil, err
+	}
+
+	res := rpc.PlayerMessage(newPlayer)
+
+	audit.Record(ctx, "player.create", newPlayer.Username, nil, res)
+	return res, nil
+}
+
+// Get - retrieve a player's details
+func (s *Server) Get(ctx context.Context, req *proto.Player) (*proto.Player, error) {
+	player, err := players.GetPlayer(req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	p := rpc.PlayerMessage(player)
+
+	return p, nil
+}
+
+// GetMany - fetch several players at once, leaving out the ones which don't
+// exist
+func (s *Server) GetMany(req *proto.Names, srv proto.Players_GetManyServer) error {
+	res, err := players.GetPlayers(req.GetNames())
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Sending players", zap.Int("requested", len(req.GetNames())), zap.Int("players", len(res)))
+	for _, player := range res {
+		p := rpc.PlayerMessage(player)
+
+		if err := srv.Send(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Auth - authenticate a user via their password and return an auth token
+func (s *Server) Auth(ctx context.Context, req *proto.Player) (*proto.AuthResponse, error) {
+	token, err := players.AuthPlayer(req.GetUsername(), req.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	tokenStr, err := json.Marshal(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.AuthResponse{
+		Username: req.GetUsername(),
+		Token:    string(tokenStr),
+	}, nil
+}
+
+// List - stream one page of players in the system, sending the cursor for
+// the next page in the `next` trailer
+func (s *Server) List(req *proto.PlayerListRequest, srv proto.Players_ListServer) error {
+	page, err := paging.NewQuery(int(req.GetLimit()), req.GetAfter(), req.GetSort(), players.SortFields...)
+	if err != nil {
+		return err
+	}
+
+	filter := &players.ListFilter{
+		Prefix:   req.GetPrefix(),
+		Location: req.GetLocation(),
+		Online:   req.GetOnline(),
+	}
+
+	next, err := players.ListPlayers(page, filter, func(player *data.Player) error {
+		log.Debug("Serving player", zap.String("username", player.Username))
+
+		p := rpc.PlayerMessage(player)
+
+		if err := srv.Send(p); err != nil {
+			log.Error("Error sending player", zap.String("username", player.Username), zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Error("Error listing players", zap.Error(err))
+		return err
+	}
+
+	srv.SetTrailer(metadata.Pairs("next", next.Encode()))
+	return nil
+}
+
+// Update - change a player's username and/or password
+func (s *Server) Update(ctx context.Context, req *proto.PlayerUpdate) (*proto.Player, error) {
+	before, err := players.GetPlayer(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	player := &data.Player{
+		Username: req.GetPlayer().GetUsername(),
+		Version:  uint(req.GetPlayer().GetVersion()),
+	}
+
+	if pw := req.GetPlayer().GetPassword(); len(pw) > 0 {
+		player.Password = &pw
+	}
+
+	updated, err := players.UpdatePlayer(req.GetId(), player)
+	if err != nil {
+		return nil, err
+	}
+
+	res := rpc.PlayerMessage(updated)
+	audit.Record(ctx, "player.update", req.GetId(), rpc.PlayerMessage(before), res)
+	return res, nil
+}
+
+// Delete - delete a player
+func (s *Server) Delete(ctx context.Context, req *proto.Player) (*proto.Player, error) {
+	before, err := players.GetPlayer(req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := players.DeletePlayer(req.GetUsername(), uint(req.GetVersion())); err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, "player.delete", req.GetUsername(), rpc.PlayerMessage(before), nil)
+	return req, nil
+}
+
+// Restore - bring back a deleted player who has not been purged
+func (s *Server) Restore(ctx context.Context, req *proto.Player) (*proto.Player, error) {
+	player, err := players.RestorePlayer(req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	res := rpc.PlayerMessage(player)
+
+	audit.Record(ctx, "player.restore", player.Username, nil, res)
+	return res, nil
+}
+
+// Snapshot - capture every player account, without passwords
+func (s *Server) Snapshot(ctx context.Context, req *proto.Empty) (*proto.PlayerSnapshot, error) {
+	accounts, err := players.TakeSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proto.PlayerSnapshot{
+		Players: make([]*proto.Player, len(accounts)),
+	}
+
+	for i, account := range accounts {
+		res.Players[i] = rpc.PlayerMessage(account)
+	}
+
+	return res, nil
+}
+
+// RestoreSnapshot - bring back the player accounts in a snapshot
+func (s *Server) RestoreSnapshot(ctx context.Context, req *proto.PlayerSnapshotRestore) (*proto.RestoreReport, error) {
+	snapshot := req.GetSnapshot().GetPlayers()
+	accounts := make([]*data.Player, len(snapshot))
+	for i, p := range snapshot {
+		accounts[i] = &data.Player{
+			Username: p.GetUsername(),
+			Version:  uint(p.GetVersion()),
+		}
+	}
+
+	report, err := players.RestoreSnapshot(accounts, req.GetClear())
+	if err != nil {
+		return nil, err
+	}
+
+	res := restoreReport(report)
+	audit.Record(ctx, "player.snapshot.restore", "", nil, res)
+	return res, nil
+}
+
+// Travel - move a player to a new location
+func (s *Server) Travel(ctx context.Context, req *proto.TravelRequest) (*proto.TravelResponse, error) {
+	player, err := players.GetPlayer(req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	before := rpc.PlayerMessage(player)
+	position, err := players.Travel(player, req.GetLocation())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proto.TravelResponse{
+		Player: rpc.PlayerMessage(player),
+		Position: &proto.Position{
+			Location: position.Location,
+			X:        int32(position.X),
+			Y:        int32(position.Y),
+		},
+	}
+
+	audit.Record(ctx, "player.travel", player.Username, before, res.Player)
+	return res, nil
+}
+
+// Move - move a player within their current location
+func (s *Server) Move(ctx context.Context, req *proto.MoveRequest) (*proto.Position, error) {
+	player, err := players.GetPlayer(req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	before := rpc.PlayerMessage(player)
+	if err := players.Move(player, int(req.GetX()), int(req.GetY()), req.GetLimitSpeed()); err != nil {
+		return nil, err
+	}
+
+	// players moving themselves are too frequent and too ordinary to audit;
+	// their moves which break the speed limit are kept as violations instead
+	if !req.GetLimitSpeed() {
+		audit.Record(ctx, "player.move", player.Username, before, rpc.PlayerMessage(player))
+	}
+
+	return &proto.Position{
+		Location: player.Position.Location,
+		X:        int32(player.Position.X),
+		Y:        int32(player.Position.Y),
+	}, nil
+}
+
+// Violations - list the moves of a player which broke the speed limit
+func (s *Server) Violations(req *proto.Player, srv proto.Players_ViolationsServer) error {
+	violations, err := players.ListViolations(req.GetUsername())
+	if err != nil {
+		return err
+	}
+
+	for _, violation := range violations {
+		msg := &proto.Violation{
+			Id:       uint64(violation.ID),
+			Username: violation.Username,
+			Location: violation.Location,
+			FromX:    int32(violation.FromX),
+			FromY:    int32(violation.FromY),
+			ToX:      int32(violation.ToX),
+			ToY:      int32(violation.ToY),
+			Distance: violation.Distance,
+			Allowed:  violation.Allowed,
+			Clamped:  violation.Clamped,
+			Time:     violation.CreatedAt.Unix(),
+		}
+
+		if err := srv.Send(msg); err != nil {
+			log.Error("Error sending violation", zap.Uint("id", violation.ID), zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Audit - stream one page of the audit log, sending the cursor for the next
+// page in the `next` trailer
+func (s *Server) Audit(req *proto.AuditQuery, srv proto.Players_AuditServer) error {
+	page, err := paging.NewQuery(int(req.GetLimit()), req.GetAfter(), "", "id")
+	if err != nil {
+		return err
+	}
+
+	filter := &audit.Filter{
+		Actor:     req.GetActor(),
+		Action:    req.GetAction(),
+		Target:    req.GetTarget(),
+		RequestID: req.GetRequestId(),
+	}
+
+	if req.GetSince() != 0 {
+		filter.Since = time.Unix(req.GetSince(), 0)
+	}
+
+	if req.GetUntil() != 0 {
+		filter.Until = time.Unix(req.GetUntil(), 0)
+	}
+
+	entries, next, err := audit.List(filter, page)
+	if err != nil {
+		log.Error("Error listing audit entries", zap.Error(err))
+		return err
+	}
+
+	for _, entry := range entries {
+		msg := &proto.AuditEntry{
+			Id:        uint64(entry.ID),
+			Time:      entry.CreatedAt.Unix(),
+			Actor:     entry.Actor,
+			Source:    entry.Source,
+			Address:   entry.Address,
+			Action:    entry.Action,
+			Target:    entry.Target,
+			RequestId: entry.RequestID,
+			Before:    entry.Before,
+			After:     entry.After,
+		}
+
+		if err := srv.Send(msg); err != nil {
+			log.Error("Error sending audit entry", zap.Uint("id", entry.ID), zap.Error(err))
+			return err
+		}
+	}
+
+	srv.SetTrailer(metadata.Pairs("next", next.Encode()))
+	return nil
+}
+
+// restoreReport - convert a snapshot restore report to its message
+func restoreReport(report *data.RestoreReport) *proto.RestoreReport {
+	return &proto.RestoreReport{
+		Created:   int32(report.Created),
+		Updated:   int32(report.Updated),
+		Restored:  int32(report.Restored),
+		Deleted:   int32(report.Deleted),
+		Positions: int32(report.Positions),
+		Missing:   report.Missing,
+	}
+}
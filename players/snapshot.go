@@ -0,0 +1,104 @@
+package players
+
+import (
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"go.uber.org/zap"
+)
+
+// TakeSnapshot - capture every player account, without passwords
+func TakeSnapshot() ([]*data.Player, error) {
+	query := &ListQuery{
+		Query: paging.Query{
+			Limit: paging.MaxLimit,
+			Sort:  SortUsername,
+		},
+	}
+
+	accounts := make([]*data.Player, 0)
+	for {
+		page, err := GetStore().List(query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, player := range page {
+			accounts = append(accounts, &data.Player{
+				Username: player.Username,
+				Version:  player.Version,
+			})
+		}
+
+		if len(page) < query.Limit {
+			return accounts, nil
+		}
+
+		query.After = page[len(page)-1].Cursor(SortUsername)
+	}
+}
+
+// RestoreSnapshot - bring back the player accounts in a snapshot which were
+// deleted since. Snapshots have no passwords, so accounts which have been
+// purged cannot be recreated and are reported as missing. If clear is set,
+// accounts which are not in the snapshot are deleted.
+func RestoreSnapshot(accounts []*data.Player, clear bool) (*data.RestoreReport, error) {
+	store := GetStore()
+	report := &data.RestoreReport{}
+
+	if clear {
+		keep := make(map[string]bool, len(accounts))
+		for _, account := range accounts {
+			keep[account.Username] = true
+		}
+
+		current, err := TakeSnapshot()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, account := range current {
+			if keep[account.Username] {
+				continue
+			}
+
+			if err := DeletePlayer(account.Username, 0); err != nil {
+				return nil, err
+			}
+
+			report.Deleted++
+		}
+	}
+
+	for _, account := range accounts {
+		if _, err := store.Get(account.Username); err == nil {
+			continue
+		} else if !errors.IsKind(err, errors.ENotFound) {
+			return nil, err
+		}
+
+		exists, err := store.Exists(account.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			report.Missing = append(report.Missing, account.Username)
+			continue
+		}
+
+		if _, err := store.Restore(account.Username); err != nil {
+			return nil, err
+		}
+
+		report.Restored++
+	}
+
+	log.Info("Restored players snapshot",
+		zap.Bool("clear", clear),
+		zap.Int("restored", report.Restored),
+		zap.Int("deleted", report.Deleted),
+		zap.Int("missing", len(report.Missing)))
+
+	return report, nil
+}
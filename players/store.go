@@ -0,0 +1,116 @@
+package players
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/positions"
+)
+
+const (
+	// SortUsername - sort players by username
+	SortUsername = "username"
+
+	// SortCreated - sort players by when their account was created
+	SortCreated = "createdAt"
+)
+
+// SortFields - fields players can be sorted by, the first being the default
+var SortFields = []string{SortUsername, SortCreated}
+
+// ListQuery - which players to list, and in what order
+type ListQuery struct {
+	paging.Query
+
+	// Prefix - only list players whose username starts with this
+	Prefix string
+
+	// Usernames - only list these players, unless nil
+	Usernames []string
+}
+
+// Cursor - get the position of a player in a list sorted by a field
+func (p *Player) Cursor(sort string) *paging.Cursor {
+	switch sort {
+	case SortCreated:
+		return &paging.Cursor{Value: paging.TimeValue(p.CreatedAt), Key: p.Username}
+	default:
+		return &paging.Cursor{Value: p.Username, Key: p.Username}
+	}
+}
+
+// UpdateHook - called before an update is committed, with a position store
+// which takes part in the same transaction. If the hook fails, the update is
+// rolled back.
+type UpdateHook func(positions positions.Store) error
+
+// Store - storage for player accounts
+type Store interface {
+	// Exists - check whether a username is taken, including by a deleted
+	// player who has not been purged yet
+	Exists(username string) (bool, error)
+
+	// Create - store a new player account
+	Create(player *Player) error
+
+	// Get - get a player account by username
+	Get(username string) (*Player, error)
+
+	// List - list one page of player accounts
+	List(query *ListQuery) ([]*Player, error)
+
+	// Update - change a player's username and/or password, moving their
+	// violations to a new username along with them. If the player's version is
+	// set, the update fails unless it matches the stored version.
+	Update(username string, player *Player, hook UpdateHook) (*Player, error)
+
+	// Delete - delete a player account, keeping their position (if they have
+	// one) for when they are restored. If version is not 0, the deletion
+	// fails unless it matches the stored version.
+	Delete(username string, version uint, position *data.Position) error
+
+	// Restore - bring back a deleted player who has not been purged yet. The
+	// player returned has the position kept when they were deleted, which is
+	// cleared from the store.
+	Restore(username string) (*Player, error)
+
+	// Purge - permanently remove players deleted before a time, returning how
+	// many were removed
+	Purge(before time.Time) (int, error)
+
+	// AddViolation - record a move which broke the speed limit
+	AddViolation(violation *Violation) error
+
+	// Violations - list a player's violations, newest first
+	Violations(username string) ([]*Violation, error)
+}
+
+var store Store
+
+// SetStore - override the configured storage backend
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore - get the configured storage backend
+func GetStore() Store {
+	if store != nil {
+		return store
+	}
+
+	switch configure.GetStorage().Backend {
+	case configure.BackendMemory:
+		store = NewMemoryStore()
+	default:
+		store = NewGormStore()
+	}
+
+	return store
+}
+
+func versionMismatch(username string, expected uint) error {
+	return errors.EVersionMismatch.NewErrorf("player `%s` has changed since version %d", username, expected).WithDetail("expected", expected)
+}
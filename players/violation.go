@@ -0,0 +1,31 @@
+package players
+
+import (
+	"time"
+)
+
+// Violation - a move which was faster than the speed limit, kept for
+// moderation
+type Violation struct {
+	ID        uint      `json:"id" gorm:"primary_key"`
+	Username  string    `json:"username" gorm:"index"`
+	Location  string    `json:"location"`
+	FromX     int       `json:"fromX"`
+	FromY     int       `json:"fromY"`
+	ToX       int       `json:"toX"`
+	ToY       int       `json:"toY"`
+	Distance  float64   `json:"distance"`
+	Allowed   float64   `json:"allowed"`
+	Clamped   bool      `json:"clamped"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+}
+
+// ListViolations - list the moves of a player which broke the speed limit,
+// newest first
+func ListViolations(username string) ([]*Violation, error) {
+	if _, err := GetStore().Get(username); err != nil {
+		return nil, err
+	}
+
+	return GetStore().Violations(username)
+}
@@ -0,0 +1,299 @@
+package positions
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/jinzhu/gorm"
+	"go.uber.org/zap"
+)
+
+// Position - persisted player position, used when positions share the SQL database
+type Position struct {
+	Username  string `gorm:"primary_key"`
+	Location  string `gorm:"index"`
+	X         int
+	Y         int
+	UpdatedAt time.Time `gorm:"type:timestamp"`
+}
+
+// ToPosition - convert to universal data format
+func (p *Position) ToPosition() *data.Position {
+	return &data.Position{
+		Location: p.Location,
+		X:        p.X,
+		Y:        p.Y,
+	}
+}
+
+type gormStore struct {
+	tx *gorm.DB
+}
+
+// NewGormStore - create a position store backed by an SQL database
+func NewGormStore() Store {
+	return &gormStore{}
+}
+
+// Within - get a position store which takes part in an SQL transaction if
+// positions are kept in the same database, or the configured store if not
+func Within(tx *gorm.DB) Store {
+	if _, ok := GetStore().(*gormStore); ok {
+		return &gormStore{tx: tx}
+	}
+
+	return GetStore()
+}
+
+func (s *gormStore) db() (*gorm.DB, error) {
+	if s.tx != nil {
+		return s.tx, nil
+	}
+
+	return connect.Database()
+}
+
+func (s *gormStore) Get(username string) (*data.Position, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var pos Position
+	if err := db.Where(&Position{Username: username}).First(&pos).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			log.Debug("No position for user", zap.String("username", username))
+			return nil, nil
+		}
+
+		log.Error("Failed to get position for player", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return pos.ToPosition(), nil
+}
+
+func (s *gormStore) GetMany(usernames []string) (map[string]*data.Position, error) {
+	results := make(map[string]*data.Position, len(usernames))
+	if len(usernames) == 0 {
+		return results, nil
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var positions []*Position
+	if err := db.Where("username IN (?)", usernames).Find(&positions).Error; err != nil {
+		log.Error("Failed to get positions for players", zap.Int("players", len(usernames)), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	for _, pos := range positions {
+		results[pos.Username] = pos.ToPosition()
+	}
+
+	return results, nil
+}
+
+func (s *gormStore) Set(username string, position *data.Position) error {
+	db, err := s.db()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	err = db.Save(&Position{
+		Username:  username,
+		Location:  position.Location,
+		X:         position.X,
+		Y:         position.Y,
+		UpdatedAt: time.Now(),
+	}).Error
+	if err != nil {
+		log.Error("Failed to set position for player", zap.String("username", username), zap.String("location", position.Location), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) Moved(username string) (time.Time, error) {
+	db, err := s.db()
+	if err != nil {
+		return time.Time{}, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var pos Position
+	if err := db.Where(&Position{Username: username}).First(&pos).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return time.Time{}, nil
+		}
+
+		log.Error("Failed to get position for player", zap.String("username", username), zap.Error(err))
+		return time.Time{}, errors.EDatabase.NewError(err)
+	}
+
+	return pos.UpdatedAt, nil
+}
+
+func (s *gormStore) CompareAndSet(username string, from *data.Position, moved time.Time, to *data.Position) (bool, error) {
+	if s.tx != nil {
+		return compareAndSet(s.tx, username, from, moved, to)
+	}
+
+	db, err := connect.Database()
+	if err != nil {
+		return false, errors.EDatabaseConnection.NewError(err)
+	}
+
+	// sqlite shares a single connection, so nothing else reads or writes the
+	// position between the check and the update of the transaction
+	tx := db.Begin()
+	if err := tx.Error; err != nil {
+		log.Error("Failed to begin transaction", zap.String("username", username), zap.Error(err))
+		return false, errors.EDatabase.NewError(err)
+	}
+
+	set, err := compareAndSet(tx, username, from, moved, to)
+	if err != nil || !set {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Error("Failed to commit position", zap.String("username", username), zap.Error(err))
+		return false, errors.EDatabase.NewError(err)
+	}
+
+	return true, nil
+}
+
+func compareAndSet(tx *gorm.DB, username string, from *data.Position, moved time.Time, to *data.Position) (bool, error) {
+	var pos Position
+	if err := tx.Where(&Position{Username: username}).First(&pos).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return false, nil
+		}
+
+		log.Error("Failed to get position for player", zap.String("username", username), zap.Error(err))
+		return false, errors.EDatabase.NewError(err)
+	}
+
+	if *pos.ToPosition() != *from || !pos.UpdatedAt.Equal(moved) {
+		return false, nil
+	}
+
+	err := tx.Save(&Position{
+		Username:  username,
+		Location:  to.Location,
+		X:         to.X,
+		Y:         to.Y,
+		UpdatedAt: time.Now(),
+	}).Error
+	if err != nil {
+		log.Error("Failed to set position for player", zap.String("username", username), zap.String("location", to.Location), zap.Error(err))
+		return false, errors.EDatabase.NewError(err)
+	}
+
+	return true, nil
+}
+
+func (s *gormStore) Remove(username string) error {
+	db, err := s.db()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	if err := db.Delete(&Position{Username: username}).Error; err != nil {
+		log.Error("Error deleting location record for user", zap.String("username", username), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) Members(location string) ([]*data.Player, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var members []*Position
+	if err := db.Where(&Position{Location: location}).Order("username").Find(&members).Error; err != nil {
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	results := make([]*data.Player, len(members))
+	for i, member := range members {
+		results[i] = &data.Player{
+			Username: member.Username,
+			Position: member.ToPosition(),
+		}
+	}
+
+	return results, nil
+}
+
+func (s *gormStore) Rename(from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	q := db.Model(&Position{}).Where(&Position{Location: from}).Update("location", to)
+	if err := q.Error; err != nil {
+		log.Error("Error moving players to updated location", zap.String("location", from), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) RenamePlayer(from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	// update through the table rather than the model, otherwise gorm puts the
+	// new primary key into the WHERE clause and nothing matches. The column is
+	// updated directly so the move time is kept.
+	q := db.Table(db.NewScope(&Position{}).TableName()).Where(&Position{Username: from}).UpdateColumn("username", to)
+	if err := q.Error; err != nil {
+		log.Error("Error moving position to renamed player", zap.String("from", from), zap.String("to", to), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *gormStore) Clear(location string) ([]string, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var usernames []string
+	if err := db.Model(&Position{}).Where(&Position{Location: location}).Pluck("username", &usernames).Error; err != nil {
+		log.Error("Error fetching members in location", zap.String("name", location), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	if err := db.Where(&Position{Location: location}).Delete(&Position{}).Error; err != nil {
+		log.Error("Error deleting member list for location", zap.String("name", location), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return usernames, nil
+}
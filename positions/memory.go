@@ -0,0 +1,157 @@
+package positions
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+)
+
+type memoryStore struct {
+	mu        sync.RWMutex
+	positions map[string]data.Position
+	moved     map[string]time.Time
+}
+
+// NewMemoryStore - create a position store held in process memory
+func NewMemoryStore() Store {
+	return &memoryStore{
+		positions: make(map[string]data.Position),
+		moved:     make(map[string]time.Time),
+	}
+}
+
+func (s *memoryStore) Get(username string) (*data.Position, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pos, ok := s.positions[username]
+	if !ok {
+		return nil, nil
+	}
+
+	return &pos, nil
+}
+
+func (s *memoryStore) GetMany(usernames []string) (map[string]*data.Position, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make(map[string]*data.Position, len(usernames))
+	for _, username := range usernames {
+		if pos, ok := s.positions[username]; ok {
+			results[username] = &pos
+		}
+	}
+
+	return results, nil
+}
+
+func (s *memoryStore) Set(username string, position *data.Position) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.positions[username] = *position
+	s.moved[username] = time.Now()
+	return nil
+}
+
+func (s *memoryStore) Moved(username string) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.moved[username], nil
+}
+
+func (s *memoryStore) CompareAndSet(username string, from *data.Position, moved time.Time, to *data.Position) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pos, ok := s.positions[username]; !ok || pos != *from || !s.moved[username].Equal(moved) {
+		return false, nil
+	}
+
+	s.positions[username] = *to
+	s.moved[username] = time.Now()
+	return true, nil
+}
+
+func (s *memoryStore) Remove(username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.positions, username)
+	delete(s.moved, username)
+	return nil
+}
+
+func (s *memoryStore) Members(location string) ([]*data.Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make([]*data.Player, 0)
+	for username, pos := range s.positions {
+		if pos.Location != location {
+			continue
+		}
+
+		p := pos
+		results = append(results, &data.Player{
+			Username: username,
+			Position: &p,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Username < results[j].Username
+	})
+
+	return results, nil
+}
+
+func (s *memoryStore) Rename(from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for username, pos := range s.positions {
+		if pos.Location == from {
+			pos.Location = to
+			s.positions[username] = pos
+		}
+	}
+
+	return nil
+}
+
+func (s *memoryStore) RenamePlayer(from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pos, ok := s.positions[from]
+	if !ok || from == to {
+		return nil
+	}
+
+	s.positions[to] = pos
+	s.moved[to] = s.moved[from]
+	delete(s.positions, from)
+	delete(s.moved, from)
+	return nil
+}
+
+func (s *memoryStore) Clear(location string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usernames := make([]string, 0)
+	for username, pos := range s.positions {
+		if pos.Location == location {
+			delete(s.positions, username)
+			delete(s.moved, username)
+			usernames = append(usernames, username)
+		}
+	}
+
+	return usernames, nil
+}
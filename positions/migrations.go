@@ -0,0 +1,35 @@
+package positions
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/migrate"
+	"github.com/jinzhu/gorm"
+)
+
+// migrations - schema history for the position table, which only exists when
+// positions are kept in the SQL database
+var migrations = []*migrate.Migration{
+	{
+		Version: 1,
+		Name:    "create_position",
+		Up: func(tx *gorm.DB) error {
+			type position struct {
+				Username  string `gorm:"primary_key"`
+				Location  string `gorm:"index"`
+				X         int
+				Y         int
+				UpdatedAt time.Time `gorm:"type:timestamp"`
+			}
+
+			if tx.HasTable(&position{}) {
+				return nil
+			}
+
+			return tx.CreateTable(&position{}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.DropTableIfExists("position").Error
+		},
+	},
+}
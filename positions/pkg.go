@@ -0,0 +1,103 @@
+package positions
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	"github.com/carsonmyers/bublar-assignment/migrate"
+)
+
+var log = logger.GetLogger()
+
+// Store - storage for player positions and the set of players in each location
+type Store interface {
+	// Get - get a player's position, or nil if they are not in a location
+	Get(username string) (*data.Position, error)
+
+	// GetMany - get the positions of several players at once, keyed by
+	// username. Players who are not in a location are left out.
+	GetMany(usernames []string) (map[string]*data.Position, error)
+
+	// Set - store a player's position, moving them between locations if needed
+	Set(username string, position *data.Position) error
+
+	// Moved - when a player's position was last set, or the zero time if it
+	// isn't known
+	Moved(username string) (time.Time, error)
+
+	// CompareAndSet - store a player's position only if they are still at
+	// `from` and their position was last set at `moved`, as read from Get and
+	// Moved. Returns false, without changing anything, if the player has moved
+	// since.
+	CompareAndSet(username string, from *data.Position, moved time.Time, to *data.Position) (bool, error)
+
+	// Remove - remove a player's position and their location membership
+	Remove(username string) error
+
+	// Members - list the players present in a location
+	Members(location string) ([]*data.Player, error)
+
+	// Rename - move every player in a location to a new location name
+	Rename(from, to string) error
+
+	// RenamePlayer - move a player's position, move time and location
+	// membership to their new username, in one step
+	RenamePlayer(from, to string) error
+
+	// Clear - remove every player from a location, returning their usernames
+	Clear(location string) ([]string, error)
+}
+
+var store Store
+
+// SetStore - override the configured storage backend
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore - get the configured storage backend
+func GetStore() Store {
+	if store != nil {
+		return store
+	}
+
+	switch configure.GetStorage().Backend {
+	case configure.BackendMemory:
+		store = NewMemoryStore()
+	case configure.BackendSQLite:
+		store = NewGormStore()
+	default:
+		store = NewRedisStore()
+	}
+
+	return store
+}
+
+// Migrator - get the schema migrator for the position table, or nil if
+// positions are not kept in the SQL database
+func Migrator() (*migrate.Migrator, error) {
+	if _, ok := GetStore().(*gormStore); !ok {
+		return nil, nil
+	}
+
+	db, err := connect.Database()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	return migrate.New(db, "positions", migrations), nil
+}
+
+// Migrate - apply all pending migrations for the position table
+func Migrate() error {
+	m, err := Migrator()
+	if err != nil || m == nil {
+		return err
+	}
+
+	return m.Up()
+}
@@ -0,0 +1,540 @@
+package positions
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+const exp = 48 * time.Hour
+
+// batchSize - maximum number of keys fetched by a single MGET
+const batchSize = 500
+
+type redisStore struct{}
+
+// NewRedisStore - create a position store backed by redis
+func NewRedisStore() Store {
+	return &redisStore{}
+}
+
+func positionKey(username string) string {
+	return fmt.Sprintf("%s:position", username)
+}
+
+func movedKey(username string) string {
+	return fmt.Sprintf("%s:moved", username)
+}
+
+func locationKey(location string) string {
+	return fmt.Sprintf("location:%s", location)
+}
+
+func (s *redisStore) Get(username string) (*data.Position, error) {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	encoded, err := rdb.Get(positionKey(username)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			log.Debug("No position for user", zap.String("username", username))
+			return nil, nil
+		}
+
+		log.Error("Failed to get position for player", zap.String("username", username), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	pos := &data.Position{}
+	if err := pos.Decode(encoded); err != nil {
+		log.Error("Failed to decode player position", zap.String("username", username), zap.String("position", encoded), zap.Error(err))
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	return pos, nil
+}
+
+func (s *redisStore) GetMany(usernames []string) (map[string]*data.Position, error) {
+	results := make(map[string]*data.Position, len(usernames))
+	if len(usernames) == 0 {
+		return results, nil
+	}
+
+	rdb, err := connect.Redis()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	for start := 0; start < len(usernames); start += batchSize {
+		end := start + batchSize
+		if end > len(usernames) {
+			end = len(usernames)
+		}
+
+		batch := usernames[start:end]
+		keys := make([]string, len(batch))
+		for i, username := range batch {
+			keys[i] = positionKey(username)
+		}
+
+		values, err := rdb.MGet(keys...).Result()
+		if err != nil {
+			log.Error("Failed to get positions for players", zap.Int("players", len(batch)), zap.Error(err))
+			return nil, errors.EDatabase.NewError(err)
+		}
+
+		for i, value := range values {
+			encoded, ok := value.(string)
+			if !ok {
+				continue
+			}
+
+			pos := &data.Position{}
+			if err := pos.Decode(encoded); err != nil {
+				log.Error("Failed to decode player position", zap.String("username", batch[i]), zap.String("position", encoded), zap.Error(err))
+				return nil, errors.EDatabase.NewError(err)
+			}
+
+			results[batch[i]] = pos
+		}
+	}
+
+	return results, nil
+}
+
+func (s *redisStore) Set(username string, position *data.Position) error {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	previous, err := s.Get(username)
+	if err != nil {
+		return err
+	}
+
+	if previous != nil {
+		member := &data.Player{Username: username, Position: previous}
+		if _, err := rdb.SRem(locationKey(previous.Location), member.Encode()).Result(); err != nil {
+			log.Error("Failed to remove outdated record from location", zap.String("username", username), zap.String("location", previous.Location), zap.Error(err))
+			return errors.EDatabase.NewError(err)
+		}
+	}
+
+	log.Debug("Storing new position", zap.String("username", username), zap.String("data", position.Encode()))
+	if _, err := rdb.Set(positionKey(username), position.Encode(), exp).Result(); err != nil {
+		log.Error("Failed to set position for player", zap.String("username", username), zap.String("location", position.Location), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	member := &data.Player{Username: username, Position: position}
+	if _, err := rdb.SAdd(locationKey(position.Location), member.Encode()).Result(); err != nil {
+		log.Error("Failed to add player to location", zap.String("username", username), zap.String("location", position.Location), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	if _, err := rdb.Set(movedKey(username), time.Now().UnixNano(), exp).Result(); err != nil {
+		log.Error("Failed to set move time for player", zap.String("username", username), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *redisStore) Moved(username string) (time.Time, error) {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return time.Time{}, errors.EDatabaseConnection.NewError(err)
+	}
+
+	moved, err := rdb.Get(movedKey(username)).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+
+		log.Error("Failed to get move time for player", zap.String("username", username), zap.Error(err))
+		return time.Time{}, errors.EDatabase.NewError(err)
+	}
+
+	return time.Unix(0, moved), nil
+}
+
+// compareAndSetScript - move a player if their position and move time are
+// still what the caller read, keeping their location membership in step.
+// Scripts run atomically, so no other move can come between the check and the
+// update.
+//
+// KEYS[1]: position of the player
+// KEYS[2]: move time of the player
+// KEYS[3]: member set of the location the player is in
+// KEYS[4]: member set of the location the player moves to
+// ARGV[1]: encoded position the player must be at
+// ARGV[2]: move time the player must have, or empty if it must not be set
+// ARGV[3]: encoded position to move to
+// ARGV[4]: member to remove from the old location
+// ARGV[5]: member to add to the new location
+// ARGV[6]: new move time
+// ARGV[7]: expiry of position records, in seconds
+var compareAndSetScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+
+local moved = redis.call('GET', KEYS[2])
+if (moved or '') ~= ARGV[2] then
+	return 0
+end
+
+redis.call('SREM', KEYS[3], ARGV[4])
+redis.call('SET', KEYS[1], ARGV[3], 'EX', ARGV[7])
+redis.call('SADD', KEYS[4], ARGV[5])
+redis.call('SET', KEYS[2], ARGV[6], 'EX', ARGV[7])
+return 1
+`)
+
+func (s *redisStore) CompareAndSet(username string, from *data.Position, moved time.Time, to *data.Position) (bool, error) {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return false, errors.EDatabaseConnection.NewError(err)
+	}
+
+	var movedArg string
+	if !moved.IsZero() {
+		movedArg = strconv.FormatInt(moved.UnixNano(), 10)
+	}
+
+	keys := []string{positionKey(username), movedKey(username), locationKey(from.Location), locationKey(to.Location)}
+	previous := &data.Player{Username: username, Position: from}
+	member := &data.Player{Username: username, Position: to}
+
+	set, err := compareAndSetScript.Run(rdb, keys, from.Encode(), movedArg, to.Encode(), previous.Encode(), member.Encode(), time.Now().UnixNano(), int(exp.Seconds())).Int()
+	if err != nil {
+		log.Error("Failed to set position for player", zap.String("username", username), zap.String("location", to.Location), zap.Error(err))
+		return false, errors.EDatabase.NewError(err)
+	}
+
+	return set == 1, nil
+}
+
+func (s *redisStore) Remove(username string) error {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	pos, err := s.Get(username)
+	if err != nil || pos == nil {
+		return err
+	}
+
+	if _, err := rdb.Del(positionKey(username), movedKey(username)).Result(); err != nil {
+		log.Error("Error deleting location record for user", zap.String("username", username), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	member := &data.Player{Username: username, Position: pos}
+	if _, err := rdb.SRem(locationKey(pos.Location), member.Encode()).Result(); err != nil {
+		log.Error("Error removing user from location", zap.String("username", username), zap.String("location", pos.Location), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *redisStore) Members(location string) ([]*data.Player, error) {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	key := locationKey(location)
+	members, err := rdb.SMembers(key).Result()
+	if err != nil {
+		return nil, errors.EDatabase.NewError(err)
+	}
+
+	log.Debug("Queried players from location", zap.String("key", key), zap.Int("players", len(members)))
+
+	results := make([]*data.Player, len(members))
+	for i, member := range members {
+		results[i] = &data.Player{}
+		if err := results[i].Decode(member); err != nil {
+			return nil, errors.EDatabase.NewErrorf("failed to decode position `%s`", member).Wrap(err)
+		}
+	}
+
+	return results, nil
+}
+
+// changedMembers - a Lua prelude which returns -1 from a script unless the
+// member set in KEYS[1] holds exactly the members from ARGV[offset + 1] on, as
+// read by the caller before the script was run
+const changedMembers = `
+local offset = tonumber(ARGV[1])
+if redis.call('SCARD', KEYS[1]) ~= #ARGV - offset then
+	return -1
+end
+
+for i = offset + 1, #ARGV do
+	if redis.call('SISMEMBER', KEYS[1], ARGV[i]) == 0 then
+		return -1
+	end
+end
+`
+
+// renameScript - move every member of one location set into another, and
+// rewrite the position of each player to match. The members are read first so
+// every key the script touches can be passed in KEYS; if the set has changed
+// since, nothing is written and -1 is returned so the caller can read it again.
+// Scripts run atomically, so the rename either happens completely or not at
+// all.
+//
+// KEYS[1]: member set of the old location
+// KEYS[2]: member set of the new location
+// KEYS[3...]: position of each member, in the order of the members in ARGV
+// ARGV[1]: number of arguments before the members (4)
+// ARGV[2]: old location name
+// ARGV[3]: new location name
+// ARGV[4]: expiry of position records, in seconds
+// ARGV[5...]: members of the old location
+var renameScript = redis.NewScript(changedMembers + `
+for i = offset + 1, #ARGV do
+	local member = ARGV[i]
+	local username, x, y = string.match(member, '^([^:]*):.*:(-?%d+):(-?%d+)$')
+	if not username then
+		return redis.error_reply('invalid member encoding "' .. member .. '"')
+	end
+
+	local key = KEYS[i - offset + 2]
+	local coords = ':' .. x .. ':' .. y
+
+	-- stale members whose player has since moved elsewhere are dropped
+	if redis.call('GET', key) == ARGV[2] .. coords then
+		redis.call('SET', key, ARGV[3] .. coords, 'EX', ARGV[4])
+		redis.call('SADD', KEYS[2], username .. ':' .. ARGV[3] .. coords)
+	end
+end
+
+redis.call('DEL', KEYS[1])
+return #ARGV - offset
+`)
+
+// clearScript - remove every member of a location set, along with the position
+// and move time of each player who is still in the location. As with
+// renameScript, the members are read first, and -1 is returned without writing
+// anything if the set has changed since. Returns the usernames of the players
+// who were removed.
+//
+// KEYS[1]: member set of the location
+// KEYS[2...]: position and move time of each member, in pairs, in the order of
+// the members in ARGV
+// ARGV[1]: number of arguments before the members (2)
+// ARGV[2]: location name
+// ARGV[3...]: members of the location
+var clearScript = redis.NewScript(changedMembers + `
+local removed = {}
+for i = offset + 1, #ARGV do
+	local member = ARGV[i]
+	local username, x, y = string.match(member, '^([^:]*):.*:(-?%d+):(-?%d+)$')
+	if not username then
+		return redis.error_reply('invalid member encoding "' .. member .. '"')
+	end
+
+	local key = KEYS[(i - offset) * 2]
+
+	-- stale members whose player has since moved elsewhere are dropped
+	-- without touching the player
+	if redis.call('GET', key) == ARGV[2] .. ':' .. x .. ':' .. y then
+		redis.call('DEL', key, KEYS[(i - offset) * 2 + 1])
+		table.insert(removed, username)
+	end
+end
+
+redis.call('DEL', KEYS[1])
+return removed
+`)
+
+// maxMemberAttempts - how many times the members of a location are read again
+// when they change while the location is being renamed or cleared
+const maxMemberAttempts = 3
+
+// members - read the members of a location set, along with the usernames they
+// belong to
+func (s *redisStore) members(rdb *redis.Client, location string) ([]string, []string, error) {
+	members, err := rdb.SMembers(locationKey(location)).Result()
+	if err != nil {
+		log.Error("Error fetching members in location", zap.String("name", location), zap.Error(err))
+		return nil, nil, errors.EDatabase.NewError(err)
+	}
+
+	usernames := make([]string, len(members))
+	for i, member := range members {
+		p := &data.Player{}
+		if err := p.Decode(member); err != nil {
+			log.Error("Error decoding member of location", zap.String("name", location), zap.String("data", member), zap.Error(err))
+			return nil, nil, errors.EDatabase.NewErrorf("failed to decode position `%s`", member).Wrap(err)
+		}
+
+		usernames[i] = p.Username
+	}
+
+	return members, usernames, nil
+}
+
+func (s *redisStore) Rename(from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	rdb, err := connect.Redis()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	for attempt := 0; attempt < maxMemberAttempts; attempt++ {
+		members, usernames, err := s.members(rdb, from)
+		if err != nil {
+			return err
+		}
+
+		keys := []string{locationKey(from), locationKey(to)}
+		args := []interface{}{4, from, to, int(exp.Seconds())}
+		for i, member := range members {
+			keys = append(keys, positionKey(usernames[i]))
+			args = append(args, member)
+		}
+
+		moved, err := renameScript.Run(rdb, keys, args...).Int()
+		if err != nil {
+			log.Error("Error moving players to renamed location", zap.String("from", from), zap.String("to", to), zap.Error(err))
+			return errors.EDatabase.NewError(err)
+		}
+
+		if moved >= 0 {
+			log.Debug("Moved players to renamed location", zap.String("from", from), zap.String("to", to), zap.Int("players", moved))
+			return nil
+		}
+	}
+
+	return errors.EDatabase.NewErrorf("players kept moving in or out of location `%s` while renaming it", from)
+}
+
+// renamePlayerScript - move a player's position and move time to their new
+// username, and replace their member of the location set, if their position is
+// still what the caller read
+//
+// KEYS[1]: position of the old username
+// KEYS[2]: move time of the old username
+// KEYS[3]: position of the new username
+// KEYS[4]: move time of the new username
+// KEYS[5]: member set of the location the player is in
+// ARGV[1]: encoded position the player must be at
+// ARGV[2]: member of the old username
+// ARGV[3]: member of the new username
+// ARGV[4]: expiry of position records, in seconds
+var renamePlayerScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+
+local moved = redis.call('GET', KEYS[2])
+redis.call('SREM', KEYS[5], ARGV[2])
+redis.call('SET', KEYS[3], ARGV[1], 'EX', ARGV[4])
+redis.call('SADD', KEYS[5], ARGV[3])
+if moved then
+	redis.call('SET', KEYS[4], moved, 'EX', ARGV[4])
+else
+	redis.call('DEL', KEYS[4])
+end
+
+redis.call('DEL', KEYS[1], KEYS[2])
+return 1
+`)
+
+// maxRenameAttempts - how many times a renamed player's position is read
+// again when they move while it is being renamed
+const maxRenameAttempts = 3
+
+func (s *redisStore) RenamePlayer(from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	rdb, err := connect.Redis()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	for attempt := 0; attempt < maxRenameAttempts; attempt++ {
+		pos, err := s.Get(from)
+		if err != nil || pos == nil {
+			return err
+		}
+
+		keys := []string{positionKey(from), movedKey(from), positionKey(to), movedKey(to), locationKey(pos.Location)}
+		previous := &data.Player{Username: from, Position: pos}
+		member := &data.Player{Username: to, Position: pos}
+
+		renamed, err := renamePlayerScript.Run(rdb, keys, pos.Encode(), previous.Encode(), member.Encode(), int(exp.Seconds())).Int()
+		if err != nil {
+			log.Error("Error moving position to renamed player", zap.String("from", from), zap.String("to", to), zap.Error(err))
+			return errors.EDatabase.NewError(err)
+		}
+
+		if renamed == 1 {
+			return nil
+		}
+	}
+
+	return errors.EDatabase.NewErrorf("player `%s` kept moving while being renamed", from)
+}
+
+func (s *redisStore) Clear(location string) ([]string, error) {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return nil, errors.EDatabaseConnection.NewError(err)
+	}
+
+	for attempt := 0; attempt < maxMemberAttempts; attempt++ {
+		members, usernames, err := s.members(rdb, location)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := []string{locationKey(location)}
+		args := []interface{}{2, location}
+		for i, member := range members {
+			keys = append(keys, positionKey(usernames[i]), movedKey(usernames[i]))
+			args = append(args, member)
+		}
+
+		res, err := clearScript.Run(rdb, keys, args...).Result()
+		if err != nil {
+			log.Error("Error clearing players from location", zap.String("name", location), zap.Error(err))
+			return nil, errors.EDatabase.NewError(err)
+		}
+
+		removed, ok := res.([]interface{})
+		if !ok {
+			continue
+		}
+
+		cleared := make([]string, len(removed))
+		for i, username := range removed {
+			cleared[i], _ = username.(string)
+		}
+
+		return cleared, nil
+	}
+
+	return nil, errors.EDatabase.NewErrorf("players kept moving in or out of location `%s` while clearing it", location)
+}
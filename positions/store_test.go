@@ -0,0 +1,335 @@
+package positions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/data"
+)
+
+// useMiniredis - point the redis connection at an in-process server for the
+// length of a test
+func useMiniredis(t testing.TB) {
+	srv, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("starting miniredis: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	configure.Redis(&configure.RedisConfig{
+		Host: srv.Host(),
+		Port: uint(srv.Server().Addr().Port),
+	})
+}
+
+// stores - the stores which can be tested without a database
+var stores = []struct {
+	name  string
+	store func(t *testing.T) Store
+}{
+	{"memory", func(t *testing.T) Store { return NewMemoryStore() }},
+	{"redis", func(t *testing.T) Store {
+		useMiniredis(t)
+		return NewRedisStore()
+	}},
+}
+
+func TestGetMany(t *testing.T) {
+	positions := map[string]*data.Position{
+		"alice": {Location: "town", X: 1, Y: 2},
+		"carol": {Location: "field", X: -3, Y: 4},
+	}
+
+	cases := []struct {
+		name      string
+		usernames []string
+		want      []string
+	}{
+		{"none", []string{}, []string{}},
+		{"all placed", []string{"alice", "carol"}, []string{"alice", "carol"}},
+		{"none placed", []string{"bob", "dave"}, []string{}},
+		{"some placed", []string{"alice", "bob", "carol", "dave"}, []string{"alice", "carol"}},
+		{"repeated", []string{"alice", "alice", "bob"}, []string{"alice"}},
+	}
+
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.store(t)
+			for username, pos := range positions {
+				if err := store.Set(username, pos); err != nil {
+					t.Fatalf("setting position of %s: %v", username, err)
+				}
+			}
+
+			for _, c := range cases {
+				t.Run(c.name, func(t *testing.T) {
+					found, err := store.GetMany(c.usernames)
+					if err != nil {
+						t.Fatalf("GetMany: %v", err)
+					}
+
+					if len(found) != len(c.want) {
+						t.Errorf("got %d positions, want %d: %v", len(found), len(c.want), found)
+					}
+
+					for _, username := range c.want {
+						pos, ok := found[username]
+						if !ok {
+							t.Errorf("missing position of %s", username)
+							continue
+						}
+
+						if *pos != *positions[username] {
+							t.Errorf("position of %s is %v, want %v", username, *pos, *positions[username])
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestCompareAndSet(t *testing.T) {
+	start := &data.Position{Location: "town", X: 1, Y: 2}
+	step := &data.Position{Location: "town", X: 2, Y: 2}
+
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.store(t)
+
+			if set, err := store.CompareAndSet("alice", start, time.Time{}, step); err != nil || set {
+				t.Fatalf("moving a player without a position: got %v, %v", set, err)
+			}
+
+			if err := store.Set("alice", start); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+
+			moved, err := store.Moved("alice")
+			if err != nil {
+				t.Fatalf("Moved: %v", err)
+			}
+
+			elsewhere := &data.Position{Location: "town", X: 5, Y: 5}
+			if set, err := store.CompareAndSet("alice", elsewhere, moved, step); err != nil || set {
+				t.Errorf("moving from the wrong position: got %v, %v", set, err)
+			}
+
+			if set, err := store.CompareAndSet("alice", start, moved.Add(-time.Second), step); err != nil || set {
+				t.Errorf("moving with the wrong move time: got %v, %v", set, err)
+			}
+
+			if set, err := store.CompareAndSet("alice", start, moved, step); err != nil || !set {
+				t.Fatalf("moving: got %v, %v", set, err)
+			}
+
+			// the first move changed the move time, so a second one made from
+			// the same reading fails
+			if set, err := store.CompareAndSet("alice", start, moved, elsewhere); err != nil || set {
+				t.Errorf("moving twice from the same reading: got %v, %v", set, err)
+			}
+
+			pos, err := store.Get("alice")
+			if err != nil || pos == nil || *pos != *step {
+				t.Errorf("position is %v (%v), want %v", pos, err, *step)
+			}
+
+			members, err := store.Members("town")
+			if err != nil {
+				t.Fatalf("Members: %v", err)
+			}
+
+			if len(members) != 1 || *members[0].Position != *step {
+				t.Errorf("members of town are %v, want only alice at %v", members, *step)
+			}
+		})
+	}
+}
+
+func TestRenamePlayer(t *testing.T) {
+	start := &data.Position{Location: "town", X: 1, Y: 2}
+
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.store(t)
+
+			if err := store.RenamePlayer("bob", "robert"); err != nil {
+				t.Fatalf("renaming a player without a position: %v", err)
+			}
+
+			if pos, err := store.Get("robert"); err != nil || pos != nil {
+				t.Errorf("player renamed without a position is at %v (%v), want nowhere", pos, err)
+			}
+
+			if err := store.Set("alice", start); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+
+			moved, err := store.Moved("alice")
+			if err != nil {
+				t.Fatalf("Moved: %v", err)
+			}
+
+			if err := store.RenamePlayer("alice", "alicia"); err != nil {
+				t.Fatalf("RenamePlayer: %v", err)
+			}
+
+			if pos, err := store.Get("alice"); err != nil || pos != nil {
+				t.Errorf("old username is at %v (%v), want nowhere", pos, err)
+			}
+
+			pos, err := store.Get("alicia")
+			if err != nil || pos == nil || *pos != *start {
+				t.Errorf("new username is at %v (%v), want %v", pos, err, *start)
+			}
+
+			// the move time is kept, so renaming doesn't reset the speed limit
+			if renamed, err := store.Moved("alicia"); err != nil || !renamed.Equal(moved) {
+				t.Errorf("new username moved at %v (%v), want %v", renamed, err, moved)
+			}
+
+			members, err := store.Members("town")
+			if err != nil {
+				t.Fatalf("Members: %v", err)
+			}
+
+			if len(members) != 1 || members[0].Username != "alicia" {
+				t.Errorf("members of town are %v, want only alicia", members)
+			}
+		})
+	}
+}
+
+// placeAll - store the position of each player, failing the test if any can't
+// be stored
+func placeAll(t *testing.T, store Store, positions map[string]*data.Position) {
+	for username, pos := range positions {
+		if err := store.Set(username, pos); err != nil {
+			t.Fatalf("setting position of %s: %v", username, err)
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.store(t)
+			placeAll(t, store, map[string]*data.Position{
+				"alice": {Location: "town", X: 1, Y: 2},
+				"bob":   {Location: "town", X: -3, Y: 4},
+				"carol": {Location: "field", X: 5, Y: 6},
+			})
+
+			if err := store.Rename("town", "city"); err != nil {
+				t.Fatalf("Rename: %v", err)
+			}
+
+			cases := []struct {
+				username string
+				want     data.Position
+			}{
+				{"alice", data.Position{Location: "city", X: 1, Y: 2}},
+				{"bob", data.Position{Location: "city", X: -3, Y: 4}},
+				{"carol", data.Position{Location: "field", X: 5, Y: 6}},
+			}
+
+			for _, c := range cases {
+				pos, err := store.Get(c.username)
+				if err != nil || pos == nil || *pos != c.want {
+					t.Errorf("%s is at %v (%v), want %v", c.username, pos, err, c.want)
+				}
+			}
+
+			if members, err := store.Members("town"); err != nil || len(members) != 0 {
+				t.Errorf("members of town are %v (%v), want none", members, err)
+			}
+
+			if members, err := store.Members("city"); err != nil || len(members) != 2 {
+				t.Errorf("members of city are %v (%v), want alice and bob", members, err)
+			}
+		})
+	}
+}
+
+func TestClear(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.store(t)
+			placeAll(t, store, map[string]*data.Position{
+				"alice": {Location: "town", X: 1, Y: 2},
+				"bob":   {Location: "town", X: -3, Y: 4},
+				"carol": {Location: "field", X: 5, Y: 6},
+			})
+
+			cleared, err := store.Clear("town")
+			if err != nil {
+				t.Fatalf("Clear: %v", err)
+			}
+
+			if len(cleared) != 2 {
+				t.Errorf("cleared %v, want alice and bob", cleared)
+			}
+
+			for _, username := range []string{"alice", "bob"} {
+				if pos, err := store.Get(username); err != nil || pos != nil {
+					t.Errorf("%s is at %v (%v), want nowhere", username, pos, err)
+				}
+			}
+
+			if pos, err := store.Get("carol"); err != nil || pos == nil || pos.Location != "field" {
+				t.Errorf("carol is at %v (%v), want field", pos, err)
+			}
+		})
+	}
+}
+
+// TestStaleMembers - a member left in a location set after its player moved
+// elsewhere doesn't move or remove the player when the location is renamed or
+// cleared
+func TestStaleMembers(t *testing.T) {
+	useMiniredis(t)
+	store := NewRedisStore()
+	placeAll(t, store, map[string]*data.Position{
+		"alice": {Location: "town", X: 1, Y: 2},
+		"bob":   {Location: "field", X: 3, Y: 4},
+	})
+
+	rdb, err := connect.Redis()
+	if err != nil {
+		t.Fatalf("connecting to redis: %v", err)
+	}
+
+	stale := &data.Player{Username: "bob", Position: &data.Position{Location: "town", X: 3, Y: 4}}
+	if err := rdb.SAdd(locationKey("town"), stale.Encode()).Err(); err != nil {
+		t.Fatalf("adding stale member: %v", err)
+	}
+
+	if err := store.Rename("town", "city"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	if pos, err := store.Get("bob"); err != nil || pos == nil || pos.Location != "field" {
+		t.Errorf("after rename, bob is at %v (%v), want field", pos, err)
+	}
+
+	stale.Position.Location = "city"
+	if err := rdb.SAdd(locationKey("city"), stale.Encode()).Err(); err != nil {
+		t.Fatalf("adding stale member: %v", err)
+	}
+
+	cleared, err := store.Clear("city")
+	if err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	if len(cleared) != 1 || cleared[0] != "alice" {
+		t.Errorf("cleared %v, want only alice", cleared)
+	}
+
+	if pos, err := store.Get("bob"); err != nil || pos == nil || pos.Location != "field" {
+		t.Errorf("after clear, bob is at %v (%v), want field", pos, err)
+	}
+}
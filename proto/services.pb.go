@@ -7,6 +7,8 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	any "github.com/golang/protobuf/ptypes/any"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -25,11 +27,14 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Player struct {
-	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	Location             string   `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
-	X                    int32    `protobuf:"varint,4,opt,name=x,proto3" json:"x,omitempty"`
-	Y                    int32    `protobuf:"varint,5,opt,name=y,proto3" json:"y,omitempty"`
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Location string `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	X        int32  `protobuf:"varint,4,opt,name=x,proto3" json:"x,omitempty"`
+	Y        int32  `protobuf:"varint,5,opt,name=y,proto3" json:"y,omitempty"`
+	// incremented on every change; set on updates and deletions to make them
+	// conditional on the player not having changed since
+	Version              uint64   `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -95,6 +100,13 @@ func (m *Player) GetY() int32 {
 	return 0
 }
 
+func (m *Player) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 type PlayerUpdate struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Player               *Player  `protobuf:"bytes,2,opt,name=player,proto3" json:"player,omitempty"`
@@ -143,9 +155,17 @@ func (m *PlayerUpdate) GetPlayer() *Player {
 }
 
 type Location struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	X                    int32    `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y                    int32    `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	X    int32  `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y    int32  `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	// incremented on every change; set on updates and deletions to make them
+	// conditional on the location not having changed since
+	Version uint64 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	// size in tiles, which players can't move outside of; 0 is unbounded
+	Width  int32 `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`
+	Height int32 `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	// tiles within the location which players can't move onto
+	Blocked              []*Tile  `protobuf:"bytes,7,rep,name=blocked,proto3" json:"blocked,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -197,6 +217,81 @@ func (m *Location) GetY() int32 {
 	return 0
 }
 
+func (m *Location) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Location) GetWidth() int32 {
+	if m != nil {
+		return m.Width
+	}
+	return 0
+}
+
+func (m *Location) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Location) GetBlocked() []*Tile {
+	if m != nil {
+		return m.Blocked
+	}
+	return nil
+}
+
+type Tile struct {
+	X                    int32    `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    int32    `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Tile) Reset()         { *m = Tile{} }
+func (m *Tile) String() string { return proto.CompactTextString(m) }
+func (*Tile) ProtoMessage()    {}
+func (*Tile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{3}
+}
+
+func (m *Tile) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Tile.Unmarshal(m, b)
+}
+func (m *Tile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Tile.Marshal(b, m, deterministic)
+}
+func (m *Tile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Tile.Merge(m, src)
+}
+func (m *Tile) XXX_Size() int {
+	return xxx_messageInfo_Tile.Size(m)
+}
+func (m *Tile) XXX_DiscardUnknown() {
+	xxx_messageInfo_Tile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Tile proto.InternalMessageInfo
+
+func (m *Tile) GetX() int32 {
+	if m != nil {
+		return m.X
+	}
+	return 0
+}
+
+func (m *Tile) GetY() int32 {
+	if m != nil {
+		return m.Y
+	}
+	return 0
+}
+
 type LocationUpdate struct {
 	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Location             *Location `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
@@ -209,7 +304,7 @@ func (m *LocationUpdate) Reset()         { *m = LocationUpdate{} }
 func (m *LocationUpdate) String() string { return proto.CompactTextString(m) }
 func (*LocationUpdate) ProtoMessage()    {}
 func (*LocationUpdate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2d444674d051dbb, []int{3}
+	return fileDescriptor_c2d444674d051dbb, []int{4}
 }
 
 func (m *LocationUpdate) XXX_Unmarshal(b []byte) error {
@@ -244,6 +339,149 @@ func (m *LocationUpdate) GetLocation() *Location {
 	return nil
 }
 
+// the cursor for the next page of a list is sent in the `next` trailer
+type PlayerListRequest struct {
+	Limit                int32    `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	After                string   `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	Sort                 string   `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	Prefix               string   `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Location             string   `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	Online               bool     `protobuf:"varint,6,opt,name=online,proto3" json:"online,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlayerListRequest) Reset()         { *m = PlayerListRequest{} }
+func (m *PlayerListRequest) String() string { return proto.CompactTextString(m) }
+func (*PlayerListRequest) ProtoMessage()    {}
+func (*PlayerListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{5}
+}
+
+func (m *PlayerListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlayerListRequest.Unmarshal(m, b)
+}
+func (m *PlayerListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlayerListRequest.Marshal(b, m, deterministic)
+}
+func (m *PlayerListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlayerListRequest.Merge(m, src)
+}
+func (m *PlayerListRequest) XXX_Size() int {
+	return xxx_messageInfo_PlayerListRequest.Size(m)
+}
+func (m *PlayerListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlayerListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlayerListRequest proto.InternalMessageInfo
+
+func (m *PlayerListRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *PlayerListRequest) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *PlayerListRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+func (m *PlayerListRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *PlayerListRequest) GetLocation() string {
+	if m != nil {
+		return m.Location
+	}
+	return ""
+}
+
+func (m *PlayerListRequest) GetOnline() bool {
+	if m != nil {
+		return m.Online
+	}
+	return false
+}
+
+type LocationListRequest struct {
+	Limit                int32    `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	After                string   `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	Sort                 string   `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	Prefix               string   `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LocationListRequest) Reset()         { *m = LocationListRequest{} }
+func (m *LocationListRequest) String() string { return proto.CompactTextString(m) }
+func (*LocationListRequest) ProtoMessage()    {}
+func (*LocationListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{6}
+}
+
+func (m *LocationListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LocationListRequest.Unmarshal(m, b)
+}
+func (m *LocationListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LocationListRequest.Marshal(b, m, deterministic)
+}
+func (m *LocationListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocationListRequest.Merge(m, src)
+}
+func (m *LocationListRequest) XXX_Size() int {
+	return xxx_messageInfo_LocationListRequest.Size(m)
+}
+func (m *LocationListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocationListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LocationListRequest proto.InternalMessageInfo
+
+func (m *LocationListRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *LocationListRequest) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *LocationListRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+func (m *LocationListRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
 type Position struct {
 	Location             string   `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
 	X                    int32    `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
@@ -257,7 +495,7 @@ func (m *Position) Reset()         { *m = Position{} }
 func (m *Position) String() string { return proto.CompactTextString(m) }
 func (*Position) ProtoMessage()    {}
 func (*Position) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2d444674d051dbb, []int{4}
+	return fileDescriptor_c2d444674d051dbb, []int{7}
 }
 
 func (m *Position) XXX_Unmarshal(b []byte) error {
@@ -311,7 +549,7 @@ func (m *AuthResponse) Reset()         { *m = AuthResponse{} }
 func (m *AuthResponse) String() string { return proto.CompactTextString(m) }
 func (*AuthResponse) ProtoMessage()    {}
 func (*AuthResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2d444674d051dbb, []int{5}
+	return fileDescriptor_c2d444674d051dbb, []int{8}
 }
 
 func (m *AuthResponse) XXX_Unmarshal(b []byte) error {
@@ -358,7 +596,7 @@ func (m *TravelRequest) Reset()         { *m = TravelRequest{} }
 func (m *TravelRequest) String() string { return proto.CompactTextString(m) }
 func (*TravelRequest) ProtoMessage()    {}
 func (*TravelRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2d444674d051dbb, []int{6}
+	return fileDescriptor_c2d444674d051dbb, []int{9}
 }
 
 func (m *TravelRequest) XXX_Unmarshal(b []byte) error {
@@ -405,7 +643,7 @@ func (m *TravelResponse) Reset()         { *m = TravelResponse{} }
 func (m *TravelResponse) String() string { return proto.CompactTextString(m) }
 func (*TravelResponse) ProtoMessage()    {}
 func (*TravelResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2d444674d051dbb, []int{7}
+	return fileDescriptor_c2d444674d051dbb, []int{10}
 }
 
 func (m *TravelResponse) XXX_Unmarshal(b []byte) error {
@@ -441,9 +679,12 @@ func (m *TravelResponse) GetPosition() *Position {
 }
 
 type MoveRequest struct {
-	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	X                    int32    `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y                    int32    `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	X        int32  `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y        int32  `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	// set when players move themselves, so the move is held to the speed
+	// limit; admins can move players anywhere
+	LimitSpeed           bool     `protobuf:"varint,4,opt,name=limit_speed,json=limitSpeed,proto3" json:"limit_speed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -453,7 +694,7 @@ func (m *MoveRequest) Reset()         { *m = MoveRequest{} }
 func (m *MoveRequest) String() string { return proto.CompactTextString(m) }
 func (*MoveRequest) ProtoMessage()    {}
 func (*MoveRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2d444674d051dbb, []int{8}
+	return fileDescriptor_c2d444674d051dbb, []int{11}
 }
 
 func (m *MoveRequest) XXX_Unmarshal(b []byte) error {
@@ -495,157 +736,1620 @@ func (m *MoveRequest) GetY() int32 {
 	return 0
 }
 
-type Empty struct {
+func (m *MoveRequest) GetLimitSpeed() bool {
+	if m != nil {
+		return m.LimitSpeed
+	}
+	return false
+}
+
+// a move which was faster than the speed limit
+type Violation struct {
+	Id       uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Location string `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	FromX    int32  `protobuf:"varint,4,opt,name=from_x,json=fromX,proto3" json:"from_x,omitempty"`
+	FromY    int32  `protobuf:"varint,5,opt,name=from_y,json=fromY,proto3" json:"from_y,omitempty"`
+	ToX      int32  `protobuf:"varint,6,opt,name=to_x,json=toX,proto3" json:"to_x,omitempty"`
+	ToY      int32  `protobuf:"varint,7,opt,name=to_y,json=toY,proto3" json:"to_y,omitempty"`
+	// how far the player tried to move, and how far they could have
+	Distance float64 `protobuf:"fixed64,8,opt,name=distance,proto3" json:"distance,omitempty"`
+	Allowed  float64 `protobuf:"fixed64,9,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// whether the move was shortened, rather than rejected
+	Clamped              bool     `protobuf:"varint,10,opt,name=clamped,proto3" json:"clamped,omitempty"`
+	Time                 int64    `protobuf:"varint,11,opt,name=time,proto3" json:"time,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Empty) Reset()         { *m = Empty{} }
-func (m *Empty) String() string { return proto.CompactTextString(m) }
-func (*Empty) ProtoMessage()    {}
-func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c2d444674d051dbb, []int{9}
+func (m *Violation) Reset()         { *m = Violation{} }
+func (m *Violation) String() string { return proto.CompactTextString(m) }
+func (*Violation) ProtoMessage()    {}
+func (*Violation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{12}
 }
 
-func (m *Empty) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Empty.Unmarshal(m, b)
+func (m *Violation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Violation.Unmarshal(m, b)
 }
-func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+func (m *Violation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Violation.Marshal(b, m, deterministic)
 }
-func (m *Empty) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Empty.Merge(m, src)
+func (m *Violation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Violation.Merge(m, src)
 }
-func (m *Empty) XXX_Size() int {
-	return xxx_messageInfo_Empty.Size(m)
+func (m *Violation) XXX_Size() int {
+	return xxx_messageInfo_Violation.Size(m)
 }
-func (m *Empty) XXX_DiscardUnknown() {
-	xxx_messageInfo_Empty.DiscardUnknown(m)
+func (m *Violation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Violation.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Empty proto.InternalMessageInfo
+var xxx_messageInfo_Violation proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*Player)(nil), "proto.Player")
-	proto.RegisterType((*PlayerUpdate)(nil), "proto.PlayerUpdate")
-	proto.RegisterType((*Location)(nil), "proto.Location")
-	proto.RegisterType((*LocationUpdate)(nil), "proto.LocationUpdate")
-	proto.RegisterType((*Position)(nil), "proto.Position")
-	proto.RegisterType((*AuthResponse)(nil), "proto.AuthResponse")
-	proto.RegisterType((*TravelRequest)(nil), "proto.TravelRequest")
-	proto.RegisterType((*TravelResponse)(nil), "proto.TravelResponse")
-	proto.RegisterType((*MoveRequest)(nil), "proto.MoveRequest")
-	proto.RegisterType((*Empty)(nil), "proto.Empty")
+func (m *Violation) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
 }
 
-func init() {
-	proto.RegisterFile("proto/services.proto", fileDescriptor_c2d444674d051dbb)
+func (m *Violation) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
 }
 
-var fileDescriptor_c2d444674d051dbb = []byte{
-	// 537 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0x41, 0x6f, 0xda, 0x30,
-	0x14, 0xc7, 0x49, 0x80, 0x94, 0x3e, 0x28, 0x93, 0x5c, 0x2a, 0xa1, 0x9c, 0x2a, 0x4b, 0xd5, 0x98,
-	0xc6, 0x02, 0x63, 0x87, 0x49, 0x3b, 0x6d, 0xdd, 0x50, 0x2f, 0xad, 0x54, 0x45, 0xdb, 0x65, 0x97,
-	0x29, 0x10, 0xab, 0x8d, 0x96, 0xc4, 0x59, 0xec, 0x30, 0xf8, 0x3e, 0xfb, 0x86, 0xfb, 0x02, 0x95,
-	0x63, 0x3b, 0x4d, 0x02, 0x05, 0x4e, 0xc9, 0xf3, 0xff, 0xd9, 0xef, 0xe7, 0xff, 0x3f, 0x81, 0x41,
-	0x92, 0x52, 0x4e, 0x27, 0x8c, 0xa4, 0xab, 0x60, 0x49, 0x98, 0x93, 0x97, 0xa8, 0x9d, 0x3f, 0xf0,
-	0x1a, 0xac, 0xfb, 0xd0, 0xdb, 0x90, 0x14, 0xd9, 0xd0, 0xc9, 0x18, 0x49, 0x63, 0x2f, 0x22, 0x43,
-	0xe3, 0xd2, 0x18, 0x9d, 0xba, 0x45, 0x2d, 0xb4, 0xc4, 0x63, 0xec, 0x2f, 0x4d, 0xfd, 0xa1, 0x29,
-	0x35, 0x5d, 0x0b, 0x2d, 0xa4, 0x4b, 0x8f, 0x07, 0x34, 0x1e, 0x36, 0xa5, 0xa6, 0x6b, 0xd4, 0x03,
-	0x63, 0x3d, 0x6c, 0x5d, 0x1a, 0xa3, 0xb6, 0x6b, 0xac, 0x45, 0xb5, 0x19, 0xb6, 0x65, 0xb5, 0xc1,
-	0x73, 0xe8, 0xc9, 0xc9, 0x3f, 0x12, 0xdf, 0xe3, 0x04, 0xf5, 0xc1, 0x0c, 0x7c, 0x35, 0xd9, 0x0c,
-	0x7c, 0x74, 0x05, 0x56, 0x92, 0xeb, 0xf9, 0xc4, 0xee, 0xec, 0x4c, 0x82, 0x3b, 0x72, 0x93, 0xab,
-	0x44, 0xfc, 0x09, 0x3a, 0xb7, 0x7a, 0x1c, 0x82, 0x56, 0x09, 0x3f, 0x7f, 0x97, 0x08, 0x66, 0x05,
-	0xa1, 0xa9, 0x11, 0xee, 0xa0, 0xaf, 0xf7, 0xbe, 0x00, 0xf1, 0xb6, 0x74, 0x39, 0x89, 0xf1, 0x4a,
-	0x61, 0xe8, 0x8d, 0xcf, 0xb7, 0xc5, 0xd7, 0xd0, 0xb9, 0xa7, 0x2c, 0xc8, 0x51, 0xca, 0xae, 0x18,
-	0xbb, 0x5c, 0x79, 0x01, 0xe9, 0x33, 0xf4, 0xbe, 0x64, 0xfc, 0xd1, 0x25, 0x2c, 0xa1, 0x31, 0x23,
-	0x7b, 0x53, 0x19, 0x40, 0x9b, 0xd3, 0xdf, 0x24, 0x56, 0x91, 0xc8, 0x02, 0xdf, 0xc0, 0xd9, 0xf7,
-	0xd4, 0x5b, 0x91, 0xd0, 0x25, 0x7f, 0x32, 0xc2, 0xf8, 0xa1, 0x60, 0x2b, 0xf7, 0x2b, 0x61, 0x62,
-	0x1f, 0xfa, 0xfa, 0x20, 0x05, 0xf3, 0x1c, 0x89, 0xb1, 0x27, 0x12, 0x61, 0x5a, 0xa2, 0x7c, 0xa8,
-	0x99, 0xa6, 0xed, 0x71, 0x8b, 0x06, 0x3c, 0x87, 0xee, 0x1d, 0x5d, 0x91, 0x63, 0x60, 0xf7, 0xf9,
-	0x76, 0x02, 0xed, 0x79, 0x94, 0xf0, 0xcd, 0xec, 0xbf, 0x09, 0x27, 0x92, 0x87, 0xa1, 0x11, 0x58,
-	0x5f, 0x53, 0x22, 0x72, 0xad, 0x92, 0xda, 0xd5, 0x12, 0x37, 0xd0, 0x15, 0x34, 0x6f, 0x08, 0x3f,
-	0xd8, 0x36, 0x86, 0x96, 0x48, 0xa7, 0xde, 0x77, 0xae, 0xca, 0x72, 0x72, 0xb8, 0x81, 0x5e, 0x43,
-	0xeb, 0x36, 0x60, 0x1c, 0xf5, 0x94, 0x9c, 0x03, 0x6e, 0x1d, 0x3a, 0x35, 0x90, 0x03, 0x96, 0xfa,
-	0xfe, 0xce, 0x2b, 0xa2, 0x5c, 0xdc, 0xc6, 0xf8, 0x08, 0x96, 0x4c, 0x06, 0x0d, 0x94, 0x54, 0x49,
-	0xdc, 0xbe, 0xa8, 0xad, 0x16, 0x44, 0xef, 0xa0, 0x25, 0xcc, 0x46, 0x48, 0x35, 0x94, 0x9c, 0xb7,
-	0xeb, 0x19, 0xe1, 0x86, 0xf0, 0xef, 0x1b, 0x09, 0xc9, 0x61, 0xff, 0x66, 0xff, 0x4c, 0x38, 0xd5,
-	0x7f, 0x04, 0x43, 0xe3, 0xc2, 0xf7, 0xfa, 0xdf, 0x62, 0xd7, 0x17, 0x70, 0x03, 0xbd, 0x91, 0xde,
-	0x1f, 0xd7, 0xba, 0xcb, 0xd1, 0xed, 0xc6, 0xa9, 0x81, 0xde, 0x43, 0x57, 0xb4, 0xea, 0x4f, 0x61,
-	0xeb, 0xf4, 0x1d, 0x31, 0xcc, 0x8a, 0x18, 0x2e, 0x6a, 0xdd, 0x2a, 0x88, 0x1d, 0x44, 0xe3, 0xc2,
-	0xa2, 0x23, 0xf8, 0xaf, 0xa7, 0x3f, 0x9d, 0x87, 0x80, 0x3f, 0x66, 0x0b, 0x67, 0x49, 0xa3, 0xc9,
-	0xd2, 0x4b, 0x19, 0x8d, 0x23, 0xc1, 0x37, 0x59, 0x64, 0x8b, 0xd0, 0x4b, 0x7f, 0x79, 0x8c, 0x05,
-	0x0f, 0x71, 0x44, 0x62, 0x3e, 0xc9, 0x37, 0x2f, 0xac, 0xfc, 0xf1, 0xe1, 0x29, 0x00, 0x00, 0xff,
-	0xff, 0x7a, 0xa8, 0x58, 0x4f, 0xc5, 0x05, 0x00, 0x00,
+func (m *Violation) GetLocation() string {
+	if m != nil {
+		return m.Location
+	}
+	return ""
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func (m *Violation) GetFromX() int32 {
+	if m != nil {
+		return m.FromX
+	}
+	return 0
+}
 
-// PlayersClient is the client API for Players service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PlayersClient interface {
-	Create(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error)
-	Get(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error)
-	Auth(ctx context.Context, in *Player, opts ...grpc.CallOption) (*AuthResponse, error)
-	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Players_ListClient, error)
-	Update(ctx context.Context, in *PlayerUpdate, opts ...grpc.CallOption) (*Player, error)
-	Travel(ctx context.Context, in *TravelRequest, opts ...grpc.CallOption) (*TravelResponse, error)
-	Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*Position, error)
-	Delete(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error)
+func (m *Violation) GetFromY() int32 {
+	if m != nil {
+		return m.FromY
+	}
+	return 0
 }
 
-type playersClient struct {
-	cc grpc.ClientConnInterface
+func (m *Violation) GetToX() int32 {
+	if m != nil {
+		return m.ToX
+	}
+	return 0
 }
 
-func NewPlayersClient(cc grpc.ClientConnInterface) PlayersClient {
-	return &playersClient{cc}
+func (m *Violation) GetToY() int32 {
+	if m != nil {
+		return m.ToY
+	}
+	return 0
 }
 
-func (c *playersClient) Create(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error) {
-	out := new(Player)
-	err := c.cc.Invoke(ctx, "/proto.Players/Create", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Violation) GetDistance() float64 {
+	if m != nil {
+		return m.Distance
 	}
-	return out, nil
+	return 0
 }
 
-func (c *playersClient) Get(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error) {
-	out := new(Player)
-	err := c.cc.Invoke(ctx, "/proto.Players/Get", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Violation) GetAllowed() float64 {
+	if m != nil {
+		return m.Allowed
 	}
-	return out, nil
+	return 0
 }
 
-func (c *playersClient) Auth(ctx context.Context, in *Player, opts ...grpc.CallOption) (*AuthResponse, error) {
-	out := new(AuthResponse)
-	err := c.cc.Invoke(ctx, "/proto.Players/Auth", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Violation) GetClamped() bool {
+	if m != nil {
+		return m.Clamped
 	}
-	return out, nil
+	return false
 }
 
-func (c *playersClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Players_ListClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Players_serviceDesc.Streams[0], "/proto.Players/List", opts...)
-	if err != nil {
-		return nil, err
+func (m *Violation) GetTime() int64 {
+	if m != nil {
+		return m.Time
 	}
-	x := &playersListClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	return 0
+}
+
+// empty fields match every entry; since and until are unix seconds. The
+// cursor for the next page is sent in the `next` trailer
+type AuditQuery struct {
+	Actor                string   `protobuf:"bytes,1,opt,name=actor,proto3" json:"actor,omitempty"`
+	Action               string   `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Target               string   `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"`
+	RequestId            string   `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Since                int64    `protobuf:"varint,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until                int64    `protobuf:"varint,6,opt,name=until,proto3" json:"until,omitempty"`
+	Limit                int32    `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	After                string   `protobuf:"bytes,8,opt,name=after,proto3" json:"after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditQuery) Reset()         { *m = AuditQuery{} }
+func (m *AuditQuery) String() string { return proto.CompactTextString(m) }
+func (*AuditQuery) ProtoMessage()    {}
+func (*AuditQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{13}
+}
+
+func (m *AuditQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditQuery.Unmarshal(m, b)
+}
+func (m *AuditQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditQuery.Marshal(b, m, deterministic)
+}
+func (m *AuditQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditQuery.Merge(m, src)
+}
+func (m *AuditQuery) XXX_Size() int {
+	return xxx_messageInfo_AuditQuery.Size(m)
+}
+func (m *AuditQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditQuery proto.InternalMessageInfo
+
+func (m *AuditQuery) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditQuery) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditQuery) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *AuditQuery) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *AuditQuery) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *AuditQuery) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *AuditQuery) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *AuditQuery) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+// a change made through a service, and who made it
+type AuditEntry struct {
+	Id        uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Time      int64  `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Actor     string `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Source    string `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Address   string `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	Action    string `protobuf:"bytes,6,opt,name=action,proto3" json:"action,omitempty"`
+	Target    string `protobuf:"bytes,7,opt,name=target,proto3" json:"target,omitempty"`
+	RequestId string `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// the JSON of the target before and after the change, empty if it didn't
+	// exist
+	Before               string   `protobuf:"bytes,9,opt,name=before,proto3" json:"before,omitempty"`
+	After                string   `protobuf:"bytes,10,opt,name=after,proto3" json:"after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEntry) Reset()         { *m = AuditEntry{} }
+func (m *AuditEntry) String() string { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()    {}
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{14}
+}
+
+func (m *AuditEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEntry.Unmarshal(m, b)
+}
+func (m *AuditEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEntry.Marshal(b, m, deterministic)
+}
+func (m *AuditEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEntry.Merge(m, src)
+}
+func (m *AuditEntry) XXX_Size() int {
+	return xxx_messageInfo_AuditEntry.Size(m)
+}
+func (m *AuditEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEntry proto.InternalMessageInfo
+
+func (m *AuditEntry) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetBefore() string {
+	if m != nil {
+		return m.Before
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+type Event struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Location             string   `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Previous             string   `protobuf:"bytes,3,opt,name=previous,proto3" json:"previous,omitempty"`
+	Time                 int64    `protobuf:"varint,4,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{15}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *Event) GetLocation() string {
+	if m != nil {
+		return m.Location
+	}
+	return ""
+}
+
+func (m *Event) GetPrevious() string {
+	if m != nil {
+		return m.Previous
+	}
+	return ""
+}
+
+func (m *Event) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+// a batch of players or locations to fetch at once
+type Names struct {
+	Names                []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Names) Reset()         { *m = Names{} }
+func (m *Names) String() string { return proto.CompactTextString(m) }
+func (*Names) ProtoMessage()    {}
+func (*Names) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{16}
+}
+
+func (m *Names) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Names.Unmarshal(m, b)
+}
+func (m *Names) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Names.Marshal(b, m, deterministic)
+}
+func (m *Names) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Names.Merge(m, src)
+}
+func (m *Names) XXX_Size() int {
+	return xxx_messageInfo_Names.Size(m)
+}
+func (m *Names) XXX_DiscardUnknown() {
+	xxx_messageInfo_Names.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Names proto.InternalMessageInfo
+
+func (m *Names) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{17}
+}
+
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (m *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(m, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Empty proto.InternalMessageInfo
+
+// every player account, without passwords
+type PlayerSnapshot struct {
+	Players              []*Player `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PlayerSnapshot) Reset()         { *m = PlayerSnapshot{} }
+func (m *PlayerSnapshot) String() string { return proto.CompactTextString(m) }
+func (*PlayerSnapshot) ProtoMessage()    {}
+func (*PlayerSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{18}
+}
+
+func (m *PlayerSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlayerSnapshot.Unmarshal(m, b)
+}
+func (m *PlayerSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlayerSnapshot.Marshal(b, m, deterministic)
+}
+func (m *PlayerSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlayerSnapshot.Merge(m, src)
+}
+func (m *PlayerSnapshot) XXX_Size() int {
+	return xxx_messageInfo_PlayerSnapshot.Size(m)
+}
+func (m *PlayerSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlayerSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlayerSnapshot proto.InternalMessageInfo
+
+func (m *PlayerSnapshot) GetPlayers() []*Player {
+	if m != nil {
+		return m.Players
+	}
+	return nil
+}
+
+type PlayerSnapshotRestore struct {
+	Snapshot *PlayerSnapshot `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// delete accounts which are not in the snapshot
+	Clear                bool     `protobuf:"varint,2,opt,name=clear,proto3" json:"clear,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlayerSnapshotRestore) Reset()         { *m = PlayerSnapshotRestore{} }
+func (m *PlayerSnapshotRestore) String() string { return proto.CompactTextString(m) }
+func (*PlayerSnapshotRestore) ProtoMessage()    {}
+func (*PlayerSnapshotRestore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{19}
+}
+
+func (m *PlayerSnapshotRestore) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlayerSnapshotRestore.Unmarshal(m, b)
+}
+func (m *PlayerSnapshotRestore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlayerSnapshotRestore.Marshal(b, m, deterministic)
+}
+func (m *PlayerSnapshotRestore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlayerSnapshotRestore.Merge(m, src)
+}
+func (m *PlayerSnapshotRestore) XXX_Size() int {
+	return xxx_messageInfo_PlayerSnapshotRestore.Size(m)
+}
+func (m *PlayerSnapshotRestore) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlayerSnapshotRestore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlayerSnapshotRestore proto.InternalMessageInfo
+
+func (m *PlayerSnapshotRestore) GetSnapshot() *PlayerSnapshot {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+func (m *PlayerSnapshotRestore) GetClear() bool {
+	if m != nil {
+		return m.Clear
+	}
+	return false
+}
+
+// a location and the positions of the players in it
+type LocationState struct {
+	Location             *Location `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Members              []*Player `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *LocationState) Reset()         { *m = LocationState{} }
+func (m *LocationState) String() string { return proto.CompactTextString(m) }
+func (*LocationState) ProtoMessage()    {}
+func (*LocationState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{20}
+}
+
+func (m *LocationState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LocationState.Unmarshal(m, b)
+}
+func (m *LocationState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LocationState.Marshal(b, m, deterministic)
+}
+func (m *LocationState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocationState.Merge(m, src)
+}
+func (m *LocationState) XXX_Size() int {
+	return xxx_messageInfo_LocationState.Size(m)
+}
+func (m *LocationState) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocationState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LocationState proto.InternalMessageInfo
+
+func (m *LocationState) GetLocation() *Location {
+	if m != nil {
+		return m.Location
+	}
+	return nil
+}
+
+func (m *LocationState) GetMembers() []*Player {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+type LocationSnapshot struct {
+	Locations            []*LocationState `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *LocationSnapshot) Reset()         { *m = LocationSnapshot{} }
+func (m *LocationSnapshot) String() string { return proto.CompactTextString(m) }
+func (*LocationSnapshot) ProtoMessage()    {}
+func (*LocationSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{21}
+}
+
+func (m *LocationSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LocationSnapshot.Unmarshal(m, b)
+}
+func (m *LocationSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LocationSnapshot.Marshal(b, m, deterministic)
+}
+func (m *LocationSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocationSnapshot.Merge(m, src)
+}
+func (m *LocationSnapshot) XXX_Size() int {
+	return xxx_messageInfo_LocationSnapshot.Size(m)
+}
+func (m *LocationSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocationSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LocationSnapshot proto.InternalMessageInfo
+
+func (m *LocationSnapshot) GetLocations() []*LocationState {
+	if m != nil {
+		return m.Locations
+	}
+	return nil
+}
+
+type LocationSnapshotRestore struct {
+	Snapshot *LocationSnapshot `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// delete locations, and remove players from locations, not in the snapshot
+	Clear                bool     `protobuf:"varint,2,opt,name=clear,proto3" json:"clear,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LocationSnapshotRestore) Reset()         { *m = LocationSnapshotRestore{} }
+func (m *LocationSnapshotRestore) String() string { return proto.CompactTextString(m) }
+func (*LocationSnapshotRestore) ProtoMessage()    {}
+func (*LocationSnapshotRestore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{22}
+}
+
+func (m *LocationSnapshotRestore) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LocationSnapshotRestore.Unmarshal(m, b)
+}
+func (m *LocationSnapshotRestore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LocationSnapshotRestore.Marshal(b, m, deterministic)
+}
+func (m *LocationSnapshotRestore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocationSnapshotRestore.Merge(m, src)
+}
+func (m *LocationSnapshotRestore) XXX_Size() int {
+	return xxx_messageInfo_LocationSnapshotRestore.Size(m)
+}
+func (m *LocationSnapshotRestore) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocationSnapshotRestore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LocationSnapshotRestore proto.InternalMessageInfo
+
+func (m *LocationSnapshotRestore) GetSnapshot() *LocationSnapshot {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+func (m *LocationSnapshotRestore) GetClear() bool {
+	if m != nil {
+		return m.Clear
+	}
+	return false
+}
+
+type RestoreReport struct {
+	Created              int32    `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Updated              int32    `protobuf:"varint,2,opt,name=updated,proto3" json:"updated,omitempty"`
+	Restored             int32    `protobuf:"varint,3,opt,name=restored,proto3" json:"restored,omitempty"`
+	Deleted              int32    `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Positions            int32    `protobuf:"varint,5,opt,name=positions,proto3" json:"positions,omitempty"`
+	Missing              []string `protobuf:"bytes,6,rep,name=missing,proto3" json:"missing,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreReport) Reset()         { *m = RestoreReport{} }
+func (m *RestoreReport) String() string { return proto.CompactTextString(m) }
+func (*RestoreReport) ProtoMessage()    {}
+func (*RestoreReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{23}
+}
+
+func (m *RestoreReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreReport.Unmarshal(m, b)
+}
+func (m *RestoreReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreReport.Marshal(b, m, deterministic)
+}
+func (m *RestoreReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreReport.Merge(m, src)
+}
+func (m *RestoreReport) XXX_Size() int {
+	return xxx_messageInfo_RestoreReport.Size(m)
+}
+func (m *RestoreReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreReport proto.InternalMessageInfo
+
+func (m *RestoreReport) GetCreated() int32 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *RestoreReport) GetUpdated() int32 {
+	if m != nil {
+		return m.Updated
+	}
+	return 0
+}
+
+func (m *RestoreReport) GetRestored() int32 {
+	if m != nil {
+		return m.Restored
+	}
+	return 0
+}
+
+func (m *RestoreReport) GetDeleted() int32 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+func (m *RestoreReport) GetPositions() int32 {
+	if m != nil {
+		return m.Positions
+	}
+	return 0
+}
+
+func (m *RestoreReport) GetMissing() []string {
+	if m != nil {
+		return m.Missing
+	}
+	return nil
+}
+
+// attached to the status of a failed call, so that clients can recover the
+// error the service returned
+type Problem struct {
+	Kind    string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Ctx     string `protobuf:"bytes,2,opt,name=ctx,proto3" json:"ctx,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// JSON-encoded structured details, if there are any
+	Details              []byte   `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	Code                 string   `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Problem) Reset()         { *m = Problem{} }
+func (m *Problem) String() string { return proto.CompactTextString(m) }
+func (*Problem) ProtoMessage()    {}
+func (*Problem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{24}
+}
+
+func (m *Problem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Problem.Unmarshal(m, b)
+}
+func (m *Problem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Problem.Marshal(b, m, deterministic)
+}
+func (m *Problem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Problem.Merge(m, src)
+}
+func (m *Problem) XXX_Size() int {
+	return xxx_messageInfo_Problem.Size(m)
+}
+func (m *Problem) XXX_DiscardUnknown() {
+	xxx_messageInfo_Problem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Problem proto.InternalMessageInfo
+
+func (m *Problem) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *Problem) GetCtx() string {
+	if m != nil {
+		return m.Ctx
+	}
+	return ""
+}
+
+func (m *Problem) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Problem) GetDetails() []byte {
+	if m != nil {
+		return m.Details
+	}
+	return nil
+}
+
+func (m *Problem) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+// the v1 API response envelope, sent to clients which accept
+// application/x-protobuf. The data is the message of what the JSON response
+// holds, such as a Player, and lists are a List of those messages.
+type Response struct {
+	Status               string     `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Problems             []*Problem `protobuf:"bytes,2,rep,name=problems,proto3" json:"problems,omitempty"`
+	Next                 string     `protobuf:"bytes,4,opt,name=next,proto3" json:"next,omitempty"`
+	Data                 *any.Any   `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+func (*Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{25}
+}
+
+func (m *Response) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Response.Unmarshal(m, b)
+}
+func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
+}
+func (m *Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Response.Merge(m, src)
+}
+func (m *Response) XXX_Size() int {
+	return xxx_messageInfo_Response.Size(m)
+}
+func (m *Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_Response.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Response proto.InternalMessageInfo
+
+func (m *Response) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Response) GetProblems() []*Problem {
+	if m != nil {
+		return m.Problems
+	}
+	return nil
+}
+
+func (m *Response) GetNext() string {
+	if m != nil {
+		return m.Next
+	}
+	return ""
+}
+
+func (m *Response) GetData() *any.Any {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type List struct {
+	Items                []*any.Any `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *List) Reset()         { *m = List{} }
+func (m *List) String() string { return proto.CompactTextString(m) }
+func (*List) ProtoMessage()    {}
+func (*List) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{26}
+}
+
+func (m *List) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_List.Unmarshal(m, b)
+}
+func (m *List) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_List.Marshal(b, m, deterministic)
+}
+func (m *List) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_List.Merge(m, src)
+}
+func (m *List) XXX_Size() int {
+	return xxx_messageInfo_List.Size(m)
+}
+func (m *List) XXX_DiscardUnknown() {
+	xxx_messageInfo_List.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_List proto.InternalMessageInfo
+
+func (m *List) GetItems() []*any.Any {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+type Ping struct {
+	Say                  string   `protobuf:"bytes,1,opt,name=say,proto3" json:"say,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Ping) Reset()         { *m = Ping{} }
+func (m *Ping) String() string { return proto.CompactTextString(m) }
+func (*Ping) ProtoMessage()    {}
+func (*Ping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{27}
+}
+
+func (m *Ping) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Ping.Unmarshal(m, b)
+}
+func (m *Ping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Ping.Marshal(b, m, deterministic)
+}
+func (m *Ping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ping.Merge(m, src)
+}
+func (m *Ping) XXX_Size() int {
+	return xxx_messageInfo_Ping.Size(m)
+}
+func (m *Ping) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ping.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Ping proto.InternalMessageInfo
+
+func (m *Ping) GetSay() string {
+	if m != nil {
+		return m.Say
+	}
+	return ""
+}
+
+// a world document; players' positions are in their location and coordinates
+type WorldDocument struct {
+	Version              int32       `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Locations            []*Location `protobuf:"bytes,2,rep,name=locations,proto3" json:"locations,omitempty"`
+	Players              []*Player   `protobuf:"bytes,3,rep,name=players,proto3" json:"players,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *WorldDocument) Reset()         { *m = WorldDocument{} }
+func (m *WorldDocument) String() string { return proto.CompactTextString(m) }
+func (*WorldDocument) ProtoMessage()    {}
+func (*WorldDocument) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{28}
+}
+
+func (m *WorldDocument) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorldDocument.Unmarshal(m, b)
+}
+func (m *WorldDocument) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorldDocument.Marshal(b, m, deterministic)
+}
+func (m *WorldDocument) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorldDocument.Merge(m, src)
+}
+func (m *WorldDocument) XXX_Size() int {
+	return xxx_messageInfo_WorldDocument.Size(m)
+}
+func (m *WorldDocument) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorldDocument.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorldDocument proto.InternalMessageInfo
+
+func (m *WorldDocument) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *WorldDocument) GetLocations() []*Location {
+	if m != nil {
+		return m.Locations
+	}
+	return nil
+}
+
+func (m *WorldDocument) GetPlayers() []*Player {
+	if m != nil {
+		return m.Players
+	}
+	return nil
+}
+
+type WorldChange struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Action               string   `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorldChange) Reset()         { *m = WorldChange{} }
+func (m *WorldChange) String() string { return proto.CompactTextString(m) }
+func (*WorldChange) ProtoMessage()    {}
+func (*WorldChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{29}
+}
+
+func (m *WorldChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorldChange.Unmarshal(m, b)
+}
+func (m *WorldChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorldChange.Marshal(b, m, deterministic)
+}
+func (m *WorldChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorldChange.Merge(m, src)
+}
+func (m *WorldChange) XXX_Size() int {
+	return xxx_messageInfo_WorldChange.Size(m)
+}
+func (m *WorldChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorldChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorldChange proto.InternalMessageInfo
+
+func (m *WorldChange) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *WorldChange) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WorldChange) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+// the changes a world import made, or would make in a dry run
+type WorldReport struct {
+	DryRun               bool           `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Changes              []*WorldChange `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *WorldReport) Reset()         { *m = WorldReport{} }
+func (m *WorldReport) String() string { return proto.CompactTextString(m) }
+func (*WorldReport) ProtoMessage()    {}
+func (*WorldReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{30}
+}
+
+func (m *WorldReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorldReport.Unmarshal(m, b)
+}
+func (m *WorldReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorldReport.Marshal(b, m, deterministic)
+}
+func (m *WorldReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorldReport.Merge(m, src)
+}
+func (m *WorldReport) XXX_Size() int {
+	return xxx_messageInfo_WorldReport.Size(m)
+}
+func (m *WorldReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorldReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorldReport proto.InternalMessageInfo
+
+func (m *WorldReport) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *WorldReport) GetChanges() []*WorldChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+// time is unix seconds
+type WorldSnapshot struct {
+	Version              int32            `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Time                 int64            `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Locations            []*LocationState `protobuf:"bytes,3,rep,name=locations,proto3" json:"locations,omitempty"`
+	Accounts             []*Player        `protobuf:"bytes,4,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *WorldSnapshot) Reset()         { *m = WorldSnapshot{} }
+func (m *WorldSnapshot) String() string { return proto.CompactTextString(m) }
+func (*WorldSnapshot) ProtoMessage()    {}
+func (*WorldSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{31}
+}
+
+func (m *WorldSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorldSnapshot.Unmarshal(m, b)
+}
+func (m *WorldSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorldSnapshot.Marshal(b, m, deterministic)
+}
+func (m *WorldSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorldSnapshot.Merge(m, src)
+}
+func (m *WorldSnapshot) XXX_Size() int {
+	return xxx_messageInfo_WorldSnapshot.Size(m)
+}
+func (m *WorldSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorldSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorldSnapshot proto.InternalMessageInfo
+
+func (m *WorldSnapshot) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *WorldSnapshot) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *WorldSnapshot) GetLocations() []*LocationState {
+	if m != nil {
+		return m.Locations
+	}
+	return nil
+}
+
+func (m *WorldSnapshot) GetAccounts() []*Player {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+type WorldSnapshotReport struct {
+	Accounts             *RestoreReport `protobuf:"bytes,1,opt,name=accounts,proto3" json:"accounts,omitempty"`
+	Locations            *RestoreReport `protobuf:"bytes,2,opt,name=locations,proto3" json:"locations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *WorldSnapshotReport) Reset()         { *m = WorldSnapshotReport{} }
+func (m *WorldSnapshotReport) String() string { return proto.CompactTextString(m) }
+func (*WorldSnapshotReport) ProtoMessage()    {}
+func (*WorldSnapshotReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{32}
+}
+
+func (m *WorldSnapshotReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorldSnapshotReport.Unmarshal(m, b)
+}
+func (m *WorldSnapshotReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorldSnapshotReport.Marshal(b, m, deterministic)
+}
+func (m *WorldSnapshotReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorldSnapshotReport.Merge(m, src)
+}
+func (m *WorldSnapshotReport) XXX_Size() int {
+	return xxx_messageInfo_WorldSnapshotReport.Size(m)
+}
+func (m *WorldSnapshotReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorldSnapshotReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorldSnapshotReport proto.InternalMessageInfo
+
+func (m *WorldSnapshotReport) GetAccounts() *RestoreReport {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+func (m *WorldSnapshotReport) GetLocations() *RestoreReport {
+	if m != nil {
+		return m.Locations
+	}
+	return nil
+}
+
+// the outcome of one operation of a batch
+type BatchResult struct {
+	Method               string    `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Path                 string    `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	StatusCode           int32     `protobuf:"varint,3,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Skipped              bool      `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Response             *Response `protobuf:"bytes,5,opt,name=response,proto3" json:"response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *BatchResult) Reset()         { *m = BatchResult{} }
+func (m *BatchResult) String() string { return proto.CompactTextString(m) }
+func (*BatchResult) ProtoMessage()    {}
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c2d444674d051dbb, []int{33}
+}
+
+func (m *BatchResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchResult.Unmarshal(m, b)
+}
+func (m *BatchResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchResult.Marshal(b, m, deterministic)
+}
+func (m *BatchResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchResult.Merge(m, src)
+}
+func (m *BatchResult) XXX_Size() int {
+	return xxx_messageInfo_BatchResult.Size(m)
+}
+func (m *BatchResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchResult proto.InternalMessageInfo
+
+func (m *BatchResult) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *BatchResult) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *BatchResult) GetStatusCode() int32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+func (m *BatchResult) GetSkipped() bool {
+	if m != nil {
+		return m.Skipped
+	}
+	return false
+}
+
+func (m *BatchResult) GetResponse() *Response {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Player)(nil), "proto.Player")
+	proto.RegisterType((*PlayerUpdate)(nil), "proto.PlayerUpdate")
+	proto.RegisterType((*Location)(nil), "proto.Location")
+	proto.RegisterType((*Tile)(nil), "proto.Tile")
+	proto.RegisterType((*LocationUpdate)(nil), "proto.LocationUpdate")
+	proto.RegisterType((*PlayerListRequest)(nil), "proto.PlayerListRequest")
+	proto.RegisterType((*LocationListRequest)(nil), "proto.LocationListRequest")
+	proto.RegisterType((*Position)(nil), "proto.Position")
+	proto.RegisterType((*AuthResponse)(nil), "proto.AuthResponse")
+	proto.RegisterType((*TravelRequest)(nil), "proto.TravelRequest")
+	proto.RegisterType((*TravelResponse)(nil), "proto.TravelResponse")
+	proto.RegisterType((*MoveRequest)(nil), "proto.MoveRequest")
+	proto.RegisterType((*Violation)(nil), "proto.Violation")
+	proto.RegisterType((*AuditQuery)(nil), "proto.AuditQuery")
+	proto.RegisterType((*AuditEntry)(nil), "proto.AuditEntry")
+	proto.RegisterType((*Event)(nil), "proto.Event")
+	proto.RegisterType((*Names)(nil), "proto.Names")
+	proto.RegisterType((*Empty)(nil), "proto.Empty")
+	proto.RegisterType((*PlayerSnapshot)(nil), "proto.PlayerSnapshot")
+	proto.RegisterType((*PlayerSnapshotRestore)(nil), "proto.PlayerSnapshotRestore")
+	proto.RegisterType((*LocationState)(nil), "proto.LocationState")
+	proto.RegisterType((*LocationSnapshot)(nil), "proto.LocationSnapshot")
+	proto.RegisterType((*LocationSnapshotRestore)(nil), "proto.LocationSnapshotRestore")
+	proto.RegisterType((*RestoreReport)(nil), "proto.RestoreReport")
+	proto.RegisterType((*Problem)(nil), "proto.Problem")
+	proto.RegisterType((*Response)(nil), "proto.Response")
+	proto.RegisterType((*List)(nil), "proto.List")
+	proto.RegisterType((*Ping)(nil), "proto.Ping")
+	proto.RegisterType((*WorldDocument)(nil), "proto.WorldDocument")
+	proto.RegisterType((*WorldChange)(nil), "proto.WorldChange")
+	proto.RegisterType((*WorldReport)(nil), "proto.WorldReport")
+	proto.RegisterType((*WorldSnapshot)(nil), "proto.WorldSnapshot")
+	proto.RegisterType((*WorldSnapshotReport)(nil), "proto.WorldSnapshotReport")
+	proto.RegisterType((*BatchResult)(nil), "proto.BatchResult")
+}
+
+func init() {
+	proto.RegisterFile("proto/services.proto", fileDescriptor_c2d444674d051dbb)
+}
+
+var fileDescriptor_c2d444674d051dbb = []byte{
+	// 2024 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0x4b, 0x73, 0xdd, 0x48,
+	0x15, 0x8e, 0xee, 0xfb, 0x1e, 0x3f, 0x12, 0xb7, 0x1f, 0x11, 0x17, 0x27, 0xe3, 0xe9, 0x24, 0x13,
+	0x8f, 0x13, 0x7c, 0x8d, 0x67, 0xc5, 0xac, 0xc8, 0x8b, 0x40, 0x2a, 0x1e, 0x82, 0x9c, 0x21, 0x13,
+	0x6a, 0xaa, 0x3c, 0x6d, 0xa9, 0x7d, 0xad, 0x8a, 0x5e, 0xa8, 0x5b, 0x37, 0xbe, 0x95, 0x9a, 0x05,
+	0x6c, 0xd9, 0xc1, 0x96, 0x2a, 0x8a, 0x25, 0x3b, 0xf8, 0x19, 0xac, 0xf9, 0x0b, 0xfc, 0x00, 0x36,
+	0xec, 0xa9, 0x7e, 0x49, 0xba, 0xba, 0xb2, 0x9c, 0x15, 0x2b, 0xe9, 0xf4, 0xe3, 0xeb, 0xef, 0x9c,
+	0x3e, 0xe7, 0xeb, 0x6e, 0xd8, 0x48, 0xd2, 0x98, 0xc7, 0x63, 0x46, 0xd3, 0xa9, 0xef, 0x52, 0xb6,
+	0x2f, 0x4d, 0xd4, 0x95, 0x9f, 0xd1, 0xf6, 0x24, 0x8e, 0x27, 0x01, 0x1d, 0x93, 0xc4, 0x1f, 0x93,
+	0x28, 0x8a, 0x39, 0xe1, 0x7e, 0x1c, 0xe9, 0x41, 0xa3, 0x1f, 0xe8, 0x5e, 0x69, 0x9d, 0x66, 0x67,
+	0x63, 0x12, 0xcd, 0x54, 0x17, 0xfe, 0xa3, 0x05, 0xbd, 0x57, 0x01, 0x99, 0xd1, 0x14, 0x8d, 0x60,
+	0x90, 0x31, 0x9a, 0x46, 0x24, 0xa4, 0xb6, 0xb5, 0x63, 0xed, 0x0e, 0x9d, 0xdc, 0x16, 0x7d, 0x09,
+	0x61, 0xec, 0x7d, 0x9c, 0x7a, 0x76, 0x4b, 0xf5, 0x19, 0x5b, 0xf4, 0x05, 0xb1, 0x2b, 0x17, 0xb4,
+	0xdb, 0xaa, 0xcf, 0xd8, 0x68, 0x19, 0xac, 0x0b, 0xbb, 0xb3, 0x63, 0xed, 0x76, 0x1d, 0xeb, 0x42,
+	0x58, 0x33, 0xbb, 0xab, 0xac, 0x19, 0xb2, 0xa1, 0x3f, 0xa5, 0x29, 0x13, 0xd3, 0x7a, 0x3b, 0xd6,
+	0x6e, 0xc7, 0x31, 0x26, 0x7e, 0x06, 0xcb, 0x8a, 0xd3, 0xd7, 0x89, 0x47, 0x38, 0x45, 0xab, 0xd0,
+	0xf2, 0x3d, 0xcd, 0xa9, 0xe5, 0x7b, 0xe8, 0x1e, 0xf4, 0x12, 0xd9, 0x2f, 0xb9, 0x2c, 0x1d, 0xae,
+	0x28, 0x67, 0xf6, 0xd5, 0x24, 0x47, 0x77, 0xe2, 0xbf, 0x59, 0x30, 0x78, 0x69, 0x98, 0x20, 0xe8,
+	0x94, 0x3c, 0x93, 0xff, 0x8a, 0x5d, 0x6b, 0x8e, 0x5d, 0xbb, 0x86, 0x5d, 0x67, 0x8e, 0x1d, 0xda,
+	0x80, 0xee, 0x7b, 0xdf, 0xe3, 0xe7, 0xda, 0x13, 0x65, 0xa0, 0x2d, 0xe8, 0x9d, 0x53, 0x7f, 0x72,
+	0xce, 0xa5, 0x33, 0x5d, 0x47, 0x5b, 0xe8, 0x1e, 0xf4, 0x4f, 0x83, 0xd8, 0x7d, 0x47, 0x3d, 0xbb,
+	0xbf, 0xd3, 0xde, 0x5d, 0x3a, 0x5c, 0xd2, 0x64, 0x5f, 0xfb, 0x01, 0x75, 0x4c, 0x1f, 0xc6, 0xd0,
+	0x11, 0x0d, 0x8a, 0x92, 0x35, 0x47, 0x49, 0x13, 0x9c, 0xe1, 0x23, 0x58, 0x35, 0xee, 0x5c, 0x12,
+	0x98, 0x07, 0xa5, 0xad, 0x50, 0xa1, 0xb9, 0xae, 0x57, 0x33, 0x13, 0x8b, 0xbd, 0xc1, 0x7f, 0xb1,
+	0x60, 0x4d, 0x45, 0xec, 0xa5, 0xcf, 0xb8, 0x43, 0x7f, 0x9b, 0x51, 0xc6, 0x85, 0x77, 0x81, 0x1f,
+	0xfa, 0x5c, 0x93, 0x50, 0x86, 0x68, 0x25, 0x67, 0x5c, 0x07, 0x7c, 0xe8, 0x28, 0x43, 0xc4, 0x94,
+	0xc5, 0x29, 0xd7, 0xbb, 0x2e, 0xff, 0x45, 0x1c, 0x92, 0x94, 0x9e, 0xf9, 0x6a, 0xdb, 0x87, 0x8e,
+	0xb6, 0xe6, 0xb2, 0xa4, 0x5b, 0xc9, 0x92, 0x2d, 0xe8, 0xc5, 0x51, 0xe0, 0x47, 0x54, 0xc6, 0x6e,
+	0xe0, 0x68, 0x0b, 0x87, 0xb0, 0x6e, 0x78, 0xff, 0x1f, 0x28, 0xe2, 0xc7, 0x30, 0x78, 0x15, 0x33,
+	0x5f, 0x52, 0x2a, 0xd3, 0xb5, 0xea, 0x92, 0xba, 0x3e, 0x6d, 0xf0, 0x4f, 0x61, 0xf9, 0x51, 0xc6,
+	0xcf, 0x1d, 0xca, 0x92, 0x38, 0x62, 0xb4, 0xb1, 0xa8, 0x36, 0xa0, 0xcb, 0xe3, 0x77, 0x34, 0x32,
+	0x8c, 0xa5, 0x81, 0x9f, 0xc3, 0xca, 0xeb, 0x94, 0x4c, 0x69, 0x60, 0xdc, 0xbd, 0xa2, 0x2e, 0xe7,
+	0x36, 0xbc, 0x44, 0x13, 0x7b, 0xb0, 0x6a, 0x80, 0x34, 0x99, 0xa2, 0x6e, 0xac, 0x86, 0xba, 0x11,
+	0x59, 0x94, 0xe8, 0x38, 0x54, 0xb2, 0xc8, 0x84, 0xc7, 0xc9, 0x07, 0xe0, 0x33, 0x58, 0x3a, 0x8a,
+	0xa7, 0xf4, 0x63, 0xc8, 0x36, 0x95, 0xdb, 0x27, 0xb0, 0x24, 0xb7, 0xf1, 0x84, 0x25, 0x94, 0x7a,
+	0x72, 0x63, 0x06, 0x0e, 0xc8, 0xa6, 0x63, 0xd1, 0x82, 0xff, 0xd0, 0x82, 0xe1, 0xaf, 0xfd, 0x38,
+	0x50, 0x5b, 0x50, 0x24, 0x7e, 0x47, 0x26, 0x7e, 0x79, 0xd9, 0x56, 0x43, 0x8c, 0xaa, 0xfa, 0xb4,
+	0x09, 0xbd, 0xb3, 0x34, 0x0e, 0x4f, 0x8c, 0x48, 0x75, 0x85, 0xf5, 0x4d, 0xde, 0x6c, 0xd4, 0x4a,
+	0x36, 0xbf, 0x45, 0x6b, 0xd0, 0xe1, 0xf1, 0xc9, 0x85, 0xae, 0xf0, 0x36, 0x8f, 0xbf, 0xd1, 0x4d,
+	0x33, 0xbb, 0x6f, 0x9a, 0xde, 0x8a, 0xf5, 0x3c, 0x9f, 0x71, 0x12, 0xb9, 0xd4, 0x1e, 0xec, 0x58,
+	0xbb, 0x96, 0x93, 0xdb, 0x42, 0x55, 0x48, 0x10, 0xc4, 0xef, 0xa9, 0x67, 0x0f, 0x65, 0x97, 0x31,
+	0x45, 0x8f, 0x1b, 0x90, 0x30, 0xa1, 0x9e, 0x0d, 0xd2, 0x79, 0x63, 0x8a, 0x14, 0xe6, 0x7e, 0x48,
+	0xed, 0xa5, 0x1d, 0x6b, 0xb7, 0xed, 0xc8, 0x7f, 0xfc, 0x4f, 0x0b, 0xe0, 0x51, 0xe6, 0xf9, 0xfc,
+	0x57, 0x19, 0x4d, 0x67, 0x32, 0xf7, 0x5d, 0x1e, 0xa7, 0x3a, 0xe4, 0xca, 0x10, 0x79, 0x4e, 0xdc,
+	0x52, 0x6a, 0x68, 0x4b, 0xb4, 0x73, 0x92, 0x4e, 0xa8, 0xa9, 0x0a, 0x6d, 0xa1, 0x5b, 0x00, 0xa9,
+	0xda, 0xc6, 0x13, 0xdf, 0xd3, 0xb5, 0x31, 0xd4, 0x2d, 0xbf, 0xf0, 0xc4, 0x22, 0xcc, 0x17, 0x4e,
+	0x75, 0x25, 0x11, 0x65, 0x88, 0xd6, 0x2c, 0xe2, 0x7e, 0x20, 0x83, 0xd2, 0x76, 0x94, 0x51, 0x94,
+	0x68, 0xbf, 0xb6, 0x44, 0x07, 0xa5, 0x12, 0xc5, 0xff, 0x35, 0xbe, 0x3c, 0x8b, 0x78, 0x3a, 0x5b,
+	0xd8, 0x5a, 0xe3, 0x7e, 0xab, 0x70, 0xbf, 0xf0, 0xb7, 0x5d, 0xf1, 0x97, 0xc5, 0x59, 0xea, 0x52,
+	0x53, 0xd7, 0xca, 0x92, 0x41, 0xf7, 0xbc, 0x94, 0x32, 0xa6, 0x95, 0xc7, 0x98, 0xa5, 0x08, 0xf5,
+	0x2e, 0x89, 0x50, 0xbf, 0x21, 0x42, 0x83, 0x6a, 0x84, 0xb6, 0xa0, 0x77, 0x4a, 0xcf, 0xe2, 0x94,
+	0xca, 0xcd, 0x1d, 0x3a, 0xda, 0x2a, 0xfc, 0x86, 0xb2, 0xdf, 0x13, 0xe8, 0x3e, 0x9b, 0xd2, 0x88,
+	0x0b, 0x0f, 0xdf, 0xf9, 0x91, 0xd1, 0x71, 0xf9, 0xdf, 0x54, 0xd8, 0xa2, 0x2f, 0x49, 0xe9, 0xd4,
+	0x8f, 0x33, 0x66, 0x12, 0xda, 0xd8, 0x79, 0xb4, 0x3a, 0xa5, 0x64, 0xb9, 0x05, 0xdd, 0xaf, 0x48,
+	0x48, 0x99, 0xe0, 0x21, 0x2a, 0x82, 0xd9, 0xd6, 0x4e, 0x5b, 0xf0, 0x90, 0x06, 0xee, 0x43, 0xf7,
+	0x59, 0x98, 0xf0, 0x19, 0xfe, 0x09, 0xac, 0x2a, 0x25, 0x38, 0x8e, 0x48, 0xc2, 0xce, 0x63, 0x8e,
+	0xee, 0x43, 0x5f, 0x69, 0x82, 0x9a, 0xb2, 0xa0, 0x18, 0xa6, 0x17, 0x7f, 0x07, 0x9b, 0xf3, 0x53,
+	0x1d, 0xca, 0xb8, 0x70, 0xfd, 0xc7, 0x30, 0x60, 0xba, 0x49, 0x8b, 0xce, 0xe6, 0x1c, 0x44, 0x3e,
+	0x3e, 0x1f, 0x26, 0x58, 0xba, 0x01, 0x25, 0x4a, 0xc8, 0x07, 0x8e, 0x32, 0x30, 0x85, 0x15, 0x73,
+	0x16, 0x1c, 0x73, 0x71, 0xf6, 0x3d, 0xa8, 0x28, 0x74, 0xd3, 0x59, 0x27, 0x1c, 0x09, 0x69, 0x78,
+	0x2a, 0x1c, 0x69, 0xd5, 0x3a, 0xa2, 0x7b, 0xf1, 0xcf, 0xe0, 0x46, 0xbe, 0x8c, 0x21, 0x74, 0x08,
+	0x43, 0x03, 0x64, 0xe2, 0xb0, 0x51, 0x59, 0x4a, 0x52, 0x72, 0x8a, 0x61, 0xd8, 0x83, 0x9b, 0x55,
+	0x1c, 0x13, 0x92, 0x2f, 0x16, 0x42, 0x72, 0xb3, 0x8a, 0xf6, 0xb1, 0x41, 0xf9, 0x87, 0x05, 0x2b,
+	0x1a, 0xd6, 0xa1, 0x89, 0x38, 0xdb, 0x84, 0x8c, 0xa4, 0x94, 0x70, 0xea, 0xe9, 0xd3, 0xd1, 0x98,
+	0xa2, 0x27, 0x93, 0xb7, 0x06, 0x4f, 0x6b, 0xb0, 0x31, 0x45, 0x3e, 0xa5, 0x0a, 0xc4, 0xd3, 0x82,
+	0x9c, 0xdb, 0x62, 0x96, 0x47, 0x03, 0xca, 0xb5, 0x26, 0x77, 0x1d, 0x63, 0xa2, 0x6d, 0x18, 0x9a,
+	0x43, 0x80, 0x69, 0x99, 0x2c, 0x1a, 0xc4, 0xbc, 0xd0, 0x67, 0xcc, 0x8f, 0x26, 0x76, 0x4f, 0x26,
+	0x9b, 0x31, 0xf1, 0x7b, 0xe8, 0xbf, 0x4a, 0xe3, 0xd3, 0x80, 0x86, 0xb5, 0x89, 0x7f, 0x03, 0xda,
+	0x2e, 0xbf, 0xd0, 0x39, 0x2f, 0x7e, 0x25, 0x14, 0x65, 0x8c, 0x4c, 0xa8, 0xce, 0x76, 0x63, 0x2a,
+	0x72, 0x9c, 0xf8, 0x01, 0x93, 0xe4, 0x96, 0x1d, 0x63, 0x0a, 0x64, 0x37, 0xf6, 0xa8, 0xae, 0x77,
+	0xf9, 0x2f, 0xae, 0xba, 0x83, 0xfc, 0x28, 0x14, 0x5a, 0xc1, 0x09, 0xcf, 0x98, 0x5e, 0x5c, 0x5b,
+	0x68, 0x4f, 0xd4, 0x96, 0x64, 0x67, 0x32, 0x65, 0xd5, 0x64, 0x8a, 0x6a, 0x76, 0xf2, 0x7e, 0xb1,
+	0x48, 0x44, 0x2f, 0xb8, 0x56, 0x1b, 0xf9, 0x8f, 0x76, 0xa1, 0xe3, 0x11, 0x4e, 0xe4, 0xc2, 0x22,
+	0x4d, 0xd4, 0xcd, 0x7b, 0xdf, 0xdc, 0xbc, 0xf7, 0x1f, 0x45, 0x33, 0x47, 0x8e, 0x78, 0xd1, 0x19,
+	0xb4, 0x6f, 0x74, 0xf0, 0x21, 0x74, 0xc4, 0xd5, 0x06, 0xed, 0x41, 0xd7, 0xe7, 0x34, 0x2c, 0xf2,
+	0xab, 0x6e, 0xa2, 0x1a, 0x82, 0x6d, 0xe8, 0xbc, 0xf2, 0xa3, 0x89, 0x08, 0x15, 0x23, 0x33, 0xed,
+	0x80, 0xf8, 0xc5, 0xbf, 0xb3, 0x60, 0xe5, 0x4d, 0x9c, 0x06, 0xde, 0xd3, 0xd8, 0xcd, 0x42, 0xa1,
+	0x2d, 0xa5, 0x6b, 0xac, 0xce, 0x07, 0x6d, 0xa2, 0x1f, 0x95, 0xb3, 0x5a, 0xb9, 0xba, 0x50, 0x40,
+	0xc5, 0x88, 0xb2, 0x14, 0xb4, 0x1b, 0xa5, 0xe0, 0x08, 0x96, 0x24, 0x85, 0x27, 0xe7, 0x24, 0x9a,
+	0xd0, 0xda, 0x3d, 0x36, 0x77, 0xf1, 0x56, 0xe9, 0x2e, 0x5e, 0x48, 0x71, 0xbb, 0x2c, 0xc5, 0xf8,
+	0xb5, 0x86, 0xd3, 0xf9, 0x7d, 0x13, 0xfa, 0x5e, 0x3a, 0x3b, 0x49, 0x33, 0xe5, 0xcf, 0xc0, 0xe9,
+	0x79, 0xe9, 0xcc, 0xc9, 0x22, 0xf4, 0x10, 0xfa, 0xae, 0x5c, 0xd1, 0x38, 0x83, 0x34, 0xbf, 0x12,
+	0x19, 0xc7, 0x0c, 0xc1, 0x7f, 0x36, 0x81, 0xca, 0x8b, 0xfc, 0xf2, 0x40, 0xd5, 0x1d, 0x40, 0x73,
+	0x92, 0xd0, 0xfe, 0x28, 0x49, 0x40, 0x9f, 0xc3, 0x80, 0xb8, 0x6e, 0x9c, 0x45, 0x5c, 0xa4, 0x6b,
+	0x4d, 0x08, 0xf3, 0x6e, 0xfc, 0x01, 0xd6, 0xe7, 0xd8, 0x69, 0xe7, 0x0f, 0x4a, 0x08, 0x96, 0x4e,
+	0x30, 0x85, 0x30, 0x27, 0x02, 0x05, 0xd0, 0x3c, 0xcf, 0x56, 0xc3, 0x94, 0x62, 0x18, 0xfe, 0xab,
+	0x05, 0x4b, 0x8f, 0x09, 0x77, 0xc5, 0x25, 0x36, 0x0b, 0xe4, 0x75, 0x39, 0xa4, 0xfc, 0x3c, 0x36,
+	0x7b, 0xa8, 0x2d, 0x11, 0x97, 0x84, 0xf0, 0x73, 0xb3, 0x8b, 0xe2, 0x5f, 0x5c, 0xe3, 0x54, 0x21,
+	0x9d, 0xc8, 0xf2, 0x53, 0x6a, 0x02, 0xaa, 0xe9, 0x49, 0xec, 0xc9, 0x92, 0x65, 0xef, 0xfc, 0x24,
+	0xc9, 0xef, 0x78, 0xc6, 0x14, 0x7a, 0x9e, 0xea, 0xea, 0xd4, 0xd5, 0x73, 0xbd, 0x60, 0x2a, 0x9b,
+	0x9d, 0x7c, 0xc0, 0xe1, 0x7f, 0x06, 0xd0, 0x57, 0x51, 0x63, 0xe8, 0x29, 0xf4, 0x9e, 0x48, 0x8d,
+	0x43, 0xf3, 0xf1, 0x1c, 0xcd, 0x9b, 0x78, 0xfb, 0xf7, 0xff, 0xfa, 0xf7, 0x9f, 0x5a, 0x5b, 0x78,
+	0x6d, 0x3c, 0x3d, 0x1c, 0x13, 0x2f, 0xf4, 0xa3, 0xb1, 0xce, 0xd9, 0x2f, 0xad, 0x3d, 0xf4, 0x02,
+	0xda, 0xcf, 0x29, 0xbf, 0x02, 0xe2, 0x9e, 0x84, 0xf8, 0x04, 0xdd, 0x12, 0x10, 0x6e, 0xe0, 0xd3,
+	0x88, 0x1b, 0x8c, 0xf1, 0x07, 0x73, 0xe1, 0xfc, 0x1e, 0x3d, 0x84, 0x8e, 0x78, 0x04, 0x54, 0xc1,
+	0xd6, 0xb5, 0x59, 0x7e, 0x20, 0xe0, 0x6b, 0x68, 0x0f, 0xfa, 0xcf, 0x29, 0x3f, 0x22, 0xd1, 0x0c,
+	0x2d, 0xeb, 0x11, 0xf2, 0xb8, 0xae, 0x2e, 0x7e, 0xed, 0xc0, 0x42, 0x47, 0x5a, 0x2e, 0xec, 0xb9,
+	0xae, 0xd2, 0xe3, 0xa8, 0x3a, 0x69, 0x24, 0x19, 0x6f, 0x20, 0xb4, 0xc8, 0xf8, 0xc0, 0x42, 0xaf,
+	0xa1, 0xa7, 0x5f, 0x92, 0xeb, 0x73, 0xd3, 0x54, 0x63, 0x15, 0xeb, 0x33, 0x89, 0xb5, 0x73, 0xb8,
+	0xb5, 0x10, 0xc0, 0xf1, 0x07, 0xdf, 0xfb, 0xfe, 0x4b, 0xf3, 0x7e, 0xa0, 0xd0, 0x53, 0x0f, 0x0f,
+	0x64, 0x72, 0x6d, 0xee, 0x41, 0x33, 0xda, 0xac, 0xb4, 0xea, 0x48, 0xec, 0x4b, 0xf8, 0x5d, 0x7c,
+	0xa7, 0x06, 0x3e, 0x8f, 0xed, 0x98, 0xcb, 0x49, 0x62, 0xc7, 0xde, 0x42, 0x47, 0xbc, 0x3c, 0x90,
+	0x29, 0xf4, 0xd2, 0x33, 0x64, 0x54, 0x7d, 0xb0, 0xe0, 0x87, 0x12, 0xfc, 0x33, 0xfc, 0x69, 0x23,
+	0x78, 0x18, 0x4f, 0xa9, 0x80, 0x7e, 0x09, 0xbd, 0xa7, 0xf2, 0x98, 0xbb, 0x22, 0x1f, 0xee, 0x4a,
+	0xd4, 0xdb, 0x7b, 0xdb, 0x4d, 0xa8, 0xe8, 0x6b, 0xe8, 0x9b, 0xb3, 0xbf, 0x19, 0xce, 0x90, 0xbc,
+	0xdb, 0x48, 0x52, 0x9f, 0xcd, 0xe8, 0x18, 0x06, 0xb9, 0x7a, 0x99, 0xc4, 0x91, 0x17, 0xb9, 0x51,
+	0xfd, 0x15, 0x0b, 0x63, 0x09, 0xbf, 0x8d, 0x46, 0x8b, 0xf0, 0xf9, 0x3d, 0x23, 0x80, 0xeb, 0x9a,
+	0x6b, 0x8e, 0xbd, 0x5d, 0x8b, 0xa6, 0x47, 0x8d, 0x6a, 0xe5, 0xc4, 0x14, 0x0a, 0x6e, 0x58, 0x4a,
+	0xc4, 0xf9, 0x3b, 0x80, 0xfc, 0x4d, 0xc7, 0xaa, 0xc1, 0xb9, 0xa1, 0xcd, 0x7c, 0x04, 0x1e, 0x4b,
+	0xd4, 0xcf, 0xd1, 0xfd, 0xc6, 0xf8, 0x4c, 0x73, 0xc4, 0x03, 0x0b, 0xfd, 0x1c, 0xba, 0xf2, 0x6d,
+	0x81, 0xd6, 0xf2, 0xe2, 0x33, 0xaf, 0xa6, 0xd1, 0x5c, 0x93, 0x7c, 0x7c, 0xe0, 0x9b, 0x72, 0x85,
+	0x35, 0x74, 0xbd, 0x58, 0x81, 0x88, 0xde, 0x03, 0xeb, 0xf0, 0xef, 0x7d, 0x18, 0xbe, 0xcc, 0xc5,
+	0xfc, 0x45, 0x2e, 0x3a, 0xd5, 0x43, 0x73, 0x54, 0x6d, 0xc0, 0xb7, 0x25, 0xa8, 0x8d, 0xd7, 0x0b,
+	0xd0, 0x5c, 0x6d, 0x55, 0xb6, 0x49, 0xe9, 0xb9, 0x1a, 0xe8, 0x8e, 0x04, 0xba, 0x85, 0x7e, 0x58,
+	0x2a, 0xe6, 0x1c, 0x69, 0xfc, 0x41, 0x65, 0xdb, 0xb1, 0x96, 0x88, 0x51, 0x65, 0x76, 0x59, 0x24,
+	0x16, 0x90, 0xb5, 0x36, 0xa2, 0x8d, 0x3a, 0xe4, 0x03, 0x0b, 0x7d, 0x0b, 0x4b, 0x62, 0xbe, 0x91,
+	0xdc, 0x05, 0xaa, 0x95, 0x44, 0x7e, 0x20, 0xe1, 0xee, 0xa1, 0x3b, 0x0d, 0x44, 0x4b, 0x32, 0xf4,
+	0xf0, 0x32, 0x05, 0x5c, 0xe0, 0x79, 0x4d, 0x8e, 0x1e, 0xfe, 0xd2, 0x75, 0xb3, 0x84, 0x88, 0x03,
+	0xee, 0x4a, 0xc5, 0xfc, 0x36, 0x97, 0xb8, 0xcd, 0x0a, 0x98, 0x16, 0xb9, 0x85, 0x35, 0xf6, 0x24,
+	0xf9, 0xbb, 0x87, 0x76, 0xcd, 0x76, 0x29, 0xa1, 0x2b, 0xde, 0x15, 0x5f, 0xe5, 0x42, 0x71, 0xf5,
+	0xee, 0xe9, 0xf2, 0xdb, 0x1b, 0xd5, 0xe2, 0xaa, 0xcd, 0x7b, 0x53, 0x48, 0xc5, 0xd5, 0x80, 0x9a,
+	0x28, 0xc6, 0x97, 0x03, 0xe6, 0x62, 0xf1, 0xe6, 0x52, 0xb1, 0xb8, 0xec, 0xf1, 0x61, 0xc4, 0x0d,
+	0x6d, 0xd7, 0xc1, 0xe7, 0x82, 0x91, 0x2c, 0x0a, 0xc6, 0xed, 0x4b, 0x10, 0x9b, 0x25, 0xe3, 0xbe,
+	0x5c, 0xee, 0x53, 0xdc, 0xb8, 0x9c, 0x28, 0x97, 0x5d, 0xe8, 0xc9, 0x77, 0x33, 0xab, 0x38, 0x92,
+	0x5b, 0xa2, 0x53, 0xec, 0xfd, 0xe3, 0x83, 0xdf, 0xec, 0x4f, 0x7c, 0x7e, 0x9e, 0x9d, 0xee, 0xbb,
+	0x71, 0x38, 0x76, 0x49, 0xca, 0xe2, 0x28, 0x94, 0x72, 0x71, 0x9a, 0x9d, 0x06, 0x24, 0x3d, 0x21,
+	0x8c, 0xf9, 0x93, 0x28, 0x94, 0x87, 0xa2, 0xbc, 0x68, 0xf7, 0xe4, 0xe7, 0x8b, 0xff, 0x0d, 0x00,
+	0xe7, 0xba, 0x14, 0xda, 0x6d, 0x17, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PlayersClient is the client API for Players service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PlayersClient interface {
+	Create(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error)
+	Get(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error)
+	Auth(ctx context.Context, in *Player, opts ...grpc.CallOption) (*AuthResponse, error)
+	// players which don't exist are left out
+	GetMany(ctx context.Context, in *Names, opts ...grpc.CallOption) (Players_GetManyClient, error)
+	List(ctx context.Context, in *PlayerListRequest, opts ...grpc.CallOption) (Players_ListClient, error)
+	Update(ctx context.Context, in *PlayerUpdate, opts ...grpc.CallOption) (*Player, error)
+	Travel(ctx context.Context, in *TravelRequest, opts ...grpc.CallOption) (*TravelResponse, error)
+	Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*Position, error)
+	Delete(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error)
+	Restore(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error)
+	Snapshot(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PlayerSnapshot, error)
+	RestoreSnapshot(ctx context.Context, in *PlayerSnapshotRestore, opts ...grpc.CallOption) (*RestoreReport, error)
+	// moves which broke the speed limit, newest first
+	Violations(ctx context.Context, in *Player, opts ...grpc.CallOption) (Players_ViolationsClient, error)
+	// changes made through either service, newest first
+	Audit(ctx context.Context, in *AuditQuery, opts ...grpc.CallOption) (Players_AuditClient, error)
+}
+
+type playersClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlayersClient(cc grpc.ClientConnInterface) PlayersClient {
+	return &playersClient{cc}
+}
+
+func (c *playersClient) Create(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error) {
+	out := new(Player)
+	err := c.cc.Invoke(ctx, "/proto.Players/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playersClient) Get(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error) {
+	out := new(Player)
+	err := c.cc.Invoke(ctx, "/proto.Players/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playersClient) Auth(ctx context.Context, in *Player, opts ...grpc.CallOption) (*AuthResponse, error) {
+	out := new(AuthResponse)
+	err := c.cc.Invoke(ctx, "/proto.Players/Auth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playersClient) GetMany(ctx context.Context, in *Names, opts ...grpc.CallOption) (Players_GetManyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Players_serviceDesc.Streams[0], "/proto.Players/GetMany", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &playersGetManyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Players_GetManyClient interface {
+	Recv() (*Player, error)
+	grpc.ClientStream
+}
+
+type playersGetManyClient struct {
+	grpc.ClientStream
+}
+
+func (x *playersGetManyClient) Recv() (*Player, error) {
+	m := new(Player)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *playersClient) List(ctx context.Context, in *PlayerListRequest, opts ...grpc.CallOption) (Players_ListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Players_serviceDesc.Streams[1], "/proto.Players/List", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &playersListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
 	}
 	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
@@ -706,16 +2410,116 @@ func (c *playersClient) Delete(ctx context.Context, in *Player, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *playersClient) Restore(ctx context.Context, in *Player, opts ...grpc.CallOption) (*Player, error) {
+	out := new(Player)
+	err := c.cc.Invoke(ctx, "/proto.Players/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playersClient) Snapshot(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PlayerSnapshot, error) {
+	out := new(PlayerSnapshot)
+	err := c.cc.Invoke(ctx, "/proto.Players/Snapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playersClient) RestoreSnapshot(ctx context.Context, in *PlayerSnapshotRestore, opts ...grpc.CallOption) (*RestoreReport, error) {
+	out := new(RestoreReport)
+	err := c.cc.Invoke(ctx, "/proto.Players/RestoreSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *playersClient) Violations(ctx context.Context, in *Player, opts ...grpc.CallOption) (Players_ViolationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Players_serviceDesc.Streams[2], "/proto.Players/Violations", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &playersViolationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Players_ViolationsClient interface {
+	Recv() (*Violation, error)
+	grpc.ClientStream
+}
+
+type playersViolationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *playersViolationsClient) Recv() (*Violation, error) {
+	m := new(Violation)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *playersClient) Audit(ctx context.Context, in *AuditQuery, opts ...grpc.CallOption) (Players_AuditClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Players_serviceDesc.Streams[3], "/proto.Players/Audit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &playersAuditClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Players_AuditClient interface {
+	Recv() (*AuditEntry, error)
+	grpc.ClientStream
+}
+
+type playersAuditClient struct {
+	grpc.ClientStream
+}
+
+func (x *playersAuditClient) Recv() (*AuditEntry, error) {
+	m := new(AuditEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PlayersServer is the server API for Players service.
 type PlayersServer interface {
 	Create(context.Context, *Player) (*Player, error)
 	Get(context.Context, *Player) (*Player, error)
 	Auth(context.Context, *Player) (*AuthResponse, error)
-	List(*Empty, Players_ListServer) error
+	// players which don't exist are left out
+	GetMany(*Names, Players_GetManyServer) error
+	List(*PlayerListRequest, Players_ListServer) error
 	Update(context.Context, *PlayerUpdate) (*Player, error)
 	Travel(context.Context, *TravelRequest) (*TravelResponse, error)
 	Move(context.Context, *MoveRequest) (*Position, error)
 	Delete(context.Context, *Player) (*Player, error)
+	Restore(context.Context, *Player) (*Player, error)
+	Snapshot(context.Context, *Empty) (*PlayerSnapshot, error)
+	RestoreSnapshot(context.Context, *PlayerSnapshotRestore) (*RestoreReport, error)
+	// moves which broke the speed limit, newest first
+	Violations(*Player, Players_ViolationsServer) error
+	// changes made through either service, newest first
+	Audit(*AuditQuery, Players_AuditServer) error
 }
 
 // UnimplementedPlayersServer can be embedded to have forward compatible implementations.
@@ -731,7 +2535,10 @@ func (*UnimplementedPlayersServer) Get(ctx context.Context, req *Player) (*Playe
 func (*UnimplementedPlayersServer) Auth(ctx context.Context, req *Player) (*AuthResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Auth not implemented")
 }
-func (*UnimplementedPlayersServer) List(req *Empty, srv Players_ListServer) error {
+func (*UnimplementedPlayersServer) GetMany(req *Names, srv Players_GetManyServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetMany not implemented")
+}
+func (*UnimplementedPlayersServer) List(req *PlayerListRequest, srv Players_ListServer) error {
 	return status.Errorf(codes.Unimplemented, "method List not implemented")
 }
 func (*UnimplementedPlayersServer) Update(ctx context.Context, req *PlayerUpdate) (*Player, error) {
@@ -746,6 +2553,21 @@ func (*UnimplementedPlayersServer) Move(ctx context.Context, req *MoveRequest) (
 func (*UnimplementedPlayersServer) Delete(ctx context.Context, req *Player) (*Player, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (*UnimplementedPlayersServer) Restore(ctx context.Context, req *Player) (*Player, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (*UnimplementedPlayersServer) Snapshot(ctx context.Context, req *Empty) (*PlayerSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (*UnimplementedPlayersServer) RestoreSnapshot(ctx context.Context, req *PlayerSnapshotRestore) (*RestoreReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSnapshot not implemented")
+}
+func (*UnimplementedPlayersServer) Violations(req *Player, srv Players_ViolationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Violations not implemented")
+}
+func (*UnimplementedPlayersServer) Audit(req *AuditQuery, srv Players_AuditServer) error {
+	return status.Errorf(codes.Unimplemented, "method Audit not implemented")
+}
 
 func RegisterPlayersServer(s *grpc.Server, srv PlayersServer) {
 	s.RegisterService(&_Players_serviceDesc, srv)
@@ -805,8 +2627,29 @@ func _Players_Auth_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Players_GetMany_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Names)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlayersServer).GetMany(m, &playersGetManyServer{stream})
+}
+
+type Players_GetManyServer interface {
+	Send(*Player) error
+	grpc.ServerStream
+}
+
+type playersGetManyServer struct {
+	grpc.ServerStream
+}
+
+func (x *playersGetManyServer) Send(m *Player) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Players_List_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Empty)
+	m := new(PlayerListRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -862,42 +2705,138 @@ func _Players_Travel_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Players_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MoveRequest)
+func _Players_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayersServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Players/Move",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayersServer).Move(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Players_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Player)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayersServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Players/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayersServer).Delete(ctx, req.(*Player))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Players_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Player)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlayersServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Players/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlayersServer).Restore(ctx, req.(*Player))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Players_Snapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PlayersServer).Move(ctx, in)
+		return srv.(PlayersServer).Snapshot(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Players/Move",
+		FullMethod: "/proto.Players/Snapshot",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PlayersServer).Move(ctx, req.(*MoveRequest))
+		return srv.(PlayersServer).Snapshot(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Players_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Player)
+func _Players_RestoreSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerSnapshotRestore)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PlayersServer).Delete(ctx, in)
+		return srv.(PlayersServer).RestoreSnapshot(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Players/Delete",
+		FullMethod: "/proto.Players/RestoreSnapshot",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PlayersServer).Delete(ctx, req.(*Player))
+		return srv.(PlayersServer).RestoreSnapshot(ctx, req.(*PlayerSnapshotRestore))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Players_Violations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Player)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlayersServer).Violations(m, &playersViolationsServer{stream})
+}
+
+type Players_ViolationsServer interface {
+	Send(*Violation) error
+	grpc.ServerStream
+}
+
+type playersViolationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *playersViolationsServer) Send(m *Violation) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Players_Audit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AuditQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PlayersServer).Audit(m, &playersAuditServer{stream})
+}
+
+type Players_AuditServer interface {
+	Send(*AuditEntry) error
+	grpc.ServerStream
+}
+
+type playersAuditServer struct {
+	grpc.ServerStream
+}
+
+func (x *playersAuditServer) Send(m *AuditEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Players_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.Players",
 	HandlerType: (*PlayersServer)(nil),
@@ -930,13 +2869,40 @@ var _Players_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _Players_Delete_Handler,
 		},
+		{
+			MethodName: "Restore",
+			Handler:    _Players_Restore_Handler,
+		},
+		{
+			MethodName: "Snapshot",
+			Handler:    _Players_Snapshot_Handler,
+		},
+		{
+			MethodName: "RestoreSnapshot",
+			Handler:    _Players_RestoreSnapshot_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetMany",
+			Handler:       _Players_GetMany_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "List",
 			Handler:       _Players_List_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Violations",
+			Handler:       _Players_Violations_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Audit",
+			Handler:       _Players_Audit_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/services.proto",
 }
@@ -947,10 +2913,18 @@ var _Players_serviceDesc = grpc.ServiceDesc{
 type LocationsClient interface {
 	Create(ctx context.Context, in *Location, opts ...grpc.CallOption) (*Location, error)
 	Get(ctx context.Context, in *Location, opts ...grpc.CallOption) (*Location, error)
-	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Locations_ListClient, error)
+	List(ctx context.Context, in *LocationListRequest, opts ...grpc.CallOption) (Locations_ListClient, error)
 	ListPlayers(ctx context.Context, in *Location, opts ...grpc.CallOption) (Locations_ListPlayersClient, error)
+	// locations which don't exist are left out
+	GetMany(ctx context.Context, in *Names, opts ...grpc.CallOption) (Locations_GetManyClient, error)
+	// the players in each of the locations, with their location set
+	Occupants(ctx context.Context, in *Names, opts ...grpc.CallOption) (Locations_OccupantsClient, error)
 	Update(ctx context.Context, in *LocationUpdate, opts ...grpc.CallOption) (*Location, error)
 	Delete(ctx context.Context, in *Location, opts ...grpc.CallOption) (*Location, error)
+	Restore(ctx context.Context, in *Location, opts ...grpc.CallOption) (*Location, error)
+	Snapshot(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LocationSnapshot, error)
+	RestoreSnapshot(ctx context.Context, in *LocationSnapshotRestore, opts ...grpc.CallOption) (*RestoreReport, error)
+	Events(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Locations_EventsClient, error)
 }
 
 type locationsClient struct {
@@ -979,7 +2953,7 @@ func (c *locationsClient) Get(ctx context.Context, in *Location, opts ...grpc.Ca
 	return out, nil
 }
 
-func (c *locationsClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Locations_ListClient, error) {
+func (c *locationsClient) List(ctx context.Context, in *LocationListRequest, opts ...grpc.CallOption) (Locations_ListClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_Locations_serviceDesc.Streams[0], "/proto.Locations/List", opts...)
 	if err != nil {
 		return nil, err
@@ -1043,6 +3017,70 @@ func (x *locationsListPlayersClient) Recv() (*Player, error) {
 	return m, nil
 }
 
+func (c *locationsClient) GetMany(ctx context.Context, in *Names, opts ...grpc.CallOption) (Locations_GetManyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Locations_serviceDesc.Streams[2], "/proto.Locations/GetMany", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &locationsGetManyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Locations_GetManyClient interface {
+	Recv() (*Location, error)
+	grpc.ClientStream
+}
+
+type locationsGetManyClient struct {
+	grpc.ClientStream
+}
+
+func (x *locationsGetManyClient) Recv() (*Location, error) {
+	m := new(Location)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *locationsClient) Occupants(ctx context.Context, in *Names, opts ...grpc.CallOption) (Locations_OccupantsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Locations_serviceDesc.Streams[3], "/proto.Locations/Occupants", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &locationsOccupantsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Locations_OccupantsClient interface {
+	Recv() (*Player, error)
+	grpc.ClientStream
+}
+
+type locationsOccupantsClient struct {
+	grpc.ClientStream
+}
+
+func (x *locationsOccupantsClient) Recv() (*Player, error) {
+	m := new(Player)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *locationsClient) Update(ctx context.Context, in *LocationUpdate, opts ...grpc.CallOption) (*Location, error) {
 	out := new(Location)
 	err := c.cc.Invoke(ctx, "/proto.Locations/Update", in, out, opts...)
@@ -1061,14 +3099,81 @@ func (c *locationsClient) Delete(ctx context.Context, in *Location, opts ...grpc
 	return out, nil
 }
 
+func (c *locationsClient) Restore(ctx context.Context, in *Location, opts ...grpc.CallOption) (*Location, error) {
+	out := new(Location)
+	err := c.cc.Invoke(ctx, "/proto.Locations/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationsClient) Snapshot(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*LocationSnapshot, error) {
+	out := new(LocationSnapshot)
+	err := c.cc.Invoke(ctx, "/proto.Locations/Snapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationsClient) RestoreSnapshot(ctx context.Context, in *LocationSnapshotRestore, opts ...grpc.CallOption) (*RestoreReport, error) {
+	out := new(RestoreReport)
+	err := c.cc.Invoke(ctx, "/proto.Locations/RestoreSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationsClient) Events(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Locations_EventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Locations_serviceDesc.Streams[4], "/proto.Locations/Events", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &locationsEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Locations_EventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type locationsEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *locationsEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // LocationsServer is the server API for Locations service.
 type LocationsServer interface {
 	Create(context.Context, *Location) (*Location, error)
 	Get(context.Context, *Location) (*Location, error)
-	List(*Empty, Locations_ListServer) error
+	List(*LocationListRequest, Locations_ListServer) error
 	ListPlayers(*Location, Locations_ListPlayersServer) error
+	// locations which don't exist are left out
+	GetMany(*Names, Locations_GetManyServer) error
+	// the players in each of the locations, with their location set
+	Occupants(*Names, Locations_OccupantsServer) error
 	Update(context.Context, *LocationUpdate) (*Location, error)
 	Delete(context.Context, *Location) (*Location, error)
+	Restore(context.Context, *Location) (*Location, error)
+	Snapshot(context.Context, *Empty) (*LocationSnapshot, error)
+	RestoreSnapshot(context.Context, *LocationSnapshotRestore) (*RestoreReport, error)
+	Events(*Empty, Locations_EventsServer) error
 }
 
 // UnimplementedLocationsServer can be embedded to have forward compatible implementations.
@@ -1081,18 +3186,36 @@ func (*UnimplementedLocationsServer) Create(ctx context.Context, req *Location)
 func (*UnimplementedLocationsServer) Get(ctx context.Context, req *Location) (*Location, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
-func (*UnimplementedLocationsServer) List(req *Empty, srv Locations_ListServer) error {
+func (*UnimplementedLocationsServer) List(req *LocationListRequest, srv Locations_ListServer) error {
 	return status.Errorf(codes.Unimplemented, "method List not implemented")
 }
 func (*UnimplementedLocationsServer) ListPlayers(req *Location, srv Locations_ListPlayersServer) error {
 	return status.Errorf(codes.Unimplemented, "method ListPlayers not implemented")
 }
+func (*UnimplementedLocationsServer) GetMany(req *Names, srv Locations_GetManyServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetMany not implemented")
+}
+func (*UnimplementedLocationsServer) Occupants(req *Names, srv Locations_OccupantsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Occupants not implemented")
+}
 func (*UnimplementedLocationsServer) Update(ctx context.Context, req *LocationUpdate) (*Location, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
 }
 func (*UnimplementedLocationsServer) Delete(ctx context.Context, req *Location) (*Location, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (*UnimplementedLocationsServer) Restore(ctx context.Context, req *Location) (*Location, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (*UnimplementedLocationsServer) Snapshot(ctx context.Context, req *Empty) (*LocationSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (*UnimplementedLocationsServer) RestoreSnapshot(ctx context.Context, req *LocationSnapshotRestore) (*RestoreReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSnapshot not implemented")
+}
+func (*UnimplementedLocationsServer) Events(req *Empty, srv Locations_EventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Events not implemented")
+}
 
 func RegisterLocationsServer(s *grpc.Server, srv LocationsServer) {
 	s.RegisterService(&_Locations_serviceDesc, srv)
@@ -1135,7 +3258,7 @@ func _Locations_Get_Handler(srv interface{}, ctx context.Context, dec func(inter
 }
 
 func _Locations_List_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Empty)
+	m := new(LocationListRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -1176,6 +3299,48 @@ func (x *locationsListPlayersServer) Send(m *Player) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Locations_GetMany_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Names)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LocationsServer).GetMany(m, &locationsGetManyServer{stream})
+}
+
+type Locations_GetManyServer interface {
+	Send(*Location) error
+	grpc.ServerStream
+}
+
+type locationsGetManyServer struct {
+	grpc.ServerStream
+}
+
+func (x *locationsGetManyServer) Send(m *Location) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Locations_Occupants_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Names)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LocationsServer).Occupants(m, &locationsOccupantsServer{stream})
+}
+
+type Locations_OccupantsServer interface {
+	Send(*Player) error
+	grpc.ServerStream
+}
+
+type locationsOccupantsServer struct {
+	grpc.ServerStream
+}
+
+func (x *locationsOccupantsServer) Send(m *Player) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Locations_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LocationUpdate)
 	if err := dec(in); err != nil {
@@ -1212,6 +3377,81 @@ func _Locations_Delete_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Locations_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Location)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationsServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Locations/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationsServer).Restore(ctx, req.(*Location))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Locations_Snapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationsServer).Snapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Locations/Snapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationsServer).Snapshot(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Locations_RestoreSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocationSnapshotRestore)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationsServer).RestoreSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Locations/RestoreSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationsServer).RestoreSnapshot(ctx, req.(*LocationSnapshotRestore))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Locations_Events_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LocationsServer).Events(m, &locationsEventsServer{stream})
+}
+
+type Locations_EventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type locationsEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *locationsEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Locations_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.Locations",
 	HandlerType: (*LocationsServer)(nil),
@@ -1232,6 +3472,18 @@ var _Locations_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _Locations_Delete_Handler,
 		},
+		{
+			MethodName: "Restore",
+			Handler:    _Locations_Restore_Handler,
+		},
+		{
+			MethodName: "Snapshot",
+			Handler:    _Locations_Snapshot_Handler,
+		},
+		{
+			MethodName: "RestoreSnapshot",
+			Handler:    _Locations_RestoreSnapshot_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1244,6 +3496,21 @@ var _Locations_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Locations_ListPlayers_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetMany",
+			Handler:       _Locations_GetMany_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Occupants",
+			Handler:       _Locations_Occupants_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Events",
+			Handler:       _Locations_Events_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/services.proto",
 }
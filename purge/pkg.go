@@ -0,0 +1,49 @@
+package purge
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/logger"
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+// Func - permanently remove records deleted before a time, returning how many
+// were removed
+type Func func(before time.Time) (int, error)
+
+// Deleted - permanently remove the records of a kind (like "players") which
+// were deleted longer ago than the configured retention period
+func Deleted(kind string, purge Func) (int, error) {
+	before := time.Now().Add(-configure.GetStorage().Retention)
+	purged, err := purge(before)
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		log.Info("Purged deleted "+kind, zap.Int(kind, purged), zap.Time("before", before))
+	}
+
+	return purged, nil
+}
+
+// Start - purge deleted records of a kind in the background at the configured
+// interval. Purging is disabled if the interval is not positive.
+func Start(kind string, purge Func) {
+	interval := configure.GetStorage().PurgeInterval
+	if interval <= 0 {
+		log.Warn("Purging deleted " + kind + " is disabled")
+		return
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			if _, err := Deleted(kind, purge); err != nil {
+				log.Error("Failed to purge deleted "+kind, zap.Error(err))
+			}
+		}
+	}()
+}
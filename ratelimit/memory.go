@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// purgeInterval - how often expired counters and lockouts are removed from a
+// memory store
+const purgeInterval = time.Minute
+
+type memoryCounter struct {
+	count   uint64
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*memoryCounter
+	locks     map[string]time.Time
+	nextPurge time.Time
+}
+
+// NewMemoryStore - create a store which keeps limits within this process
+func NewMemoryStore() Store {
+	return &memoryStore{
+		counters:  make(map[string]*memoryCounter),
+		locks:     make(map[string]time.Time),
+		nextPurge: time.Now().Add(purgeInterval),
+	}
+}
+
+func (s *memoryStore) Incr(key string, window time.Duration) (uint64, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextPurge) {
+		s.purge(now)
+	}
+
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.expires) {
+		counter = &memoryCounter{expires: now.Add(window)}
+		s.counters[key] = counter
+	}
+
+	counter.count++
+	return counter.count, counter.expires.Sub(now), nil
+}
+
+func (s *memoryStore) Lock(key string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locks[key] = time.Now().Add(d)
+	return nil
+}
+
+func (s *memoryStore) Locked(key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if remaining := time.Until(s.locks[key]); remaining > 0 {
+		return remaining, nil
+	}
+
+	return 0, nil
+}
+
+func (s *memoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	return nil
+}
+
+func (s *memoryStore) Rename(from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if counter, ok := s.counters[from]; ok {
+		s.counters[to] = counter
+	} else {
+		delete(s.counters, to)
+	}
+
+	if expires, ok := s.locks[from]; ok {
+		s.locks[to] = expires
+	} else {
+		delete(s.locks, to)
+	}
+
+	delete(s.counters, from)
+	delete(s.locks, from)
+	return nil
+}
+
+// purge - remove expired counters and lockouts. The lock must be held.
+func (s *memoryStore) purge(now time.Time) {
+	for key, counter := range s.counters {
+		if !now.Before(counter.expires) {
+			delete(s.counters, key)
+		}
+	}
+
+	for key, expires := range s.locks {
+		if !now.Before(expires) {
+			delete(s.locks, key)
+		}
+	}
+
+	s.nextPurge = now.Add(purgeInterval)
+}
@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/configure"
+	"github.com/carsonmyers/bublar-assignment/logger"
+)
+
+var log = logger.GetLogger()
+
+// Store - keeps the counters and lockouts of rate limits
+type Store interface {
+	// Incr - count an event, returning the count and how long until it
+	// resets. A counter resets a window after its first event.
+	Incr(key string, window time.Duration) (uint64, time.Duration, error)
+
+	// Lock - lock out a key for a while
+	Lock(key string, d time.Duration) error
+
+	// Locked - how much longer a key is locked out, or 0 if it isn't
+	Locked(key string) (time.Duration, error)
+
+	// Reset - remove the counter of a key
+	Reset(key string) error
+
+	// Rename - move the counter and lockout of a key to another key,
+	// replacing any the other key had
+	Rename(from, to string) error
+}
+
+var store Store
+
+// SetStore - override the configured store
+func SetStore(s Store) {
+	store = s
+}
+
+// GetStore - get the configured store
+func GetStore() Store {
+	if store != nil {
+		return store
+	}
+
+	// as with events, a standalone process without redis keeps its limits in
+	// memory
+	switch configure.GetStorage().Backend {
+	case configure.BackendMemory, configure.BackendSQLite:
+		store = NewMemoryStore()
+	default:
+		store = NewRedisStore()
+	}
+
+	return store
+}
+
+// Allow - count a request against a limit of some number per window. If the
+// limit was exceeded, the time until the window resets is returned.
+func Allow(key string, limit uint, window time.Duration) (time.Duration, error) {
+	count, remaining, err := GetStore().Incr("rate/"+key, window)
+	if err != nil {
+		return 0, err
+	}
+
+	if count > uint64(limit) {
+		return remaining, nil
+	}
+
+	return 0, nil
+}
+
+// Locked - how much longer a key is locked out after failing too often
+func Locked(key string) (time.Duration, error) {
+	return GetStore().Locked(key)
+}
+
+// Fail - count a failure (like a wrong password) of a key, locking it out
+// once it has failed too many times. Each failure after the threshold doubles
+// the lockout, up to the configured maximum.
+func Fail(key string) (time.Duration, error) {
+	conf := configure.GetRateLimit()
+	if conf.LockoutThreshold == 0 {
+		return 0, nil
+	}
+
+	count, _, err := GetStore().Incr("fail/"+key, conf.FailureWindow)
+	if err != nil {
+		return 0, err
+	}
+
+	if count < uint64(conf.LockoutThreshold) {
+		return 0, nil
+	}
+
+	lockout := conf.LockoutMax
+	if doublings := count - uint64(conf.LockoutThreshold); doublings < 32 {
+		if d := conf.LockoutBase << doublings; d > 0 && d < lockout {
+			lockout = d
+		}
+	}
+
+	if err := GetStore().Lock(key, lockout); err != nil {
+		return 0, err
+	}
+
+	return lockout, nil
+}
+
+// Succeed - forget the failures of a key
+func Succeed(key string) error {
+	return GetStore().Reset("fail/" + key)
+}
+
+// LoginKey - the key failed logins to an account are counted under
+func LoginKey(username string) string {
+	return "login/user/" + username
+}
+
+// RenamePlayer - carry the failed logins and gameplay limit of a player over
+// to their new username, so renaming an account doesn't reset its limits
+func RenamePlayer(from, to string) error {
+	renames := [][2]string{
+		{LoginKey(from), LoginKey(to)},
+		{"fail/" + LoginKey(from), "fail/" + LoginKey(to)},
+		{"rate/gameplay/" + from, "rate/gameplay/" + to},
+	}
+
+	for _, rename := range renames {
+		if err := GetStore().Rename(rename[0], rename[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Gameplay - count a gameplay request by a player against the configured
+// limit, returning how long they must wait if they exceeded it
+func Gameplay(username string) (time.Duration, error) {
+	conf := configure.GetRateLimit()
+	if conf.GameplayRate == 0 {
+		return 0, nil
+	}
+
+	return Allow("gameplay/"+username, conf.GameplayRate, conf.GameplayWindow)
+}
@@ -0,0 +1,209 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/carsonmyers/bublar-assignment/configure"
+)
+
+// stores - the stores which can be tested without a real redis server, each
+// with a way to let time pass for it
+var stores = []struct {
+	name  string
+	store func(t *testing.T) (Store, func(time.Duration))
+}{
+	{"memory", func(t *testing.T) (Store, func(time.Duration)) {
+		return NewMemoryStore(), time.Sleep
+	}},
+	{"redis", func(t *testing.T) (Store, func(time.Duration)) {
+		srv, err := miniredis.Run()
+		if err != nil {
+			t.Fatalf("starting miniredis: %v", err)
+		}
+		t.Cleanup(srv.Close)
+
+		configure.Redis(&configure.RedisConfig{
+			Host: srv.Host(),
+			Port: uint(srv.Server().Addr().Port),
+		})
+
+		return NewRedisStore(), srv.FastForward
+	}},
+}
+
+// useStore - use a store and rate limit configuration for the length of a test
+func useStore(t *testing.T, store Store, config *configure.RateLimitConfig) {
+	SetStore(store)
+	configure.RateLimit(config)
+	t.Cleanup(func() {
+		SetStore(nil)
+		configure.RateLimit(nil)
+	})
+}
+
+const (
+	base   = 40 * time.Millisecond
+	window = 400 * time.Millisecond
+)
+
+var lockoutConfig = &configure.RateLimitConfig{
+	LockoutThreshold: 3,
+	LockoutBase:      base,
+	LockoutMax:       4 * base,
+	FailureWindow:    window,
+}
+
+// TestLockout - a key is locked out once it reaches the threshold, for twice
+// as long with each further failure up to the maximum, until the lockout
+// expires
+func TestLockout(t *testing.T) {
+	cases := []struct {
+		failure uint
+		want    time.Duration
+	}{
+		{1, 0},
+		{2, 0},
+		{3, base},
+		{4, 2 * base},
+		{5, 4 * base},
+		{6, 4 * base},
+	}
+
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store, _ := s.store(t)
+			useStore(t, store, lockoutConfig)
+
+			for _, c := range cases {
+				lockout, err := Fail("alice")
+				if err != nil {
+					t.Fatalf("Fail: %v", err)
+				}
+
+				if lockout != c.want {
+					t.Errorf("failure %d locked out for %v, want %v", c.failure, lockout, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLockoutExpiry(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store, advance := s.store(t)
+			useStore(t, store, lockoutConfig)
+
+			for i := uint(0); i < lockoutConfig.LockoutThreshold; i++ {
+				if _, err := Fail("alice"); err != nil {
+					t.Fatalf("Fail: %v", err)
+				}
+			}
+
+			locked, err := Locked("alice")
+			if err != nil || locked <= 0 || locked > base {
+				t.Fatalf("locked out for %v (%v), want up to %v", locked, err, base)
+			}
+
+			if locked, err := Locked("bob"); err != nil || locked != 0 {
+				t.Errorf("another key is locked out for %v (%v), want 0", locked, err)
+			}
+
+			advance(base + 10*time.Millisecond)
+
+			if locked, err := Locked("alice"); err != nil || locked != 0 {
+				t.Errorf("after the lockout, locked out for %v (%v), want 0", locked, err)
+			}
+
+			// the failures still count until their window ends, so the next
+			// one locks the key out again, for longer
+			if lockout, err := Fail("alice"); err != nil || lockout != 2*base {
+				t.Errorf("failing again locked out for %v (%v), want %v", lockout, err, 2*base)
+			}
+
+			advance(window)
+
+			if lockout, err := Fail("alice"); err != nil || lockout != 0 {
+				t.Errorf("failing after the window locked out for %v (%v), want 0", lockout, err)
+			}
+		})
+	}
+}
+
+func TestSucceed(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store, _ := s.store(t)
+			useStore(t, store, lockoutConfig)
+
+			for i := uint(1); i < lockoutConfig.LockoutThreshold; i++ {
+				if _, err := Fail("alice"); err != nil {
+					t.Fatalf("Fail: %v", err)
+				}
+			}
+
+			if err := Succeed("alice"); err != nil {
+				t.Fatalf("Succeed: %v", err)
+			}
+
+			if lockout, err := Fail("alice"); err != nil || lockout != 0 {
+				t.Errorf("failing after a success locked out for %v (%v), want 0", lockout, err)
+			}
+		})
+	}
+}
+
+func TestAllow(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store, advance := s.store(t)
+			useStore(t, store, lockoutConfig)
+
+			for i := 1; i <= 3; i++ {
+				if retry, err := Allow("bob", 2, window); err != nil || (retry > 0) != (i > 2) {
+					t.Errorf("request %d: retry after %v (%v)", i, retry, err)
+				}
+			}
+
+			advance(window + 10*time.Millisecond)
+
+			if retry, err := Allow("bob", 2, window); err != nil || retry != 0 {
+				t.Errorf("after the window: retry after %v (%v), want 0", retry, err)
+			}
+		})
+	}
+}
+
+// TestRenamePlayer - a renamed player keeps their lockout and failures
+func TestRenamePlayer(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store, _ := s.store(t)
+			useStore(t, store, lockoutConfig)
+
+			for i := uint(0); i < lockoutConfig.LockoutThreshold; i++ {
+				if _, err := Fail(LoginKey("alice")); err != nil {
+					t.Fatalf("Fail: %v", err)
+				}
+			}
+
+			if err := RenamePlayer("alice", "alicia"); err != nil {
+				t.Fatalf("RenamePlayer: %v", err)
+			}
+
+			if locked, err := Locked(LoginKey("alice")); err != nil || locked != 0 {
+				t.Errorf("old username locked out for %v (%v), want 0", locked, err)
+			}
+
+			if locked, err := Locked(LoginKey("alicia")); err != nil || locked <= 0 {
+				t.Errorf("new username locked out for %v (%v), want the lockout", locked, err)
+			}
+
+			if lockout, err := Fail(LoginKey("alicia")); err != nil || lockout != 2*base {
+				t.Errorf("failing after the rename locked out for %v (%v), want %v", lockout, err, 2*base)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+const (
+	counterPrefix = "ratelimit:count:"
+	lockPrefix    = "ratelimit:lock:"
+)
+
+// incr - increment a counter, starting its window on the first increment, in
+// one step so a counter can't be left without an expiry
+var incr = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// rename - move each key to the one after it, keeping its expiry, or remove
+// the one after it if the key doesn't exist
+var rename = redis.NewScript(`
+for i = 1, #KEYS, 2 do
+	if redis.call("EXISTS", KEYS[i]) == 1 then
+		redis.call("RENAME", KEYS[i], KEYS[i + 1])
+	else
+		redis.call("DEL", KEYS[i + 1])
+	end
+end
+return 0
+`)
+
+type redisStore struct{}
+
+// NewRedisStore - create a store shared by every API process through redis
+func NewRedisStore() Store {
+	return &redisStore{}
+}
+
+func (s *redisStore) Incr(key string, window time.Duration) (uint64, time.Duration, error) {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return 0, 0, errors.EDatabaseConnection.NewError(err)
+	}
+
+	res, err := incr.Run(rdb, []string{counterPrefix + key}, window.Milliseconds()).Result()
+	if err != nil {
+		log.Error("Failed to count request", zap.String("key", key), zap.Error(err))
+		return 0, 0, errors.EDatabase.NewError(err)
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, 0, errors.EDatabase.NewErrorf("unexpected counter result %v", res)
+	}
+
+	count, _ := values[0].(int64)
+	ttl, _ := values[1].(int64)
+	return uint64(count), time.Duration(ttl) * time.Millisecond, nil
+}
+
+func (s *redisStore) Lock(key string, d time.Duration) error {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	if err := rdb.Set(lockPrefix+key, 1, d).Err(); err != nil {
+		log.Error("Failed to lock out key", zap.String("key", key), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *redisStore) Locked(key string) (time.Duration, error) {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return 0, errors.EDatabaseConnection.NewError(err)
+	}
+
+	// a missing key has a negative TTL
+	ttl, err := rdb.PTTL(lockPrefix + key).Result()
+	if err != nil {
+		log.Error("Failed to check lockout", zap.String("key", key), zap.Error(err))
+		return 0, errors.EDatabase.NewError(err)
+	}
+
+	if ttl > 0 {
+		return ttl, nil
+	}
+
+	return 0, nil
+}
+
+func (s *redisStore) Reset(key string) error {
+	rdb, err := connect.Redis()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	if err := rdb.Del(counterPrefix + key).Err(); err != nil {
+		log.Error("Failed to reset counter", zap.String("key", key), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
+
+func (s *redisStore) Rename(from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	rdb, err := connect.Redis()
+	if err != nil {
+		return errors.EDatabaseConnection.NewError(err)
+	}
+
+	keys := []string{counterPrefix + from, counterPrefix + to, lockPrefix + from, lockPrefix + to}
+	if err := rename.Run(rdb, keys).Err(); err != nil {
+		log.Error("Failed to rename limits", zap.String("from", from), zap.String("to", to), zap.Error(err))
+		return errors.EDatabase.NewError(err)
+	}
+
+	return nil
+}
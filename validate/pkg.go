@@ -0,0 +1,155 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+)
+
+// Rule - check a field, returning why it is invalid, or "" if it is valid
+type Rule func(value string) string
+
+// Required - the field must not be empty
+func Required(value string) string {
+	if len(value) == 0 {
+		return "is required"
+	}
+
+	return ""
+}
+
+// Length - the field must have between min and max characters
+func Length(min, max int) Rule {
+	return func(value string) string {
+		if n := utf8.RuneCountInString(value); n < min || n > max {
+			return fmt.Sprintf("must be between %d and %d characters", min, max)
+		}
+
+		return ""
+	}
+}
+
+// Pattern - the field must match a regular expression, which is described to
+// the client if it doesn't
+func Pattern(pattern, description string) Rule {
+	re := regexp.MustCompile(pattern)
+	return func(value string) string {
+		if !re.MatchString(value) {
+			return description
+		}
+
+		return ""
+	}
+}
+
+// Reserved - the field may not be any of these words, ignoring case
+func Reserved(words ...string) Rule {
+	return func(value string) string {
+		for _, word := range words {
+			if strings.EqualFold(value, word) {
+				return "is reserved"
+			}
+		}
+
+		return ""
+	}
+}
+
+var (
+	// Username - rules for the usernames of new or renamed players
+	Username = []Rule{
+		Required,
+		Length(3, 32),
+		Pattern(`^[A-Za-z0-9_-]*$`, "may only contain letters, digits, _ and -"),
+		Reserved("admin", "administrator", "moderator", "root", "system"),
+	}
+
+	// Password - rules for player passwords
+	Password = []Rule{
+		Required,
+		Length(1, 256),
+	}
+
+	// LocationName - rules for the names of new or renamed locations
+	LocationName = []Rule{
+		Required,
+		Length(1, 64),
+		Pattern(`^[A-Za-z0-9_.-]*$`, "may only contain letters, digits, _, . and -"),
+	}
+)
+
+// Validator - collects the problems with the fields of a request
+type Validator struct {
+	problems []*errors.Error
+}
+
+// New - start validating a request
+func New() *Validator {
+	return &Validator{
+		problems: make([]*errors.Error, 0),
+	}
+}
+
+// Check - apply rules to a field, in order, until one of them fails. The
+// field's name is used as the context of the problem.
+func (v *Validator) Check(field, value string, rules ...Rule) *Validator {
+	for _, rule := range rules {
+		if reason := rule(value); len(reason) > 0 {
+			v.problems = append(v.problems, errors.EInvalidRequest.NewErrorf("%s %s", field, reason).WithContext(field))
+			break
+		}
+	}
+
+	return v
+}
+
+// Layout - check that a location's size is not negative and its blocked
+// tiles are within it. The fields are named after prefix, if it isn't empty.
+func (v *Validator) Layout(prefix string, location *data.Location) *Validator {
+	if len(prefix) > 0 {
+		prefix += "."
+	}
+
+	if location.Width < 0 {
+		v.problems = append(v.problems, errors.EInvalidRequest.NewErrorf("width must not be negative").WithContext(prefix+"width"))
+	}
+
+	if location.Height < 0 {
+		v.problems = append(v.problems, errors.EInvalidRequest.NewErrorf("height must not be negative").WithContext(prefix+"height"))
+	}
+
+	for i, tile := range location.Blocked {
+		outside := tile.X < 0 || tile.Y < 0 ||
+			(location.Width > 0 && tile.X >= location.Width) ||
+			(location.Height > 0 && tile.Y >= location.Height)
+		if outside {
+			v.problems = append(v.problems, errors.EInvalidRequest.NewErrorf("blocked tile %d,%d is outside of the location", tile.X, tile.Y).WithContext(fmt.Sprintf("%sblocked[%d]", prefix, i)))
+		}
+	}
+
+	return v
+}
+
+// Add - record a problem found outside of the field rules
+func (v *Validator) Add(problem *errors.Error) *Validator {
+	v.problems = append(v.problems, problem)
+	return v
+}
+
+// Problems - every problem found so far
+func (v *Validator) Problems() []*errors.Error {
+	return v.problems
+}
+
+// Err - the first problem found, or nil if there were none
+func (v *Validator) Err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	return v.problems[0]
+}
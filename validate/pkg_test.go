@@ -0,0 +1,108 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+)
+
+func TestRules(t *testing.T) {
+	cases := []struct {
+		name  string
+		rules []Rule
+		value string
+		want  string
+	}{
+		{"username", Username, "bob_the-3rd", ""},
+		{"username missing", Username, "", "is required"},
+		{"username too short", Username, "bo", "must be between 3 and 32 characters"},
+		{"username too long", Username, strings.Repeat("b", 33), "must be between 3 and 32 characters"},
+		{"username longest", Username, strings.Repeat("b", 32), ""},
+		{"username with spaces", Username, "bob smith", "may only contain letters, digits, _ and -"},
+		{"username with colon", Username, "bob:1", "may only contain letters, digits, _ and -"},
+		{"username reserved", Username, "Admin", "is reserved"},
+		{"password", Password, "hunter2", ""},
+		{"password missing", Password, "", "is required"},
+		{"password counted in characters", Password, strings.Repeat("é", 256), ""},
+		{"password too long", Password, strings.Repeat("p", 257), "must be between 1 and 256 characters"},
+		{"location name", LocationName, "level-1.east_wing", ""},
+		{"location name missing", LocationName, "", "is required"},
+		{"location name too long", LocationName, strings.Repeat("l", 65), "must be between 1 and 64 characters"},
+		{"location name with slash", LocationName, "a/b", "may only contain letters, digits, _, . and -"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := New().Check("field", c.value, c.rules...).Err()
+			if len(c.want) == 0 {
+				if err != nil {
+					t.Errorf("got %v, want no problem", err)
+				}
+				return
+			}
+
+			e, ok := err.(*errors.Error)
+			if !ok || e.Kind != errors.EInvalidRequest || e.Ctx != "field" {
+				t.Fatalf("got %v, want an invalid request for field", err)
+			}
+
+			if got := e.Error(); !strings.HasSuffix(got, "field "+c.want) {
+				t.Errorf("got %q, want it to end in %q", got, "field "+c.want)
+			}
+		})
+	}
+}
+
+// TestCheckFirstRule - each field gets only the first of its problems, while
+// every field is checked
+func TestCheckFirstRule(t *testing.T) {
+	problems := New().
+		Check("username", "", Username...).
+		Check("password", "", Password...).
+		Check("name", "ok", LocationName...).
+		Problems()
+
+	if len(problems) != 2 {
+		t.Fatalf("got %d problems, want 2: %v", len(problems), problems)
+	}
+
+	if problems[0].Ctx != "username" || problems[1].Ctx != "password" {
+		t.Errorf("got problems with %q and %q, want username and password", problems[0].Ctx, problems[1].Ctx)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	cases := []struct {
+		name     string
+		location *data.Location
+		want     []string
+	}{
+		{"unbounded", &data.Location{Blocked: data.Tiles{{X: 100, Y: 100}}}, nil},
+		{"bounded", &data.Location{Width: 3, Height: 2, Blocked: data.Tiles{{X: 2, Y: 1}}}, nil},
+		{"negative size", &data.Location{Width: -1, Height: -2}, []string{"loc.width", "loc.height"}},
+		{"negative tile", &data.Location{Blocked: data.Tiles{{X: -1, Y: 0}}}, []string{"loc.blocked[0]"}},
+		{"tile past width", &data.Location{Width: 3, Blocked: data.Tiles{{X: 0, Y: 0}, {X: 3, Y: 0}}}, []string{"loc.blocked[1]"}},
+		{"tile past height", &data.Location{Height: 2, Blocked: data.Tiles{{X: 0, Y: 2}}}, []string{"loc.blocked[0]"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			problems := New().Layout("loc", c.location).Problems()
+			if len(problems) != len(c.want) {
+				t.Fatalf("got problems %v, want %v", problems, c.want)
+			}
+
+			for i, problem := range problems {
+				if problem.Ctx != c.want[i] {
+					t.Errorf("got a problem with %q, want %q", problem.Ctx, c.want[i])
+				}
+			}
+		})
+	}
+
+	if problems := New().Layout("", &data.Location{Width: -1}).Problems(); len(problems) != 1 || problems[0].Ctx != "width" {
+		t.Errorf("without a prefix, got problems %v, want one with width", problems)
+	}
+}
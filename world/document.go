@@ -0,0 +1,208 @@
+package world
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/locations/rpc"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"github.com/carsonmyers/bublar-assignment/validate"
+	"gopkg.in/yaml.v2"
+)
+
+// FormatVersion - version of the document layout written by Export
+const FormatVersion = 1
+
+const (
+	// FormatJSON - encode documents as JSON
+	FormatJSON = "json"
+
+	// FormatYAML - encode documents as YAML
+	FormatYAML = "yaml"
+)
+
+// Document - the contents of a game world, as exported and imported
+type Document struct {
+	Version   int         `json:"version" yaml:"version"`
+	Locations []*Location `json:"locations" yaml:"locations"`
+	Players   []*Player   `json:"players,omitempty" yaml:"players,omitempty"`
+}
+
+// Location - a location in a world document. Locations without a size are
+// unbounded, and locations without blocked tiles can be moved around freely.
+type Location struct {
+	Name    string     `json:"name" yaml:"name"`
+	X       int        `json:"x" yaml:"x"`
+	Y       int        `json:"y" yaml:"y"`
+	Width   int        `json:"width,omitempty" yaml:"width,omitempty"`
+	Height  int        `json:"height,omitempty" yaml:"height,omitempty"`
+	Blocked data.Tiles `json:"blocked,omitempty" yaml:"blocked,omitempty"`
+}
+
+// locationFrom - convert a location message to its place in a document
+func locationFrom(msg *proto.Location) Location {
+	loc := rpc.LocationData(msg)
+	return Location{
+		Name:    loc.Name,
+		X:       loc.X,
+		Y:       loc.Y,
+		Width:   loc.Width,
+		Height:  loc.Height,
+		Blocked: loc.Blocked,
+	}
+}
+
+// data - convert a location to the universal data format
+func (l *Location) data() *data.Location {
+	return &data.Location{
+		Name:    l.Name,
+		X:       l.X,
+		Y:       l.Y,
+		Width:   l.Width,
+		Height:  l.Height,
+		Blocked: l.Blocked,
+	}
+}
+
+// message - convert a location to its message
+func (l *Location) message() *proto.Location {
+	return rpc.LocationMessage(l.data())
+}
+
+// matches - whether a location message has the same place and layout
+func (l *Location) matches(msg *proto.Location) bool {
+	other := locationFrom(msg)
+	return l.X == other.X && l.Y == other.Y && l.Width == other.Width && l.Height == other.Height &&
+		l.Blocked.Encode() == other.Blocked.Encode()
+}
+
+// Player - a player in a world document. Passwords are never exported, but
+// must be given to import a player who doesn't exist yet.
+type Player struct {
+	Username string    `json:"username" yaml:"username"`
+	Password string    `json:"password,omitempty" yaml:"password,omitempty"`
+	Position *Position `json:"position,omitempty" yaml:"position,omitempty"`
+}
+
+// Position - where a player in a world document is
+type Position struct {
+	Location string `json:"location" yaml:"location"`
+	X        int    `json:"x" yaml:"x"`
+	Y        int    `json:"y" yaml:"y"`
+}
+
+// Encode - write a document in the given format
+func Encode(w io.Writer, doc *Document, format string) error {
+	var data []byte
+	var err error
+
+	switch format {
+	case FormatJSON:
+		data, err = json.MarshalIndent(doc, "", "  ")
+		data = append(data, '\n')
+	case FormatYAML:
+		data, err = yaml.Marshal(doc)
+	default:
+		return errors.EInvalidRequest.NewErrorf("unknown format \"%s\"", format).WithContext("format")
+	}
+
+	if err != nil {
+		return errors.EInternal.NewError(err)
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// Decode - read a document in the given format
+func Decode(r io.Reader, format string) (*Document, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc Document
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&doc)
+	case FormatYAML:
+		err = yaml.UnmarshalStrict(data, &doc)
+	default:
+		return nil, errors.EInvalidRequest.NewErrorf("unknown format \"%s\"", format).WithContext("format")
+	}
+
+	if err != nil {
+		return nil, errors.EInvalidRequest.NewErrorf("invalid %s world document: %s", format, err)
+	}
+
+	return &doc, nil
+}
+
+// Validate - check a document for problems which don't depend on what is
+// already in the world
+func (d *Document) Validate() []*errors.Error {
+	problems := make([]*errors.Error, 0)
+	if d.Version != FormatVersion {
+		problems = append(problems, errors.EInvalidRequest.NewErrorf("unsupported document version %d", d.Version).WithContext("version"))
+	}
+
+	names := make(map[string]bool, len(d.Locations))
+	for i, location := range d.Locations {
+		ctx := fmt.Sprintf("locations[%d]", i)
+		if location == nil {
+			problems = append(problems, errors.EInvalidRequest.NewError("location name is required").WithContext(ctx+".name"))
+			continue
+		}
+
+		if invalid := validate.New().Check(ctx+".name", location.Name, validate.LocationName...).Problems(); len(invalid) > 0 {
+			problems = append(problems, invalid...)
+			continue
+		}
+
+		problems = append(problems, validate.New().Layout(ctx, location.data()).Problems()...)
+
+		if names[location.Name] {
+			problems = append(problems, errors.EDuplicateLocation.NewErrorf("location `%s` is listed more than once", location.Name).WithContext(ctx+".name"))
+		}
+
+		names[location.Name] = true
+	}
+
+	usernames := make(map[string]bool, len(d.Players))
+	for i, player := range d.Players {
+		ctx := fmt.Sprintf("players[%d]", i)
+		if player == nil {
+			problems = append(problems, errors.EInvalidRequest.NewError("username is required").WithContext(ctx+".username"))
+			continue
+		}
+
+		v := validate.New().Check(ctx+".username", player.Username, validate.Username...)
+		if len(player.Password) > 0 {
+			v.Check(ctx+".password", player.Password, validate.Password...)
+		}
+
+		if invalid := v.Problems(); len(invalid) > 0 {
+			problems = append(problems, invalid...)
+			continue
+		}
+
+		if usernames[player.Username] {
+			problems = append(problems, errors.EDuplicateUser.NewErrorf("player `%s` is listed more than once", player.Username).WithContext(ctx+".username"))
+		}
+
+		usernames[player.Username] = true
+
+		if player.Position != nil && len(player.Position.Location) == 0 {
+			problems = append(problems, errors.EInvalidRequest.NewError("position location is required").WithContext(ctx+".position.location"))
+		}
+	}
+
+	return problems
+}
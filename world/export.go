@@ -0,0 +1,86 @@
+package world
+
+import (
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/paging"
+	"github.com/carsonmyers/bublar-assignment/proto"
+)
+
+// Export - read every location, and optionally every player with their
+// position, from the locations and players services
+func Export(players bool) (*Document, error) {
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		return nil, errors.ERPCConnection.NewError(err)
+	}
+
+	doc := &Document{
+		Version:   FormatVersion,
+		Locations: make([]*Location, 0),
+	}
+
+	after := ""
+	for {
+		page, next, err := locationSvc.List(&proto.LocationListRequest{
+			Limit: paging.MaxLimit,
+			After: after,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range page {
+			loc := locationFrom(l)
+			doc.Locations = append(doc.Locations, &loc)
+		}
+
+		if len(next) == 0 {
+			break
+		}
+
+		after = next
+	}
+
+	if !players {
+		return doc, nil
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		return nil, errors.ERPCConnection.NewError(err)
+	}
+
+	doc.Players = make([]*Player, 0)
+	after = ""
+	for {
+		page, next, err := playerSvc.List(&proto.PlayerListRequest{
+			Limit: paging.MaxLimit,
+			After: after,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range page {
+			player := &Player{Username: p.GetUsername()}
+			if len(p.GetLocation()) > 0 {
+				player.Position = &Position{
+					Location: p.GetLocation(),
+					X:        int(p.GetX()),
+					Y:        int(p.GetY()),
+				}
+			}
+
+			doc.Players = append(doc.Players, player)
+		}
+
+		if len(next) == 0 {
+			break
+		}
+
+		after = next
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,217 @@
+package world
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"go.uber.org/zap"
+)
+
+const (
+	// ActionCreate - the record doesn't exist and is created
+	ActionCreate = "create"
+
+	// ActionUpdate - the record exists and is changed to match the document
+	ActionUpdate = "update"
+
+	// ActionUnchanged - the record already matches the document
+	ActionUnchanged = "unchanged"
+)
+
+// Options - how to import a document
+type Options struct {
+	// Upsert - update locations and players which already exist, instead of
+	// refusing to import them
+	Upsert bool
+
+	// DryRun - check the document and report what would change, without
+	// changing anything
+	DryRun bool
+}
+
+// Change - something which is (or would be) done to import a document
+type Change struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+
+	apply func() error
+}
+
+// Report - the changes made (or that would be made) by an import
+type Report struct {
+	DryRun  bool      `json:"dryRun"`
+	Changes []*Change `json:"changes"`
+}
+
+// Import - load a document into the world. Everything is checked against the
+// document and the current world before anything is written, and if there are
+// any problems nothing is. Existing locations and players are only changed
+// with the upsert option; the passwords of existing players are never changed.
+// The caller attached to ctx is recorded as making the changes.
+func Import(ctx context.Context, doc *Document, opts *Options) (*Report, []*errors.Error) {
+	if problems := doc.Validate(); len(problems) > 0 {
+		return nil, problems
+	}
+
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		return nil, []*errors.Error{errors.ERPCConnection.NewError(err)}
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		return nil, []*errors.Error{errors.ERPCConnection.NewError(err)}
+	}
+
+	locationSvc, playerSvc = locationSvc.With(ctx), playerSvc.With(ctx)
+
+	report := &Report{
+		DryRun:  opts.DryRun,
+		Changes: make([]*Change, 0),
+	}
+	problems := make([]*errors.Error, 0)
+
+	// every location the document's players can be placed in
+	known := make(map[string]bool, len(doc.Locations))
+
+	for i, l := range doc.Locations {
+		location := l
+		ctx := fmt.Sprintf("locations[%d]", i)
+		known[location.Name] = true
+
+		existing, err := locationSvc.Get(&proto.Location{Name: location.Name})
+		if err != nil && !errors.IsKind(err, errors.ENotFound) {
+			return nil, []*errors.Error{problem(err).WithContext(ctx)}
+		}
+
+		change := &Change{Kind: "location", Name: location.Name}
+		switch {
+		case err != nil:
+			change.Action = ActionCreate
+			change.apply = func() error {
+				_, err := locationSvc.Create(location.message())
+				return err
+			}
+		case !opts.Upsert:
+			problems = append(problems, errors.EDuplicateLocation.NewError(location.Name).WithContext(ctx+".name"))
+			continue
+		case location.matches(existing):
+			change.Action = ActionUnchanged
+		default:
+			change.Action = ActionUpdate
+			change.apply = func() error {
+				msg := location.message()
+				msg.Version = existing.GetVersion()
+
+				_, err := locationSvc.Update(&proto.LocationUpdate{
+					Id:       location.Name,
+					Location: msg,
+				})
+				return err
+			}
+		}
+
+		report.Changes = append(report.Changes, change)
+	}
+
+	for i, p := range doc.Players {
+		player := p
+		ctx := fmt.Sprintf("players[%d]", i)
+
+		existing, err := playerSvc.Get(&proto.Player{Username: player.Username})
+		if err != nil && !errors.IsKind(err, errors.ENotFound) {
+			return nil, []*errors.Error{problem(err).WithContext(ctx)}
+		}
+
+		change := &Change{Kind: "player", Name: player.Username}
+		switch {
+		case err != nil:
+			if len(player.Password) == 0 {
+				problems = append(problems, errors.EInvalidRequest.NewErrorf("password is required to create player `%s`", player.Username).WithContext(ctx+".password"))
+			}
+
+			change.Action = ActionCreate
+			change.apply = func() error {
+				_, err := playerSvc.Create(&proto.Player{
+					Username: player.Username,
+					Password: player.Password,
+				})
+				return err
+			}
+			existing = nil
+		case !opts.Upsert:
+			problems = append(problems, errors.EDuplicateUser.NewError(player.Username).WithContext(ctx+".username"))
+			continue
+		default:
+			change.Action = ActionUnchanged
+		}
+
+		report.Changes = append(report.Changes, change)
+
+		pos := player.Position
+		if pos == nil {
+			continue
+		}
+
+		if !known[pos.Location] {
+			if _, err := locationSvc.Get(&proto.Location{Name: pos.Location}); err != nil {
+				if !errors.IsKind(err, errors.ENotFound) {
+					return nil, []*errors.Error{problem(err).WithContext(ctx)}
+				}
+
+				problems = append(problems, errors.EUnknownLocation.NewErrorf("cannot place player in unknown location `%s`", pos.Location).WithContext(ctx+".position.location"))
+				continue
+			}
+
+			known[pos.Location] = true
+		}
+
+		if existing != nil && existing.GetLocation() == pos.Location && int(existing.GetX()) == pos.X && int(existing.GetY()) == pos.Y {
+			report.Changes = append(report.Changes, &Change{Kind: "position", Name: player.Username, Action: ActionUnchanged})
+			continue
+		}
+
+		report.Changes = append(report.Changes, &Change{
+			Kind:   "position",
+			Name:   player.Username,
+			Action: ActionUpdate,
+			apply: func() error {
+				if _, err := playerSvc.Travel(player.Username, pos.Location); err != nil {
+					return err
+				}
+
+				if pos.X == 0 && pos.Y == 0 {
+					return nil
+				}
+
+				_, err := playerSvc.Move(player.Username, int32(pos.X), int32(pos.Y), false)
+				return err
+			},
+		})
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for _, change := range report.Changes {
+		if change.apply == nil {
+			continue
+		}
+
+		if err := change.apply(); err != nil {
+			log.Error("Import failed part way through", zap.String("kind", change.Kind), zap.String("name", change.Name), zap.Error(err))
+			return report, []*errors.Error{problem(err).WithContext(change.Kind + " " + change.Name)}
+		}
+	}
+
+	return report, nil
+}
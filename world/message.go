@@ -0,0 +1,112 @@
+package world
+
+import (
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/proto"
+)
+
+// Message - convert a document to its message
+func (d *Document) Message() *proto.WorldDocument {
+	msg := &proto.WorldDocument{
+		Version:   int32(d.Version),
+		Locations: make([]*proto.Location, len(d.Locations)),
+		Players:   make([]*proto.Player, len(d.Players)),
+	}
+
+	for i, location := range d.Locations {
+		msg.Locations[i] = location.message()
+	}
+
+	for i, player := range d.Players {
+		msg.Players[i] = &proto.Player{
+			Username: player.Username,
+			Password: player.Password,
+		}
+
+		if player.Position != nil {
+			msg.Players[i].Location = player.Position.Location
+			msg.Players[i].X = int32(player.Position.X)
+			msg.Players[i].Y = int32(player.Position.Y)
+		}
+	}
+
+	return msg
+}
+
+// Message - convert an import report to its message
+func (r *Report) Message() *proto.WorldReport {
+	msg := &proto.WorldReport{
+		DryRun:  r.DryRun,
+		Changes: make([]*proto.WorldChange, len(r.Changes)),
+	}
+
+	for i, change := range r.Changes {
+		msg.Changes[i] = &proto.WorldChange{
+			Kind:   change.Kind,
+			Name:   change.Name,
+			Action: change.Action,
+		}
+	}
+
+	return msg
+}
+
+// Message - convert a snapshot to its message. Members are players with only
+// their username and coordinates, and accounts only their username and version.
+func (s *Snapshot) Message() *proto.WorldSnapshot {
+	msg := &proto.WorldSnapshot{
+		Version:   int32(s.Version),
+		Time:      s.Time.Unix(),
+		Locations: make([]*proto.LocationState, len(s.Locations)),
+		Accounts:  make([]*proto.Player, len(s.Accounts)),
+	}
+
+	for i, location := range s.Locations {
+		members := make([]*proto.Player, len(location.Members))
+		for j, member := range location.Members {
+			members[j] = &proto.Player{
+				Username: member.Username,
+				Location: location.Name,
+				X:        int32(member.X),
+				Y:        int32(member.Y),
+			}
+		}
+
+		msg.Locations[i] = &proto.LocationState{
+			Location: location.message(),
+			Members:  members,
+		}
+	}
+
+	for i, account := range s.Accounts {
+		msg.Accounts[i] = &proto.Player{
+			Username: account.Username,
+			Version:  uint64(account.Version),
+		}
+	}
+
+	return msg
+}
+
+// Message - convert a snapshot report to its message
+func (r *SnapshotReport) Message() *proto.WorldSnapshotReport {
+	return &proto.WorldSnapshotReport{
+		Accounts:  restoreReportMessage(r.Accounts),
+		Locations: restoreReportMessage(r.Locations),
+	}
+}
+
+func restoreReportMessage(report *data.RestoreReport) *proto.RestoreReport {
+	if report == nil {
+		return nil
+	}
+
+	return &proto.RestoreReport{
+		Created:   int32(report.Created),
+		Updated:   int32(report.Updated),
+		Restored:  int32(report.Restored),
+		Deleted:   int32(report.Deleted),
+		Positions: int32(report.Positions),
+		Missing:   report.Missing,
+	}
+}
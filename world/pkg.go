@@ -0,0 +1,17 @@
+package world
+
+import (
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/logger"
+)
+
+var log = logger.GetLogger()
+
+// problem - report an error from a service, keeping its kind if it has one
+func problem(err error) *errors.Error {
+	if e, ok := err.(*errors.Error); ok {
+		return e
+	}
+
+	return errors.ERPC.NewError(err)
+}
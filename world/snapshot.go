@@ -0,0 +1,271 @@
+package world
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/carsonmyers/bublar-assignment/connect"
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/proto"
+	"github.com/carsonmyers/bublar-assignment/validate"
+)
+
+// SnapshotVersion - version of the snapshot layout written by TakeSnapshot
+const SnapshotVersion = 1
+
+// Snapshot - the complete state of the world at a point in time
+type Snapshot struct {
+	Version   int                 `json:"version"`
+	Time      time.Time           `json:"time"`
+	Locations []*SnapshotLocation `json:"locations"`
+	Accounts  []*Account          `json:"accounts"`
+}
+
+// SnapshotLocation - a location in a snapshot, with the players in it
+type SnapshotLocation struct {
+	Location
+	Members []*Member `json:"members"`
+}
+
+// Member - a player's position within a location in a snapshot
+type Member struct {
+	Username string `json:"username"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+}
+
+// Account - a player account in a snapshot. Password hashes are never kept.
+type Account struct {
+	Username string `json:"username"`
+	Version  uint   `json:"version"`
+}
+
+// SnapshotReport - what restoring a snapshot changed
+type SnapshotReport struct {
+	Accounts  *data.RestoreReport `json:"accounts"`
+	Locations *data.RestoreReport `json:"locations"`
+}
+
+// TakeSnapshot - capture the accounts, locations, and player positions of the
+// world. Accounts are captured first, then locations with their players, so
+// changes made while the snapshot is taken may be partly included.
+func TakeSnapshot() (*Snapshot, error) {
+	playerSvc, err := connect.Players()
+	if err != nil {
+		return nil, errors.ERPCConnection.NewError(err)
+	}
+
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		return nil, errors.ERPCConnection.NewError(err)
+	}
+
+	snapshot := &Snapshot{
+		Version: SnapshotVersion,
+		Time:    time.Now().UTC(),
+	}
+
+	players, err := playerSvc.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Accounts = make([]*Account, len(players.GetPlayers()))
+	for i, p := range players.GetPlayers() {
+		snapshot.Accounts[i] = &Account{
+			Username: p.GetUsername(),
+			Version:  uint(p.GetVersion()),
+		}
+	}
+
+	locations, err := locationSvc.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Locations = make([]*SnapshotLocation, len(locations.GetLocations()))
+	for i, state := range locations.GetLocations() {
+		members := make([]*Member, len(state.GetMembers()))
+		for j, m := range state.GetMembers() {
+			members[j] = &Member{
+				Username: m.GetUsername(),
+				X:        int(m.GetX()),
+				Y:        int(m.GetY()),
+			}
+		}
+
+		snapshot.Locations[i] = &SnapshotLocation{
+			Location: locationFrom(state.GetLocation()),
+			Members:  members,
+		}
+	}
+
+	return snapshot, nil
+}
+
+// Validate - check a snapshot for problems before restoring it
+func (s *Snapshot) Validate() []*errors.Error {
+	problems := make([]*errors.Error, 0)
+	if s.Version != SnapshotVersion {
+		problems = append(problems, errors.EInvalidRequest.NewErrorf("unsupported snapshot version %d", s.Version).WithContext("version"))
+	}
+
+	names := make(map[string]bool, len(s.Locations))
+	placed := make(map[string]bool)
+	for i, location := range s.Locations {
+		ctx := fmt.Sprintf("locations[%d]", i)
+		if location == nil || len(location.Name) == 0 {
+			problems = append(problems, errors.EInvalidRequest.NewError("location name is required").WithContext(ctx+".name"))
+			continue
+		}
+
+		problems = append(problems, validate.New().Layout(ctx, location.data()).Problems()...)
+
+		if names[location.Name] {
+			problems = append(problems, errors.EDuplicateLocation.NewErrorf("location `%s` is listed more than once", location.Name).WithContext(ctx+".name"))
+		}
+
+		names[location.Name] = true
+
+		for j, member := range location.Members {
+			if member == nil || len(member.Username) == 0 {
+				problems = append(problems, errors.EInvalidRequest.NewError("username is required").WithContext(fmt.Sprintf("%s.members[%d].username", ctx, j)))
+				continue
+			}
+
+			if placed[member.Username] {
+				problems = append(problems, errors.EInvalidRequest.NewErrorf("player `%s` is in more than one location", member.Username).WithContext(fmt.Sprintf("%s.members[%d].username", ctx, j)))
+			}
+
+			placed[member.Username] = true
+		}
+	}
+
+	usernames := make(map[string]bool, len(s.Accounts))
+	for i, account := range s.Accounts {
+		ctx := fmt.Sprintf("accounts[%d].username", i)
+		if account == nil || len(account.Username) == 0 {
+			problems = append(problems, errors.EInvalidRequest.NewError("username is required").WithContext(ctx))
+			continue
+		}
+
+		if usernames[account.Username] {
+			problems = append(problems, errors.EDuplicateUser.NewErrorf("account `%s` is listed more than once", account.Username).WithContext(ctx))
+		}
+
+		usernames[account.Username] = true
+	}
+
+	return problems
+}
+
+// RestoreSnapshot - bring the world back to a snapshot. Accounts are restored
+// first, then locations and positions. If clear is set, anything not in the
+// snapshot is deleted (and can itself be restored until it is purged);
+// otherwise the snapshot is merged into the current world. The caller
+// attached to ctx is recorded as making the changes.
+func RestoreSnapshot(ctx context.Context, s *Snapshot, clear bool) (*SnapshotReport, []*errors.Error) {
+	if problems := s.Validate(); len(problems) > 0 {
+		return nil, problems
+	}
+
+	playerSvc, err := connect.Players()
+	if err != nil {
+		return nil, []*errors.Error{errors.ERPCConnection.NewError(err)}
+	}
+
+	locationSvc, err := connect.Locations()
+	if err != nil {
+		return nil, []*errors.Error{errors.ERPCConnection.NewError(err)}
+	}
+
+	playerSvc, locationSvc = playerSvc.With(ctx), locationSvc.With(ctx)
+
+	players := &proto.PlayerSnapshot{
+		Players: make([]*proto.Player, len(s.Accounts)),
+	}
+
+	for i, account := range s.Accounts {
+		players.Players[i] = &proto.Player{
+			Username: account.Username,
+			Version:  uint64(account.Version),
+		}
+	}
+
+	report := &SnapshotReport{}
+
+	accounts, err := playerSvc.RestoreSnapshot(players, clear)
+	if err != nil {
+		return nil, []*errors.Error{problem(err).WithContext("accounts")}
+	}
+
+	report.Accounts = fromRestoreReport(accounts)
+
+	// only players whose accounts exist once the accounts are restored can be
+	// put back in their locations
+	usernames := make([]string, 0)
+	for _, location := range s.Locations {
+		for _, member := range location.Members {
+			usernames = append(usernames, member.Username)
+		}
+	}
+
+	found, err := playerSvc.GetMany(usernames)
+	if err != nil {
+		return report, []*errors.Error{problem(err).WithContext("locations")}
+	}
+
+	exists := make(map[string]bool, len(found))
+	for _, player := range found {
+		exists[player.GetUsername()] = true
+	}
+
+	locations := &proto.LocationSnapshot{
+		Locations: make([]*proto.LocationState, len(s.Locations)),
+	}
+
+	missing := make([]string, 0)
+	for i, location := range s.Locations {
+		members := make([]*proto.Player, 0, len(location.Members))
+		for _, member := range location.Members {
+			if !exists[member.Username] {
+				missing = append(missing, member.Username)
+				continue
+			}
+
+			members = append(members, &proto.Player{
+				Username: member.Username,
+				Location: location.Name,
+				X:        int32(member.X),
+				Y:        int32(member.Y),
+			})
+		}
+
+		locations.Locations[i] = &proto.LocationState{
+			Location: location.message(),
+			Members:  members,
+		}
+	}
+
+	places, err := locationSvc.RestoreSnapshot(locations, clear)
+	if err != nil {
+		return report, []*errors.Error{problem(err).WithContext("locations")}
+	}
+
+	report.Locations = fromRestoreReport(places)
+	report.Locations.Missing = append(report.Locations.Missing, missing...)
+	return report, nil
+}
+
+func fromRestoreReport(report *proto.RestoreReport) *data.RestoreReport {
+	return &data.RestoreReport{
+		Created:   int(report.GetCreated()),
+		Updated:   int(report.GetUpdated()),
+		Restored:  int(report.GetRestored()),
+		Deleted:   int(report.GetDeleted()),
+		Positions: int(report.GetPositions()),
+		Missing:   report.GetMissing(),
+	}
+}
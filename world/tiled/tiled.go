@@ -0,0 +1,581 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/errors"
+	"github.com/carsonmyers/bublar-assignment/world"
+)
+
+const (
+	// FormatTMX - Tiled's XML map format
+	FormatTMX = "tmx"
+
+	// FormatJSON - Tiled's JSON map format
+	FormatJSON = "json"
+)
+
+// Map - the parts of a map made with the Tiled editor which describe locations
+type Map struct {
+	TileWidth  int
+	TileHeight int
+	Layers     []*Layer
+}
+
+// Layer - a layer of a Tiled map. Object layers hold locations, tile layers
+// can mark tiles as blocked, and group layers can contain other layers.
+type Layer struct {
+	Type    string
+	Name    string
+	Objects []*Object
+	Layers  []*Layer
+
+	// Tiles - the tiles of a tile layer which aren't empty, in tiles from the
+	// top-left of the map
+	Tiles []data.Tile
+}
+
+// Object - an object placed in an object layer, in pixels
+type Object struct {
+	ID     int     `xml:"id,attr" json:"id"`
+	Name   string  `xml:"name,attr" json:"name"`
+	X      float64 `xml:"x,attr" json:"x"`
+	Y      float64 `xml:"y,attr" json:"y"`
+	Width  float64 `xml:"width,attr" json:"width"`
+	Height float64 `xml:"height,attr" json:"height"`
+	GID    uint32  `xml:"gid,attr" json:"gid"`
+}
+
+const (
+	layerObjects = "objectgroup"
+	layerGroup   = "group"
+	layerTiles   = "tilelayer"
+
+	// tmxLayerTiles - the element of a tile layer in a TMX file
+	tmxLayerTiles = "layer"
+
+	// gidMask - the bits of a tile's global ID which aren't flip flags
+	gidMask = 0x1fffffff
+)
+
+// tmxLayer - a layer in a TMX file, where each kind of layer is its own element
+type tmxLayer struct {
+	Objects []*Object  `xml:"object"`
+	Layers  []tmxChild `xml:",any"`
+}
+
+// tmxChild - a layer nested inside a TMX map or group
+type tmxChild struct {
+	XMLName xml.Name
+	Name    string   `xml:"name,attr"`
+	Width   int      `xml:"width,attr"`
+	Height  int      `xml:"height,attr"`
+	Data    *tmxData `xml:"data"`
+	tmxLayer
+}
+
+// tmxData - the tiles of a TMX tile layer, either encoded as text or as one
+// element per tile. Infinite maps split the tiles into chunks.
+type tmxData struct {
+	Encoding    string      `xml:"encoding,attr"`
+	Compression string      `xml:"compression,attr"`
+	Text        string      `xml:",chardata"`
+	Tiles       []tmxTile   `xml:"tile"`
+	Chunks      []*tmxChunk `xml:"chunk"`
+}
+
+type tmxChunk struct {
+	X      int       `xml:"x,attr"`
+	Y      int       `xml:"y,attr"`
+	Width  int       `xml:"width,attr"`
+	Height int       `xml:"height,attr"`
+	Text   string    `xml:",chardata"`
+	Tiles  []tmxTile `xml:"tile"`
+}
+
+type tmxTile struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type tmxMap struct {
+	TileWidth  int        `xml:"tilewidth,attr"`
+	TileHeight int        `xml:"tileheight,attr"`
+	Layers     []tmxChild `xml:",any"`
+}
+
+// jsonLayer - a layer in a JSON map, whose tile data is either an array of
+// global IDs or an encoded string
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Objects     []*Object       `json:"objects"`
+	Layers      []*jsonLayer    `json:"layers"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Data        json.RawMessage `json:"data"`
+	Chunks      []*jsonChunk    `json:"chunks"`
+}
+
+type jsonChunk struct {
+	X      int             `json:"x"`
+	Y      int             `json:"y"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type jsonMap struct {
+	TileWidth  int          `json:"tilewidth"`
+	TileHeight int          `json:"tileheight"`
+	Layers     []*jsonLayer `json:"layers"`
+}
+
+// Decode - read a map in the given format
+func Decode(r io.Reader, format string) (*Map, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		var m jsonMap
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("invalid Tiled JSON map: %s", err)
+		}
+
+		layers, err := jsonLayers(m.Layers)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Map{
+			TileWidth:  m.TileWidth,
+			TileHeight: m.TileHeight,
+			Layers:     layers,
+		}, nil
+	case FormatTMX:
+		var tmx tmxMap
+		if err := xml.Unmarshal(raw, &tmx); err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("invalid Tiled TMX map: %s", err)
+		}
+
+		layers, err := tmxLayers(tmx.Layers)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Map{
+			TileWidth:  tmx.TileWidth,
+			TileHeight: tmx.TileHeight,
+			Layers:     layers,
+		}, nil
+	}
+
+	return nil, errors.EInvalidRequest.NewErrorf("unknown Tiled map format \"%s\"", format).WithContext("format")
+}
+
+// tmxLayers - convert TMX layer elements to the layout of the JSON format
+func tmxLayers(children []tmxChild) ([]*Layer, error) {
+	layers := make([]*Layer, 0, len(children))
+	for _, child := range children {
+		switch child.XMLName.Local {
+		case layerObjects, layerGroup:
+			nested, err := tmxLayers(child.Layers)
+			if err != nil {
+				return nil, err
+			}
+
+			layers = append(layers, &Layer{
+				Type:    child.XMLName.Local,
+				Name:    child.Name,
+				Objects: child.Objects,
+				Layers:  nested,
+			})
+		case tmxLayerTiles:
+			tiles, err := tmxTiles(&child)
+			if err != nil {
+				return nil, err
+			}
+
+			layers = append(layers, &Layer{
+				Type:  layerTiles,
+				Name:  child.Name,
+				Tiles: tiles,
+			})
+		}
+	}
+
+	return layers, nil
+}
+
+// tmxTiles - find the tiles of a TMX tile layer which aren't empty
+func tmxTiles(layer *tmxChild) ([]data.Tile, error) {
+	if layer.Data == nil {
+		return nil, nil
+	}
+
+	read := func(text string, tiles []tmxTile) ([]uint32, error) {
+		if len(layer.Data.Encoding) == 0 {
+			gids := make([]uint32, len(tiles))
+			for i, tile := range tiles {
+				gids[i] = tile.GID
+			}
+
+			return gids, nil
+		}
+
+		return decodeTiles(layer.Name, layer.Data.Encoding, layer.Data.Compression, text)
+	}
+
+	if len(layer.Data.Chunks) == 0 {
+		gids, err := read(layer.Data.Text, layer.Data.Tiles)
+		if err != nil {
+			return nil, err
+		}
+
+		return filled(layer.Name, 0, 0, layer.Width, layer.Height, gids)
+	}
+
+	tiles := make([]data.Tile, 0)
+	for _, chunk := range layer.Data.Chunks {
+		gids, err := read(chunk.Text, chunk.Tiles)
+		if err != nil {
+			return nil, err
+		}
+
+		found, err := filled(layer.Name, chunk.X, chunk.Y, chunk.Width, chunk.Height, gids)
+		if err != nil {
+			return nil, err
+		}
+
+		tiles = append(tiles, found...)
+	}
+
+	return tiles, nil
+}
+
+// jsonLayers - convert the layers of a JSON map, decoding the data of tile
+// layers
+func jsonLayers(source []*jsonLayer) ([]*Layer, error) {
+	layers := make([]*Layer, 0, len(source))
+	for _, l := range source {
+		layer := &Layer{
+			Type:    l.Type,
+			Name:    l.Name,
+			Objects: l.Objects,
+		}
+
+		switch l.Type {
+		case layerGroup:
+			nested, err := jsonLayers(l.Layers)
+			if err != nil {
+				return nil, err
+			}
+
+			layer.Layers = nested
+		case layerTiles:
+			tiles, err := jsonTiles(l)
+			if err != nil {
+				return nil, err
+			}
+
+			layer.Tiles = tiles
+		}
+
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
+
+// jsonTiles - find the tiles of a JSON tile layer which aren't empty
+func jsonTiles(layer *jsonLayer) ([]data.Tile, error) {
+	read := func(raw json.RawMessage) ([]uint32, error) {
+		if len(raw) == 0 {
+			return nil, nil
+		}
+
+		switch layer.Encoding {
+		case "", "csv":
+			var gids []uint32
+			if err := json.Unmarshal(raw, &gids); err != nil {
+				return nil, errors.EInvalidRequest.NewErrorf("invalid data in tile layer `%s`: %s", layer.Name, err)
+			}
+
+			return gids, nil
+		case "base64":
+			var text string
+			if err := json.Unmarshal(raw, &text); err != nil {
+				return nil, errors.EInvalidRequest.NewErrorf("invalid data in tile layer `%s`: %s", layer.Name, err)
+			}
+
+			return decodeTiles(layer.Name, layer.Encoding, layer.Compression, text)
+		}
+
+		return nil, errors.EInvalidRequest.NewErrorf("tile layer `%s` uses unknown encoding \"%s\"", layer.Name, layer.Encoding)
+	}
+
+	if len(layer.Chunks) == 0 {
+		gids, err := read(layer.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		if gids == nil {
+			return nil, nil
+		}
+
+		return filled(layer.Name, 0, 0, layer.Width, layer.Height, gids)
+	}
+
+	tiles := make([]data.Tile, 0)
+	for _, chunk := range layer.Chunks {
+		gids, err := read(chunk.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		found, err := filled(layer.Name, chunk.X, chunk.Y, chunk.Width, chunk.Height, gids)
+		if err != nil {
+			return nil, err
+		}
+
+		tiles = append(tiles, found...)
+	}
+
+	return tiles, nil
+}
+
+// decodeTiles - read the global IDs of a tile layer which are encoded as text
+func decodeTiles(layer, encoding, compression, text string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		if len(compression) > 0 {
+			return nil, errors.EInvalidRequest.NewErrorf("tile layer `%s` has compressed CSV data", layer)
+		}
+
+		fields := strings.Split(strings.TrimSpace(text), ",")
+		gids := make([]uint32, len(fields))
+		for i, field := range fields {
+			gid, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+			if err != nil {
+				return nil, errors.EInvalidRequest.NewErrorf("invalid tile \"%s\" in tile layer `%s`", strings.TrimSpace(field), layer)
+			}
+
+			gids[i] = uint32(gid)
+		}
+
+		return gids, nil
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+		if err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("invalid base64 data in tile layer `%s`: %s", layer, err)
+		}
+
+		var r io.Reader = bytes.NewReader(raw)
+		switch compression {
+		case "":
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return nil, errors.EInvalidRequest.NewErrorf("invalid zlib data in tile layer `%s`: %s", layer, err)
+			}
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return nil, errors.EInvalidRequest.NewErrorf("invalid gzip data in tile layer `%s`: %s", layer, err)
+			}
+		default:
+			return nil, errors.EInvalidRequest.NewErrorf("tile layer `%s` uses unsupported compression \"%s\"", layer, compression)
+		}
+
+		if raw, err = ioutil.ReadAll(r); err != nil {
+			return nil, errors.EInvalidRequest.NewErrorf("invalid %s data in tile layer `%s`: %s", compression, layer, err)
+		}
+
+		if len(raw)%4 != 0 {
+			return nil, errors.EInvalidRequest.NewErrorf("tile layer `%s` has %d bytes of data, which is not a whole number of tiles", layer, len(raw))
+		}
+
+		gids := make([]uint32, len(raw)/4)
+		for i := range gids {
+			gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+		}
+
+		return gids, nil
+	}
+
+	return nil, errors.EInvalidRequest.NewErrorf("tile layer `%s` uses unknown encoding \"%s\"", layer, encoding)
+}
+
+// filled - find the tiles which aren't empty in a rectangle of global IDs,
+// whose top-left tile is at x, y
+func filled(layer string, x, y, width, height int, gids []uint32) ([]data.Tile, error) {
+	if len(gids) != width*height {
+		return nil, errors.EInvalidRequest.NewErrorf("tile layer `%s` has %d tiles, expected %dx%d", layer, len(gids), width, height)
+	}
+
+	tiles := make([]data.Tile, 0)
+	for i, gid := range gids {
+		if gid&gidMask != 0 {
+			tiles = append(tiles, data.Tile{X: x + i%width, Y: y + i/width})
+		}
+	}
+
+	return tiles, nil
+}
+
+// Options - how to turn map objects into locations
+type Options struct {
+	// Layers - only take locations from object layers with these names, or
+	// from every object layer if empty
+	Layers []string
+
+	// Blocked - tile layers whose tiles can't be moved onto. Each location
+	// gets the blocked tiles within its object.
+	Blocked []string
+
+	// Pixels - keep coordinates in pixels instead of converting them to tiles
+	Pixels bool
+}
+
+// Locations - turn every named object in the map's object layers into a
+// location at the object's top-left corner, the size of the object. Unnamed
+// objects are skipped, and objects without a size are unbounded.
+func (m *Map) Locations(opts *Options) ([]*world.Location, error) {
+	if !opts.Pixels && (m.TileWidth <= 0 || m.TileHeight <= 0) {
+		return nil, errors.EInvalidRequest.NewErrorf("map has no tile size to convert coordinates with")
+	}
+
+	if opts.Pixels && len(opts.Blocked) > 0 {
+		return nil, errors.EInvalidRequest.NewErrorf("blocked tiles can't be used with pixel coordinates")
+	}
+
+	only := make(map[string]bool, len(opts.Layers))
+	for _, name := range opts.Layers {
+		only[name] = true
+	}
+
+	blocking := make(map[string]bool, len(opts.Blocked))
+	for _, name := range opts.Blocked {
+		blocking[name] = true
+	}
+
+	scaleX, scaleY := float64(m.TileWidth), float64(m.TileHeight)
+	if opts.Pixels {
+		scaleX, scaleY = 1, 1
+	}
+
+	objects := make([]*Object, 0)
+	blocked := make(map[data.Tile]bool)
+	found := make(map[string]bool, len(opts.Blocked))
+
+	var walk func(layers []*Layer)
+	walk = func(layers []*Layer) {
+		for _, layer := range layers {
+			switch {
+			case layer.Type == layerGroup:
+				walk(layer.Layers)
+			case layer.Type == layerTiles && blocking[layer.Name]:
+				found[layer.Name] = true
+				for _, tile := range layer.Tiles {
+					blocked[tile] = true
+				}
+			case layer.Type == layerObjects && (len(only) == 0 || only[layer.Name]):
+				for _, obj := range layer.Objects {
+					if len(obj.Name) > 0 {
+						objects = append(objects, obj)
+					}
+				}
+			}
+		}
+	}
+
+	walk(m.Layers)
+
+	for _, name := range opts.Blocked {
+		if !found[name] {
+			return nil, errors.EInvalidRequest.NewErrorf("no tile layer named `%s`", name).WithContext("blocked")
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil, errors.EInvalidRequest.NewErrorf("no named objects found in %s", layerDescription(opts.Layers))
+	}
+
+	locations := make([]*world.Location, len(objects))
+	for i, obj := range objects {
+		y := obj.Y
+		if obj.GID != 0 {
+			// tile objects are positioned by their bottom-left corner
+			y -= obj.Height
+		}
+
+		left, top := int(math.Floor(obj.X/scaleX)), int(math.Floor(y/scaleY))
+		location := &world.Location{
+			Name: obj.Name,
+			X:    left,
+			Y:    top,
+		}
+
+		// an object covers every tile it overlaps
+		if obj.Width > 0 {
+			location.Width = int(math.Ceil((obj.X+obj.Width)/scaleX)) - left
+		}
+		if obj.Height > 0 {
+			location.Height = int(math.Ceil((y+obj.Height)/scaleY)) - top
+		}
+
+		if location.Width > 0 && location.Height > 0 {
+			location.Blocked = within(blocked, left, top, location.Width, location.Height)
+		}
+
+		locations[i] = location
+	}
+
+	return locations, nil
+}
+
+// within - the blocked tiles inside a rectangle, relative to its top-left
+// corner and ordered row by row
+func within(blocked map[data.Tile]bool, left, top, width, height int) data.Tiles {
+	var tiles data.Tiles
+	for tile := range blocked {
+		if tile.X >= left && tile.X < left+width && tile.Y >= top && tile.Y < top+height {
+			tiles = append(tiles, data.Tile{X: tile.X - left, Y: tile.Y - top})
+		}
+	}
+
+	sort.Slice(tiles, func(i, j int) bool {
+		if tiles[i].Y != tiles[j].Y {
+			return tiles[i].Y < tiles[j].Y
+		}
+
+		return tiles[i].X < tiles[j].X
+	})
+
+	return tiles
+}
+
+func layerDescription(layers []string) string {
+	if len(layers) == 0 {
+		return "any object layer"
+	}
+
+	return fmt.Sprintf("object layers %q", layers)
+}
@@ -0,0 +1,218 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carsonmyers/bublar-assignment/data"
+	"github.com/carsonmyers/bublar-assignment/world"
+)
+
+// walls - the tiles of a 4x3 tile layer, with a flip flag set on one of them
+var walls = []uint32{
+	0, 1, 0, 0,
+	0, 0, 0x80000002, 0,
+	1, 0, 0, 0,
+}
+
+// rooms - the locations made from the objects of the test maps when walls
+// blocks movement: a rectangle, a point and a tile object
+var rooms = []*world.Location{
+	{Name: "hall", X: 1, Y: 0, Width: 2, Height: 2, Blocked: data.Tiles{{X: 0, Y: 0}, {X: 1, Y: 1}}},
+	{Name: "spawn", X: 2, Y: 2},
+	{Name: "statue", X: 0, Y: 2, Width: 1, Height: 1, Blocked: data.Tiles{{X: 0, Y: 0}}},
+}
+
+func encode(t *testing.T, gids []uint32, compression string) string {
+	raw := make([]byte, len(gids)*4)
+	for i, gid := range gids {
+		binary.LittleEndian.PutUint32(raw[i*4:], gid)
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	default:
+		return base64.StdEncoding.EncodeToString(raw)
+	}
+
+	if _, err := w.Write(raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func csv(gids []uint32) string {
+	fields := make([]string, len(gids))
+	for i, gid := range gids {
+		fields[i] = fmt.Sprint(gid)
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// tmxDoc - a map with the test objects, a decoy object layer, and a walls tile
+// layer with the given data element
+func tmxDoc(tileData string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.4" orientation="orthogonal" width="4" height="3" tilewidth="16" tileheight="16">
+ <tileset firstgid="1" source="tiles.tsx"/>
+ <layer id="1" name="walls" width="4" height="3">
+  ` + tileData + `
+ </layer>
+ <group id="2" name="level">
+  <objectgroup id="3" name="rooms">
+   <object id="1" name="hall" x="16" y="0" width="32" height="32"/>
+   <object id="2" name="spawn" x="40" y="40"/>
+   <object id="3" gid="5" name="statue" x="0" y="48" width="16" height="16"/>
+   <object id="4" x="8" y="8"/>
+  </objectgroup>
+ </group>
+ <objectgroup id="4" name="notes">
+  <object id="5" name="todo" x="0" y="0"/>
+ </objectgroup>
+</map>`
+}
+
+// jsonDoc - the same map as tmxDoc, with the given data fields in the walls layer
+func jsonDoc(tileData string) string {
+	return `{
+ "tilewidth": 16, "tileheight": 16, "width": 4, "height": 3,
+ "layers": [
+  {"type": "tilelayer", "name": "walls", "x": 0, "y": 0, "width": 4, "height": 3, ` + tileData + `},
+  {"type": "group", "name": "level", "layers": [
+   {"type": "objectgroup", "name": "rooms", "objects": [
+    {"id": 1, "name": "hall", "x": 16, "y": 0, "width": 32, "height": 32},
+    {"id": 2, "name": "spawn", "x": 40, "y": 40, "point": true},
+    {"id": 3, "gid": 5, "name": "statue", "x": 0, "y": 48, "width": 16, "height": 16},
+    {"id": 4, "name": "", "x": 8, "y": 8}
+   ]}
+  ]},
+  {"type": "objectgroup", "name": "notes", "objects": [{"id": 5, "name": "todo", "x": 0, "y": 0}]}
+ ]
+}`
+}
+
+func TestLocations(t *testing.T) {
+	roomsOnly := &Options{Layers: []string{"rooms"}, Blocked: []string{"walls"}}
+
+	// the walls layer of an infinite map, split into a 4x2 and a 4x1 chunk
+	top, bottom := walls[:8], walls[8:]
+
+	cases := []struct {
+		name   string
+		format string
+		input  string
+		opts   *Options
+		want   []*world.Location
+	}{
+		{"tmx csv", FormatTMX, tmxDoc(`<data encoding="csv">` + csv(walls) + `</data>`), roomsOnly, rooms},
+		{"tmx base64", FormatTMX, tmxDoc(`<data encoding="base64">` + encode(t, walls, "") + `</data>`), roomsOnly, rooms},
+		{"tmx zlib", FormatTMX, tmxDoc(`<data encoding="base64" compression="zlib">` + encode(t, walls, "zlib") + `</data>`), roomsOnly, rooms},
+		{"tmx gzip", FormatTMX, tmxDoc(`<data encoding="base64" compression="gzip">` + encode(t, walls, "gzip") + `</data>`), roomsOnly, rooms},
+		{"tmx xml", FormatTMX, tmxDoc(`<data><tile/><tile gid="1"/><tile/><tile/><tile/><tile/><tile gid="2"/><tile/><tile gid="1"/><tile/><tile/><tile/></data>`), roomsOnly, rooms},
+		{"tmx chunks", FormatTMX, tmxDoc(`<data encoding="csv"><chunk x="0" y="0" width="4" height="2">` + csv(top) + `</chunk><chunk x="0" y="2" width="4" height="1">` + csv(bottom) + `</chunk></data>`), roomsOnly, rooms},
+		{"json array", FormatJSON, jsonDoc(`"data": [` + csv(walls) + `]`), roomsOnly, rooms},
+		{"json base64", FormatJSON, jsonDoc(`"encoding": "base64", "data": "` + encode(t, walls, "") + `"`), roomsOnly, rooms},
+		{"json zlib", FormatJSON, jsonDoc(`"encoding": "base64", "compression": "zlib", "data": "` + encode(t, walls, "zlib") + `"`), roomsOnly, rooms},
+		{"json chunks", FormatJSON, jsonDoc(`"chunks": [{"x": 0, "y": 0, "width": 4, "height": 2, "data": [` + csv(top) + `]}, {"x": 0, "y": 2, "width": 4, "height": 1, "data": [` + csv(bottom) + `]}]`), roomsOnly, rooms},
+		{"no blocked layers", FormatTMX, tmxDoc(`<data encoding="csv">` + csv(walls) + `</data>`), &Options{Layers: []string{"rooms"}}, []*world.Location{
+			{Name: "hall", X: 1, Y: 0, Width: 2, Height: 2},
+			{Name: "spawn", X: 2, Y: 2},
+			{Name: "statue", X: 0, Y: 2, Width: 1, Height: 1},
+		}},
+		{"every object layer", FormatJSON, jsonDoc(`"data": [` + csv(walls) + `]`), &Options{Blocked: []string{"walls"}}, append(append([]*world.Location{}, rooms...), &world.Location{Name: "todo"})},
+		{"pixels", FormatTMX, tmxDoc(`<data encoding="csv">` + csv(walls) + `</data>`), &Options{Layers: []string{"rooms"}, Pixels: true}, []*world.Location{
+			{Name: "hall", X: 16, Y: 0, Width: 32, Height: 32},
+			{Name: "spawn", X: 40, Y: 40},
+			{Name: "statue", X: 0, Y: 32, Width: 16, Height: 16},
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m, err := Decode(strings.NewReader(c.input), c.format)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			got, err := m.Locations(c.opts)
+			if err != nil {
+				t.Fatalf("Locations: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got locations:")
+				for _, l := range got {
+					t.Errorf("  %+v", *l)
+				}
+				t.Errorf("want:")
+				for _, l := range c.want {
+					t.Errorf("  %+v", *l)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidMaps(t *testing.T) {
+	roomsOnly := &Options{Layers: []string{"rooms"}, Blocked: []string{"walls"}}
+
+	cases := []struct {
+		name   string
+		format string
+		input  string
+		opts   *Options
+		want   string
+	}{
+		{"unknown format", "tsx", tmxDoc(""), roomsOnly, "unknown Tiled map format"},
+		{"invalid tmx", FormatTMX, "<map", roomsOnly, "invalid Tiled TMX map"},
+		{"invalid json", FormatJSON, "{", roomsOnly, "invalid Tiled JSON map"},
+		{"no tile size", FormatJSON, `{"layers": [{"type": "objectgroup", "objects": [{"name": "a"}]}]}`, &Options{}, "no tile size"},
+		{"no named objects", FormatTMX, tmxDoc(`<data encoding="csv">` + csv(walls) + `</data>`), &Options{Layers: []string{"walls"}}, "no named objects found in object layers"},
+		{"missing blocked layer", FormatTMX, tmxDoc(`<data encoding="csv">` + csv(walls) + `</data>`), &Options{Blocked: []string{"water"}}, "no tile layer named `water`"},
+		{"blocked pixels", FormatTMX, tmxDoc(`<data encoding="csv">` + csv(walls) + `</data>`), &Options{Blocked: []string{"walls"}, Pixels: true}, "pixel coordinates"},
+		{"too few tiles", FormatTMX, tmxDoc(`<data encoding="csv">` + csv(walls[1:]) + `</data>`), roomsOnly, "has 11 tiles, expected 4x3"},
+		{"invalid csv tile", FormatTMX, tmxDoc(`<data encoding="csv">0,x,0</data>`), roomsOnly, "invalid tile \"x\""},
+		{"compressed csv", FormatTMX, tmxDoc(`<data encoding="csv" compression="zlib">0</data>`), roomsOnly, "compressed CSV"},
+		{"invalid base64", FormatTMX, tmxDoc(`<data encoding="base64">!!!</data>`), roomsOnly, "invalid base64 data"},
+		{"invalid zlib", FormatTMX, tmxDoc(`<data encoding="base64" compression="zlib">` + encode(t, walls, "") + `</data>`), roomsOnly, "invalid zlib data"},
+		{"zstd", FormatTMX, tmxDoc(`<data encoding="base64" compression="zstd">` + encode(t, walls, "") + `</data>`), roomsOnly, "unsupported compression \"zstd\""},
+		{"partial tile", FormatTMX, tmxDoc(`<data encoding="base64">` + base64.StdEncoding.EncodeToString([]byte{1, 2, 3}) + `</data>`), roomsOnly, "not a whole number of tiles"},
+		{"unknown encoding", FormatJSON, jsonDoc(`"encoding": "hex", "data": "00"`), roomsOnly, "unknown encoding \"hex\""},
+		{"invalid json data", FormatJSON, jsonDoc(`"data": "AAAA"`), roomsOnly, "invalid data in tile layer `walls`"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m, err := Decode(strings.NewReader(c.input), c.format)
+			if err == nil {
+				_, err = m.Locations(c.opts)
+			}
+
+			if err == nil {
+				t.Fatalf("expected an error containing %q", c.want)
+			}
+
+			if !strings.Contains(err.Error(), c.want) {
+				t.Errorf("got error %q, want one containing %q", err.Error(), c.want)
+			}
+		})
+	}
+}